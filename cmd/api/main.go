@@ -4,11 +4,17 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/gbart/fcabl-api/internal/auth"
+	"github.com/gbart/fcabl-api/internal/checkout"
 	"github.com/gbart/fcabl-api/internal/config"
 	"github.com/gbart/fcabl-api/internal/db"
 	"github.com/gbart/fcabl-api/internal/handlers"
+	"github.com/gbart/fcabl-api/internal/jobs"
+	"github.com/gbart/fcabl-api/internal/live"
+	"github.com/gbart/fcabl-api/internal/mailer"
+	"github.com/gbart/fcabl-api/internal/repository"
 	"github.com/gbart/fcabl-api/router"
 )
 
@@ -42,13 +48,46 @@ func main() {
 	fmt.Println("Database connection established")
 
 	// Initialize JWT service
-	jwtService := auth.NewJWTService(cfg.JWTSecret, cfg.JWTExpirationHours)
+	jwtService := auth.NewJWTService(cfg.JWTSecret, cfg.AccessTokenExpirationMin)
+
+	// Initialize mailer
+	mail, err := mailer.New(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize mailer: %v", err)
+	}
+
+	// Initialize payment provider
+	payments, err := checkout.New(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize payment provider: %v", err)
+	}
+
+	// Start streaming live game events to fans
+	hub := live.NewHub(pg.DB)
+	go hub.Run(context.Background())
 
 	// Initialize handlers
-	handler := handlers.NewHandler(pg, jwtService, cfg)
+	handler := handlers.NewHandler(pg, jwtService, cfg, mail, payments, hub)
+
+	queries := repository.New(pg.DB)
+
+	// Start background jobs
+	if cfg.JobsEnabled {
+		jobs.Start(context.Background(),
+			jobs.MarkOverdueInstallments(queries, time.Duration(cfg.PaymentPlanJobIntervalMin)*time.Minute),
+			jobs.Exclusive(pg.DB, jobs.SendPaymentReminders(queries, mail, jobs.DunningOptions{
+				ReminderDays: cfg.DunningReminderDays,
+				LockoutDays:  cfg.DunningLockoutDays,
+				FrontendURL:  cfg.FrontendURL,
+			}, time.Duration(cfg.DunningJobIntervalMin)*time.Minute)),
+			jobs.PurgeIdempotencyKeys(queries, time.Hour),
+			jobs.PurgeExpiredSessions(queries, time.Hour),
+			jobs.PurgeGameStreamEvents(queries, time.Hour),
+		)
+	}
 
 	// Setup router
-	r := router.SetupRouter(handler, cfg.FrontendURL, jwtService)
+	r := router.SetupRouter(handler, cfg.FrontendURL, jwtService, queries, queries, time.Duration(cfg.IdempotencyKeyTTLHours)*time.Hour)
 
 	// Start server
 	addr := fmt.Sprintf(":%s", cfg.Port)
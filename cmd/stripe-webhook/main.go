@@ -0,0 +1,60 @@
+// Command stripe-webhook signs a Stripe event fixture with a webhook secret
+// and posts it to the API, to exercise the webhook endpoint locally without
+// Stripe. Fixtures live in internal/stripe/testdata and refer to payments in
+// seed_data.sql, except checkout_session_completed.json which needs the id of
+// a session created with the fake payment provider.
+//
+//	go run ./cmd/stripe-webhook -secret whsec_test internal/stripe/testdata/payment_intent_succeeded.json
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gbart/fcabl-api/internal/stripe"
+)
+
+func main() {
+	secret := flag.String("secret", os.Getenv("STRIPE_WEBHOOK_SECRET"), "webhook signing secret, defaults to STRIPE_WEBHOOK_SECRET")
+	url := flag.String("url", "http://localhost:8080/api/webhook/stripe", "webhook endpoint to post to")
+	printOnly := flag.Bool("print", false, "only print the Stripe-Signature header")
+	flag.Parse()
+
+	if flag.NArg() != 1 || *secret == "" {
+		fmt.Fprintln(os.Stderr, "usage: stripe-webhook -secret SECRET [-url URL] [-print] FIXTURE")
+		os.Exit(2)
+	}
+
+	payload, err := os.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	header := stripe.SignatureHeader(payload, *secret, time.Now())
+	if *printOnly {
+		fmt.Println(header)
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, *url, bytes.NewReader(payload))
+	if err != nil {
+		log.Fatalf("Failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Stripe-Signature", header)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Fatalf("Failed to post webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	fmt.Printf("%s\n%s\n", resp.Status, body)
+}
@@ -9,32 +9,37 @@ import (
 
 // Claims structure for JWT tokens
 type Claims struct {
-	UserID int64  `json:"userId"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID    int64  `json:"userId"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID int64  `json:"sessionId"`
 	jwt.RegisteredClaims
 }
 
 type JWTService struct {
-	secret          []byte
-	expirationHours int
+	secret            []byte
+	expirationMinutes int
 }
 
-func NewJWTService(secret string, expirationHours int) *JWTService {
+// NewJWTService creates a JWTService issuing access tokens that expire after
+// the given number of minutes. Tokens are kept short lived because they are
+// renewed through the session's refresh token.
+func NewJWTService(secret string, expirationMinutes int) *JWTService {
 	return &JWTService{
-		secret:          []byte(secret),
-		expirationHours: expirationHours,
+		secret:            []byte(secret),
+		expirationMinutes: expirationMinutes,
 	}
 }
 
-// GenerateToken creates a new JWT token for a user
-func (s *JWTService) GenerateToken(userID int64, email, role string) (string, error) {
+// GenerateToken creates a new JWT access token for a user's session
+func (s *JWTService) GenerateToken(userID int64, email, role string, sessionID int64) (string, error) {
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * time.Duration(s.expirationHours))),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * time.Duration(s.expirationMinutes))),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
@@ -0,0 +1,93 @@
+package auth
+
+// Permission is an action a role may be allowed to perform
+type Permission string
+
+const (
+	PermManageUsers    Permission = "users:manage"
+	PermManageLeague   Permission = "league:manage"
+	PermViewPlayers    Permission = "players:view"
+	PermManagePlayers  Permission = "players:manage"
+	PermManageRoster   Permission = "roster:manage"
+	PermRecordBoxScore Permission = "boxscore:record"
+	PermRecordResults  Permission = "results:record"
+	PermManagePayments Permission = "payments:manage"
+)
+
+// Scope limits which records a permission applies to
+type Scope int
+
+const (
+	// ScopeNone means the permission is not granted
+	ScopeNone Scope = iota
+	// ScopeAll grants the permission for every record
+	ScopeAll
+	// ScopeOwnTeam grants the permission for the teams the user captains
+	ScopeOwnTeam
+	// ScopeAssignedGames grants the permission for the games the user is assigned to
+	ScopeAssignedGames
+)
+
+const (
+	RoleNormal      = "normal"
+	RoleAdmin       = "admin"
+	RoleCaptain     = "captain"
+	RoleScorekeeper = "scorekeeper"
+	RoleTreasurer   = "treasurer"
+	RoleReferee     = "referee"
+)
+
+var allPermissions = []Permission{
+	PermManageUsers,
+	PermManageLeague,
+	PermViewPlayers,
+	PermManagePlayers,
+	PermManageRoster,
+	PermRecordBoxScore,
+	PermRecordResults,
+	PermManagePayments,
+}
+
+// rolePermissions maps each role to the permissions it grants. Admins are
+// granted everything and normal users nothing beyond being signed in.
+var rolePermissions = map[string]map[Permission]Scope{
+	RoleCaptain: {
+		PermManageRoster: ScopeOwnTeam,
+	},
+	RoleScorekeeper: {
+		PermRecordBoxScore: ScopeAssignedGames,
+		PermRecordResults:  ScopeAssignedGames,
+	},
+	RoleReferee: {
+		PermRecordResults: ScopeAssignedGames,
+	},
+	RoleTreasurer: {
+		PermViewPlayers:    ScopeAll,
+		PermManagePayments: ScopeAll,
+	},
+}
+
+func init() {
+	admin := make(map[Permission]Scope, len(allPermissions))
+	for _, p := range allPermissions {
+		admin[p] = ScopeAll
+	}
+	rolePermissions[RoleAdmin] = admin
+}
+
+// PermissionScope returns the scope the role is granted the permission in,
+// or ScopeNone if the role does not have it
+func PermissionScope(role string, permission Permission) Scope {
+	return rolePermissions[role][permission]
+}
+
+// RolePermissions returns the permissions granted to a role
+func RolePermissions(role string) []Permission {
+	permissions := make([]Permission, 0, len(rolePermissions[role]))
+	for _, p := range allPermissions {
+		if _, ok := rolePermissions[role][p]; ok {
+			permissions = append(permissions, p)
+		}
+	}
+	return permissions
+}
@@ -0,0 +1,111 @@
+// Package bracket lays out single elimination playoff brackets. It works only
+// with seeds, callers map seeds to teams and persist the matches.
+package bracket
+
+import "errors"
+
+// Slot is the side of a match a team is placed in.
+type Slot string
+
+const (
+	Home Slot = "home"
+	Away Slot = "away"
+)
+
+// Match is one match in the bracket. Seeds are 0 when the slot will be filled
+// by the winner of an earlier match. NextPosition is 0 for the final.
+type Match struct {
+	Round        int
+	Position     int
+	HomeSeed     int
+	AwaySeed     int
+	NextPosition int
+	NextSlot     Slot
+}
+
+var ErrTooFewTeams = errors.New("a bracket needs at least two teams")
+
+// SingleElimination returns the matches for a single elimination bracket of
+// the given number of teams, ordered by round and position. Seeds are paired
+// so the best seed meets the worst seed, and the top two seeds can only meet
+// in the final.
+//
+// When the number of teams is not a power of two, the top seeds get byes:
+// their first round match is not created and they are placed straight into
+// the second round.
+func SingleElimination(teams int) ([]Match, error) {
+	if teams < 2 {
+		return nil, ErrTooFewTeams
+	}
+
+	size := 1
+	rounds := 0
+	for size < teams {
+		size *= 2
+		rounds++
+	}
+
+	// Lay out every match of a full bracket, then drop the first round matches
+	// that would be against a missing seed.
+	byRound := make([][]Match, rounds+1)
+	for r := 1; r <= rounds; r++ {
+		count := size >> r
+		byRound[r] = make([]Match, count)
+		for p := 1; p <= count; p++ {
+			m := Match{Round: r, Position: p}
+			if r < rounds {
+				m.NextPosition = (p + 1) / 2
+				m.NextSlot = Home
+				if p%2 == 0 {
+					m.NextSlot = Away
+				}
+			}
+			byRound[r][p-1] = m
+		}
+	}
+
+	order := seedOrder(size)
+	for i := range byRound[1] {
+		home, away := order[2*i], order[2*i+1]
+		byRound[1][i].HomeSeed = home
+		byRound[1][i].AwaySeed = away
+	}
+
+	matches := make([]Match, 0, teams-1)
+	for _, m := range byRound[1] {
+		if m.AwaySeed <= teams {
+			matches = append(matches, m)
+			continue
+		}
+
+		// Bye: the home seed goes straight to the next round. With only two
+		// teams there are no byes, so a next round always exists here.
+		next := &byRound[2][m.NextPosition-1]
+		if m.NextSlot == Home {
+			next.HomeSeed = m.HomeSeed
+		} else {
+			next.AwaySeed = m.HomeSeed
+		}
+	}
+	for r := 2; r <= rounds; r++ {
+		matches = append(matches, byRound[r]...)
+	}
+
+	return matches, nil
+}
+
+// seedOrder returns the seeds of a full bracket of the given size in the
+// order they appear from top to bottom, e.g. 1 8 4 5 2 7 3 6 for eight teams.
+// Adjacent pairs play each other in the first round.
+func seedOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		n := len(order) * 2
+		next := make([]int, 0, n)
+		for _, seed := range order {
+			next = append(next, seed, n+1-seed)
+		}
+		order = next
+	}
+	return order
+}
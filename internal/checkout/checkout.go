@@ -0,0 +1,107 @@
+// Package checkout creates hosted checkout sessions with a payment provider.
+// The player is sent to the session's URL to pay, and the provider reports the
+// outcome through webhooks. Payments taken this way are refunded through the
+// same provider, and listed from it to reconcile against the payments table.
+package checkout
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gbart/fcabl-api/internal/config"
+)
+
+// Request describes what a checkout session charges for
+type Request struct {
+	AmountCents   int64
+	Currency      string
+	Description   string
+	CustomerEmail string
+	SuccessURL    string
+	CancelURL     string
+	// Reference identifies the payer on our side, e.g. the player id
+	Reference string
+	// IdempotencyKey makes retried requests return the same session
+	IdempotencyKey string
+}
+
+// Session is a checkout session created by a provider. ID is the provider's
+// id for the session, payments are recorded under it.
+type Session struct {
+	ID        string
+	URL       string
+	ExpiresAt time.Time
+}
+
+// RefundRequest describes a full or partial refund of a payment
+type RefundRequest struct {
+	// PaymentID is the provider's id for the payment, e.g. a payment intent
+	PaymentID   string
+	AmountCents int64
+	Reason      string
+	// Reference identifies the refund on our side, e.g. the refund id
+	Reference      string
+	IdempotencyKey string
+}
+
+// Refund statuses, providers' own statuses are mapped onto these
+const (
+	RefundPending   = "pending"
+	RefundSucceeded = "succeeded"
+	RefundFailed    = "failed"
+)
+
+// Refund is a refund accepted by a provider. ID is the provider's id for it.
+// A pending refund is completed or failed later through the provider's webhook.
+type Refund struct {
+	ID     string
+	Status string
+}
+
+// Payment statuses, providers' own statuses are mapped onto these. They are
+// the same as the statuses of the payments table.
+const (
+	PaymentPending   = "pending"
+	PaymentCompleted = "completed"
+	PaymentFailed    = "failed"
+	PaymentRefunded  = "refunded"
+)
+
+// Payment is a payment as the provider has it, from its API or an export
+type Payment struct {
+	// ID is the provider's id for the payment, matched against stripe_id
+	ID              string
+	PaymentIntentID string
+	AmountCents     int64
+	Status          string
+	// Reference is the reference the checkout session was created with, if any
+	Reference string
+	Created   time.Time
+}
+
+// Provider creates checkout sessions and refunds the payments made with them
+type Provider interface {
+	Name() string
+	CreateSession(ctx context.Context, req Request) (Session, error)
+	// ExpireSession expires an open checkout session so it can no longer be paid
+	ExpireSession(ctx context.Context, id string) error
+	Refund(ctx context.Context, req RefundRequest) (Refund, error)
+	// ListPayments lists the payments created from from up to but not including to
+	ListPayments(ctx context.Context, from, to time.Time) ([]Payment, error)
+}
+
+// New returns the Provider for the configured PAYMENT_PROVIDER
+func New(cfg *config.Config) (Provider, error) {
+	switch cfg.PaymentProvider {
+	case "stripe":
+		if cfg.StripeSecretKey == "" {
+			return nil, fmt.Errorf("STRIPE_SECRET_KEY is required for the stripe payment provider")
+		}
+		return NewStripeProvider(cfg.StripeSecretKey), nil
+	case "fake":
+		return NewFakeProvider(cfg.FrontendURL), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", cfg.PaymentProvider)
+	}
+}
@@ -0,0 +1,226 @@
+package checkout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const stripeAPIURL = "https://api.stripe.com/v1"
+
+// StripeProvider creates Stripe Checkout sessions through the Stripe API
+type StripeProvider struct {
+	secretKey string
+	client    *http.Client
+}
+
+// NewStripeProvider creates a StripeProvider using the secret API key
+func NewStripeProvider(secretKey string) *StripeProvider {
+	return &StripeProvider{
+		secretKey: secretKey,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *StripeProvider) Name() string {
+	return "stripe"
+}
+
+// CreateSession creates a Checkout session in payment mode with a single line
+// item for the amount. The reference is set as the client_reference_id and
+// in the metadata of the session and its payment intent.
+func (p *StripeProvider) CreateSession(ctx context.Context, req Request) (Session, error) {
+	form := url.Values{}
+	form.Set("mode", "payment")
+	form.Set("success_url", req.SuccessURL)
+	form.Set("cancel_url", req.CancelURL)
+	form.Set("client_reference_id", req.Reference)
+	form.Set("metadata[reference]", req.Reference)
+	form.Set("payment_intent_data[metadata][reference]", req.Reference)
+	form.Set("line_items[0][quantity]", "1")
+	form.Set("line_items[0][price_data][currency]", req.Currency)
+	form.Set("line_items[0][price_data][unit_amount]", strconv.FormatInt(req.AmountCents, 10))
+	form.Set("line_items[0][price_data][product_data][name]", req.Description)
+	if req.CustomerEmail != "" {
+		form.Set("customer_email", req.CustomerEmail)
+	}
+
+	var body struct {
+		ID        string `json:"id"`
+		URL       string `json:"url"`
+		ExpiresAt int64  `json:"expires_at"`
+	}
+	if err := p.do(ctx, http.MethodPost, "/checkout/sessions", form, req.IdempotencyKey, &body); err != nil {
+		return Session{}, err
+	}
+
+	return Session{
+		ID:        body.ID,
+		URL:       body.URL,
+		ExpiresAt: time.Unix(body.ExpiresAt, 0),
+	}, nil
+}
+
+// ExpireSession expires an open Checkout session. Stripe refuses sessions
+// that are already complete or expired.
+func (p *StripeProvider) ExpireSession(ctx context.Context, id string) error {
+	var body struct {
+		ID string `json:"id"`
+	}
+	return p.do(ctx, http.MethodPost, "/checkout/sessions/"+url.PathEscape(id)+"/expire", url.Values{}, "", &body)
+}
+
+// Refund refunds the amount of a payment intent. Stripe only accepts a few
+// fixed reasons, so the reason and reference are kept in the metadata, where
+// they come back in the refund's webhook events.
+func (p *StripeProvider) Refund(ctx context.Context, req RefundRequest) (Refund, error) {
+	form := url.Values{}
+	form.Set("payment_intent", req.PaymentID)
+	form.Set("amount", strconv.FormatInt(req.AmountCents, 10))
+	form.Set("metadata[reference]", req.Reference)
+	form.Set("metadata[reason]", req.Reason)
+
+	var body struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := p.do(ctx, http.MethodPost, "/refunds", form, req.IdempotencyKey, &body); err != nil {
+		return Refund{}, err
+	}
+
+	return Refund{
+		ID:     body.ID,
+		Status: StripeRefundStatus(body.Status),
+	}, nil
+}
+
+// StripeRefundStatus maps the status of a Stripe refund onto ours. Refunds
+// waiting on the customer are still pending, canceled ones have failed.
+func StripeRefundStatus(status string) string {
+	switch status {
+	case "succeeded":
+		return RefundSucceeded
+	case "failed", "canceled":
+		return RefundFailed
+	default:
+		return RefundPending
+	}
+}
+
+// ListPayments lists the Checkout sessions created in the period, which
+// payments are recorded under. Sessions are paged through 100 at a time.
+func (p *StripeProvider) ListPayments(ctx context.Context, from, to time.Time) ([]Payment, error) {
+	var payments []Payment
+	startingAfter := ""
+	for {
+		query := url.Values{}
+		query.Set("limit", "100")
+		query.Set("created[gte]", strconv.FormatInt(from.Unix(), 10))
+		query.Set("created[lt]", strconv.FormatInt(to.Unix(), 10))
+		if startingAfter != "" {
+			query.Set("starting_after", startingAfter)
+		}
+
+		var body struct {
+			Data []struct {
+				ID            string            `json:"id"`
+				AmountTotal   int64             `json:"amount_total"`
+				Status        string            `json:"status"`
+				PaymentStatus string            `json:"payment_status"`
+				PaymentIntent string            `json:"payment_intent"`
+				Metadata      map[string]string `json:"metadata"`
+				Created       int64             `json:"created"`
+			} `json:"data"`
+			HasMore bool `json:"has_more"`
+		}
+		if err := p.do(ctx, http.MethodGet, "/checkout/sessions", query, "", &body); err != nil {
+			return nil, err
+		}
+
+		for _, session := range body.Data {
+			payments = append(payments, Payment{
+				ID:              session.ID,
+				PaymentIntentID: session.PaymentIntent,
+				AmountCents:     session.AmountTotal,
+				Status:          stripeSessionStatus(session.Status, session.PaymentStatus),
+				Reference:       session.Metadata["reference"],
+				Created:         time.Unix(session.Created, 0).UTC(),
+			})
+		}
+		if !body.HasMore || len(body.Data) == 0 {
+			return payments, nil
+		}
+		startingAfter = body.Data[len(body.Data)-1].ID
+	}
+}
+
+// stripeSessionStatus maps a Checkout session's status onto a payment status.
+// Expired sessions were never paid, refunds are not shown on the session.
+func stripeSessionStatus(status, paymentStatus string) string {
+	switch {
+	case status == "complete" && paymentStatus != "unpaid":
+		return PaymentCompleted
+	case status == "expired":
+		return PaymentFailed
+	default:
+		return PaymentPending
+	}
+}
+
+// do sends a request to the Stripe API and decodes the response into out. The
+// form is sent as the body of POST requests and as the query of GET requests.
+// Error responses are returned as errors with Stripe's message.
+func (p *StripeProvider) do(ctx context.Context, method, path string, form url.Values, idempotencyKey string, out any) error {
+	var body io.Reader
+	endpoint := stripeAPIURL + path
+	if method == http.MethodGet {
+		endpoint += "?" + form.Encode()
+	} else {
+		body = strings.NewReader(form.Encode())
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	httpReq.SetBasicAuth(p.secretKey, "")
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		httpReq.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("calling stripe: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading stripe response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Error *struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(raw, &body) == nil && body.Error != nil {
+			return fmt.Errorf("stripe returned %s: %s: %s", resp.Status, body.Error.Type, body.Error.Message)
+		}
+		return fmt.Errorf("stripe returned %s", resp.Status)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("decoding stripe response: %w", err)
+	}
+	return nil
+}
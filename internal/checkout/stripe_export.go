@@ -0,0 +1,111 @@
+package checkout
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gbart/fcabl-api/internal/money"
+)
+
+// Columns of the Stripe dashboard's payments export that are read. Header
+// names are matched case insensitively, the others are ignored.
+const (
+	stripeExportID            = "id"
+	stripeExportPaymentIntent = "paymentintent id"
+	stripeExportAmount        = "amount"
+	stripeExportStatus        = "status"
+	stripeExportCreated       = "created (utc)"
+	stripeExportCreatedDate   = "created date (utc)"
+	stripeExportReference     = "reference (metadata)"
+)
+
+// ParseStripeExport reads the payments of a CSV export from the Stripe
+// dashboard. Exports list charges, which are matched to our payments by their
+// payment intent. The id, Amount, Status and Created (UTC) columns are required.
+func ParseStripeExport(r io.Reader) ([]Payment, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("export is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading export header: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns[stripeExportCreated]; !ok {
+		if i, ok := columns[stripeExportCreatedDate]; ok {
+			columns[stripeExportCreated] = i
+		}
+	}
+	for _, required := range []string{stripeExportID, stripeExportAmount, stripeExportStatus, stripeExportCreated} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("export is missing the %q column", required)
+		}
+	}
+
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var payments []Payment
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return payments, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading export line %d: %w", line, err)
+		}
+
+		id := field(record, stripeExportID)
+		if id == "" {
+			continue
+		}
+		amount, err := money.Parse(field(record, stripeExportAmount))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		created, err := time.Parse(time.DateTime, field(record, stripeExportCreated))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid created time %q", line, field(record, stripeExportCreated))
+		}
+
+		payments = append(payments, Payment{
+			ID:              id,
+			PaymentIntentID: field(record, stripeExportPaymentIntent),
+			AmountCents:     amount,
+			Status:          stripeChargeStatus(field(record, stripeExportStatus)),
+			Reference:       field(record, stripeExportReference),
+			Created:         created,
+		})
+	}
+}
+
+// stripeChargeStatus maps the status column of an export onto a payment
+// status. Partially refunded charges are still Paid.
+func stripeChargeStatus(status string) string {
+	switch strings.ToLower(status) {
+	case "paid", "succeeded":
+		return PaymentCompleted
+	case "refunded":
+		return PaymentRefunded
+	case "failed", "canceled":
+		return PaymentFailed
+	default:
+		return PaymentPending
+	}
+}
@@ -4,21 +4,50 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
-	DatabaseURL             string
-	JWTSecret               string
-	JWTExpirationHours      int
-	FrontendURL             string
-	ResetTokenExpirationMin int
-	Port                    string
+	DatabaseURL                 string
+	JWTSecret                   string
+	AccessTokenExpirationMin    int
+	RefreshTokenExpirationHours int
+	FrontendURL                 string
+	ResetTokenExpirationMin     int
+	Port                        string
+	MailDriver                  string
+	MailFrom                    string
+	MailDir                     string
+	SMTPHost                    string
+	SMTPPort                    int
+	SMTPUsername                string
+	SMTPPassword                string
+	StripeWebhookSecret         string
+	StripeSecretKey             string
+	PaymentProvider             string
+	CheckoutCurrency            string
+	JobsEnabled                 bool
+	PaymentPlanJobIntervalMin   int
+	DunningJobIntervalMin       int
+	DunningReminderDays         []int
+	DunningLockoutDays          int
+	LeagueName                  string
+	LeagueAddress               string
+	LeagueEmail                 string
+	QuickBooksBankAccount       string
+	QuickBooksIncomeAccount     string
+	IdempotencyKeyTTLHours      int
 }
 
 func Load() (*Config, error) {
-	jwtExpHours, err := strconv.Atoi(getEnv("JWT_EXPIRATION_HOURS", "24"))
+	accessTokenExpMin, err := strconv.Atoi(getEnv("ACCESS_TOKEN_EXPIRATION_MINUTES", "15"))
 	if err != nil {
-		return nil, fmt.Errorf("invalid JWT_EXPIRATION_HOURS: %v", err)
+		return nil, fmt.Errorf("invalid ACCESS_TOKEN_EXPIRATION_MINUTES: %v", err)
+	}
+
+	refreshTokenExpHours, err := strconv.Atoi(getEnv("REFRESH_TOKEN_EXPIRATION_HOURS", "720"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REFRESH_TOKEN_EXPIRATION_HOURS: %v", err)
 	}
 
 	resetTokenExpMin, err := strconv.Atoi(getEnv("RESET_TOKEN_EXPIRATION_MINUTES", "30"))
@@ -26,16 +55,98 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid RESET_TOKEN_EXPIRATION_MINUTES: %v", err)
 	}
 
+	smtpPort, err := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_PORT: %v", err)
+	}
+
+	jobsEnabled, err := strconv.ParseBool(getEnv("JOBS_ENABLED", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOBS_ENABLED: %v", err)
+	}
+
+	paymentPlanJobIntervalMin, err := strconv.Atoi(getEnv("PAYMENT_PLAN_JOB_INTERVAL_MINUTES", "60"))
+	if err != nil || paymentPlanJobIntervalMin <= 0 {
+		return nil, fmt.Errorf("invalid PAYMENT_PLAN_JOB_INTERVAL_MINUTES: %v", err)
+	}
+
+	dunningJobIntervalMin, err := strconv.Atoi(getEnv("DUNNING_JOB_INTERVAL_MINUTES", "60"))
+	if err != nil || dunningJobIntervalMin <= 0 {
+		return nil, fmt.Errorf("invalid DUNNING_JOB_INTERVAL_MINUTES: %v", err)
+	}
+
+	dunningReminderDays, err := parseDays(getEnv("DUNNING_REMINDER_DAYS", "7,14,28"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DUNNING_REMINDER_DAYS: %v", err)
+	}
+
+	dunningLockoutDays, err := strconv.Atoi(getEnv("DUNNING_LOCKOUT_DAYS", "0"))
+	if err != nil || dunningLockoutDays < 0 {
+		return nil, fmt.Errorf("invalid DUNNING_LOCKOUT_DAYS: %v", err)
+	}
+
+	idempotencyKeyTTLHours, err := strconv.Atoi(getEnv("IDEMPOTENCY_KEY_TTL_HOURS", "24"))
+	if err != nil || idempotencyKeyTTLHours <= 0 {
+		return nil, fmt.Errorf("invalid IDEMPOTENCY_KEY_TTL_HOURS: %v", err)
+	}
+
 	return &Config{
-		DatabaseURL:             getEnv("DATABASE_URL", ""),
-		JWTSecret:               getEnv("JWT_SECRET", ""),
-		JWTExpirationHours:      jwtExpHours,
-		FrontendURL:             getEnv("FRONTEND_URL", "http://localhost:5173"),
-		ResetTokenExpirationMin: resetTokenExpMin,
-		Port:                    getEnv("PORT", "8080"),
+		DatabaseURL:                 getEnv("DATABASE_URL", ""),
+		JWTSecret:                   getEnv("JWT_SECRET", ""),
+		AccessTokenExpirationMin:    accessTokenExpMin,
+		RefreshTokenExpirationHours: refreshTokenExpHours,
+		FrontendURL:                 getEnv("FRONTEND_URL", "http://localhost:5173"),
+		ResetTokenExpirationMin:     resetTokenExpMin,
+		Port:                        getEnv("PORT", "8080"),
+		MailDriver:                  getEnv("MAIL_DRIVER", "log"),
+		MailFrom:                    getEnv("MAIL_FROM", "FCABL <no-reply@fcabl.com>"),
+		MailDir:                     getEnv("MAIL_DIR", "tmp/mail"),
+		SMTPHost:                    getEnv("SMTP_HOST", ""),
+		SMTPPort:                    smtpPort,
+		SMTPUsername:                getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:                getEnv("SMTP_PASSWORD", ""),
+		StripeWebhookSecret:         getEnv("STRIPE_WEBHOOK_SECRET", ""),
+		StripeSecretKey:             getEnv("STRIPE_SECRET_KEY", ""),
+		PaymentProvider:             getEnv("PAYMENT_PROVIDER", "fake"),
+		CheckoutCurrency:            getEnv("CHECKOUT_CURRENCY", "usd"),
+		JobsEnabled:                 jobsEnabled,
+		PaymentPlanJobIntervalMin:   paymentPlanJobIntervalMin,
+		DunningJobIntervalMin:       dunningJobIntervalMin,
+		DunningReminderDays:         dunningReminderDays,
+		DunningLockoutDays:          dunningLockoutDays,
+		LeagueName:                  getEnv("LEAGUE_NAME", "FCABL"),
+		LeagueAddress:               getEnv("LEAGUE_ADDRESS", ""),
+		LeagueEmail:                 getEnv("LEAGUE_EMAIL", ""),
+		QuickBooksBankAccount:       getEnv("QUICKBOOKS_BANK_ACCOUNT", "Undeposited Funds"),
+		QuickBooksIncomeAccount:     getEnv("QUICKBOOKS_INCOME_ACCOUNT", "Registration Fees"),
+		IdempotencyKeyTTLHours:      idempotencyKeyTTLHours,
 	}, nil
 }
 
+// parseDays parses a comma separated list of increasing day counts, e.g.
+// 7,14,28, or off for none
+func parseDays(value string) ([]int, error) {
+	var days []int
+	if value == "off" {
+		return days, nil
+	}
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		day, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+		if day < 0 || (len(days) > 0 && day <= days[len(days)-1]) {
+			return nil, fmt.Errorf("days must be increasing and not negative")
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
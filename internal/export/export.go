@@ -0,0 +1,237 @@
+// Package export writes payments, refunds and balances in formats the
+// treasurer can open in a spreadsheet or import into accounting software: CSV,
+// and QuickBooks Desktop's IIF. Amounts are written from cents, so they are
+// exact to the cent.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gbart/fcabl-api/internal/money"
+)
+
+// Payment is a payment received from a player
+type Payment struct {
+	ID            int64
+	Date          time.Time
+	PlayerID      int64
+	FirstName     string
+	LastName      string
+	Email         string
+	Season        string
+	Provider      string
+	Reference     string
+	PaymentIntent string
+	Status        string
+	AmountCents   int64
+	RefundedCents int64
+}
+
+// Refund is money returned to a player
+type Refund struct {
+	ID               int64
+	Date             time.Time
+	PaymentID        int64
+	PlayerID         int64
+	FirstName        string
+	LastName         string
+	Email            string
+	Season           string
+	PaymentReference string
+	Reference        string
+	Reason           string
+	AmountCents      int64
+}
+
+// Balance is what a player owes, negative when they are in credit
+type Balance struct {
+	PlayerID     int64
+	FirstName    string
+	LastName     string
+	BalanceCents int64
+}
+
+// Accounts are the QuickBooks accounts transactions are posted to
+type Accounts struct {
+	// Bank receives payments and pays refunds, e.g. Undeposited Funds
+	Bank string
+	// Income is credited with payments and debited with refunds
+	Income string
+}
+
+// WritePaymentsCSV writes payments as CSV with a header row
+func WritePaymentsCSV(w io.Writer, payments []Payment) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"Payment ID", "Date", "Player ID", "First Name", "Last Name", "Email", "Season",
+		"Provider", "Reference", "Payment Intent", "Status", "Amount", "Refunded", "Net",
+	})
+	for _, p := range payments {
+		cw.Write([]string{
+			id(p.ID),
+			p.Date.Format(time.DateOnly),
+			id(p.PlayerID),
+			text(p.FirstName),
+			text(p.LastName),
+			text(p.Email),
+			text(p.Season),
+			text(p.Provider),
+			text(p.Reference),
+			text(p.PaymentIntent),
+			p.Status,
+			money.Decimal(p.AmountCents),
+			money.Decimal(p.RefundedCents),
+			money.Decimal(p.AmountCents - p.RefundedCents),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteRefundsCSV writes refunds as CSV with a header row
+func WriteRefundsCSV(w io.Writer, refunds []Refund) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"Refund ID", "Date", "Payment ID", "Player ID", "First Name", "Last Name", "Email", "Season",
+		"Payment Reference", "Reference", "Reason", "Amount",
+	})
+	for _, r := range refunds {
+		cw.Write([]string{
+			id(r.ID),
+			r.Date.Format(time.DateOnly),
+			id(r.PaymentID),
+			id(r.PlayerID),
+			text(r.FirstName),
+			text(r.LastName),
+			text(r.Email),
+			text(r.Season),
+			text(r.PaymentReference),
+			text(r.Reference),
+			text(r.Reason),
+			money.Decimal(r.AmountCents),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteBalancesCSV writes player balances as CSV with a header row
+func WriteBalancesCSV(w io.Writer, balances []Balance) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"Player ID", "First Name", "Last Name", "Balance"})
+	for _, b := range balances {
+		cw.Write([]string{
+			id(b.PlayerID),
+			text(b.FirstName),
+			text(b.LastName),
+			money.Decimal(b.BalanceCents),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// iifTransaction is a QuickBooks transaction of two lines, the first posted to
+// the bank account and the second to the income account
+type iifTransaction struct {
+	kind   string
+	date   time.Time
+	docNum string
+	memo   string
+	// amount is what the bank account receives, negative when it pays out
+	amount int64
+}
+
+// WritePaymentsIIF writes payments as QuickBooks deposits into the bank
+// account. Refunded amounts are left to the refunds export.
+func WritePaymentsIIF(w io.Writer, payments []Payment, accounts Accounts) error {
+	transactions := make([]iifTransaction, 0, len(payments))
+	for _, p := range payments {
+		transactions = append(transactions, iifTransaction{
+			kind:   "DEPOSIT",
+			date:   p.Date,
+			docNum: p.Reference,
+			memo:   fmt.Sprintf("Registration fee, %s %s, %s", p.FirstName, p.LastName, p.Season),
+			amount: p.AmountCents,
+		})
+	}
+	return writeIIF(w, transactions, accounts)
+}
+
+// WriteRefundsIIF writes refunds as QuickBooks checks paid from the bank
+// account
+func WriteRefundsIIF(w io.Writer, refunds []Refund, accounts Accounts) error {
+	transactions := make([]iifTransaction, 0, len(refunds))
+	for _, r := range refunds {
+		memo := fmt.Sprintf("Registration fee refund, %s %s, %s", r.FirstName, r.LastName, r.Season)
+		if r.Reason != "" {
+			memo += ": " + r.Reason
+		}
+		transactions = append(transactions, iifTransaction{
+			kind:   "CHECK",
+			date:   r.Date,
+			docNum: r.PaymentReference,
+			memo:   memo,
+			amount: -r.AmountCents,
+		})
+	}
+	return writeIIF(w, transactions, accounts)
+}
+
+// writeIIF writes transactions as a tab separated IIF file. The name column is
+// left empty, QuickBooks rejects names missing from its lists and the player's
+// name is in the memo.
+func writeIIF(w io.Writer, transactions []iifTransaction, accounts Accounts) error {
+	var b strings.Builder
+	line := func(fields ...string) {
+		for i, field := range fields {
+			if i > 0 {
+				b.WriteByte('\t')
+			}
+			b.WriteString(iifField(field))
+		}
+		b.WriteString("\r\n")
+	}
+
+	line("!TRNS", "TRNSID", "TRNSTYPE", "DATE", "ACCNT", "NAME", "AMOUNT", "DOCNUM", "MEMO")
+	line("!SPL", "SPLID", "TRNSTYPE", "DATE", "ACCNT", "NAME", "AMOUNT", "DOCNUM", "MEMO")
+	line("!ENDTRNS")
+	for _, t := range transactions {
+		date := t.date.Format("01/02/2006")
+		line("TRNS", "", t.kind, date, accounts.Bank, "", money.Decimal(t.amount), t.docNum, t.memo)
+		line("SPL", "", t.kind, date, accounts.Income, "", money.Decimal(-t.amount), t.docNum, t.memo)
+		line("ENDTRNS")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// iifField removes the tabs and line breaks that would split a field
+func iifField(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\r' || r == '\n' {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// id formats an id for a CSV cell
+func id(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+// text guards a CSV cell against being run as a formula by spreadsheets, a
+// cell starting with = + - or @ is prefixed with a quote
+func text(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,111 @@
+// Package fees assesses a player's registration fee from the season's fee
+// schedule. Amounts are in cents, see the money package for converting them to
+// and from the DECIMAL columns they are stored in.
+package fees
+
+import (
+	"fmt"
+	"time"
+)
+
+// Kinds of fee items, stored in registration_fee_items.kind
+const (
+	KindBase            = "base"
+	KindEarlyBird       = "early_bird"
+	KindLateFee         = "late_fee"
+	KindReturningPlayer = "returning_player"
+	KindSibling         = "sibling"
+	KindPromoCode       = "promo_code"
+)
+
+// Schedule is a season's fee schedule. Zero deadlines are not set.
+type Schedule struct {
+	// Registrations on or before EarlyBirdDeadline get EarlyBirdDiscount off
+	EarlyBirdDeadline time.Time
+	EarlyBirdDiscount int64
+	// Registrations after LateDeadline pay LateFee on top
+	LateDeadline            time.Time
+	LateFee                 int64
+	ReturningPlayerDiscount int64
+	SiblingDiscount         int64
+}
+
+// Promo is a redeemed promo code. Percent codes take Value hundredths of a
+// percent off, e.g. 1550 for 15.5%, amount codes take Value cents off.
+type Promo struct {
+	Code    string
+	Percent bool
+	Value   int64
+}
+
+// Input is what a player's fee depends on
+type Input struct {
+	// Base is the division's or season's fee
+	Base int64
+	// Schedule is nil when the season has no fee schedule
+	Schedule *Schedule
+	// Date is the day the player registered or was re-activated
+	Date      time.Time
+	Returning bool
+	Sibling   bool
+	Promo     *Promo
+}
+
+// Item is one line of the fee breakdown. Discounts are negative.
+type Item struct {
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+	Amount      int64  `json:"amount"`
+}
+
+// Assess returns the breakdown of the player's fee and its total. Discounts
+// are applied in order and never take the total below zero, the promo code is
+// applied last to what is left.
+func Assess(in Input) (items []Item, total int64) {
+	add := func(kind, description string, amount int64) {
+		if amount < 0 && -amount > total {
+			amount = -total
+		}
+		if amount == 0 {
+			return
+		}
+		items = append(items, Item{Kind: kind, Description: description, Amount: amount})
+		total += amount
+	}
+
+	items = append(items, Item{Kind: KindBase, Description: "Registration fee", Amount: in.Base})
+	total = in.Base
+
+	if s := in.Schedule; s != nil {
+		date := day(in.Date)
+		if !s.EarlyBirdDeadline.IsZero() && !date.After(day(s.EarlyBirdDeadline)) {
+			add(KindEarlyBird, "Early-bird discount", -s.EarlyBirdDiscount)
+		}
+		if !s.LateDeadline.IsZero() && date.After(day(s.LateDeadline)) {
+			add(KindLateFee, "Late registration fee", s.LateFee)
+		}
+		if in.Returning {
+			add(KindReturningPlayer, "Returning player discount", -s.ReturningPlayerDiscount)
+		}
+		if in.Sibling {
+			add(KindSibling, "Sibling discount", -s.SiblingDiscount)
+		}
+	}
+
+	if p := in.Promo; p != nil {
+		discount := p.Value
+		if p.Percent {
+			// Rounded to the nearest cent
+			discount = (total*p.Value + 5000) / 10000
+		}
+		add(KindPromoCode, fmt.Sprintf("Promo code %s", p.Code), -discount)
+	}
+
+	return items, total
+}
+
+// day truncates t to midnight in its location, so deadlines include the whole day
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,160 @@
+package fees
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(time.DateTime, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestAssess(t *testing.T) {
+	schedule := &Schedule{
+		EarlyBirdDeadline:       date("2026-01-15 00:00:00"),
+		EarlyBirdDiscount:       2000,
+		LateDeadline:            date("2026-02-15 00:00:00"),
+		LateFee:                 2500,
+		ReturningPlayerDiscount: 1000,
+		SiblingDiscount:         1500,
+	}
+
+	tests := []struct {
+		name      string
+		in        Input
+		wantItems []Item
+		wantTotal int64
+	}{
+		{
+			name:      "base only",
+			in:        Input{Base: 10000, Date: date("2026-02-01 12:00:00")},
+			wantItems: []Item{{KindBase, "Registration fee", 10000}},
+			wantTotal: 10000,
+		},
+		{
+			name:      "no schedule ignores discounts",
+			in:        Input{Base: 10000, Date: date("2026-01-01 12:00:00"), Returning: true, Sibling: true},
+			wantItems: []Item{{KindBase, "Registration fee", 10000}},
+			wantTotal: 10000,
+		},
+		{
+			name: "early bird on the deadline",
+			in:   Input{Base: 10000, Schedule: schedule, Date: date("2026-01-15 23:59:59")},
+			wantItems: []Item{
+				{KindBase, "Registration fee", 10000},
+				{KindEarlyBird, "Early-bird discount", -2000},
+			},
+			wantTotal: 8000,
+		},
+		{
+			name:      "day after the early bird deadline",
+			in:        Input{Base: 10000, Schedule: schedule, Date: date("2026-01-16 00:00:00")},
+			wantItems: []Item{{KindBase, "Registration fee", 10000}},
+			wantTotal: 10000,
+		},
+		{
+			name:      "on the late deadline",
+			in:        Input{Base: 10000, Schedule: schedule, Date: date("2026-02-15 23:59:59")},
+			wantItems: []Item{{KindBase, "Registration fee", 10000}},
+			wantTotal: 10000,
+		},
+		{
+			name: "day after the late deadline",
+			in:   Input{Base: 10000, Schedule: schedule, Date: date("2026-02-16 00:00:00")},
+			wantItems: []Item{
+				{KindBase, "Registration fee", 10000},
+				{KindLateFee, "Late registration fee", 2500},
+			},
+			wantTotal: 12500,
+		},
+		{
+			name: "all discounts",
+			in:   Input{Base: 10000, Schedule: schedule, Date: date("2026-01-01 00:00:00"), Returning: true, Sibling: true},
+			wantItems: []Item{
+				{KindBase, "Registration fee", 10000},
+				{KindEarlyBird, "Early-bird discount", -2000},
+				{KindReturningPlayer, "Returning player discount", -1000},
+				{KindSibling, "Sibling discount", -1500},
+			},
+			wantTotal: 5500,
+		},
+		{
+			name: "discounts clamp at zero",
+			in:   Input{Base: 2500, Schedule: schedule, Date: date("2026-01-01 00:00:00"), Returning: true, Sibling: true},
+			wantItems: []Item{
+				{KindBase, "Registration fee", 2500},
+				{KindEarlyBird, "Early-bird discount", -2000},
+				{KindReturningPlayer, "Returning player discount", -500},
+			},
+			wantTotal: 0,
+		},
+		{
+			name: "amount promo clamps at zero",
+			in:   Input{Base: 1000, Promo: &Promo{Code: "FREE", Value: 5000}},
+			wantItems: []Item{
+				{KindBase, "Registration fee", 1000},
+				{KindPromoCode, "Promo code FREE", -1000},
+			},
+			wantTotal: 0,
+		},
+		{
+			name: "percent promo applies to what is left",
+			in:   Input{Base: 10000, Schedule: schedule, Date: date("2026-01-01 00:00:00"), Promo: &Promo{Code: "HALF", Percent: true, Value: 5000}},
+			wantItems: []Item{
+				{KindBase, "Registration fee", 10000},
+				{KindEarlyBird, "Early-bird discount", -2000},
+				{KindPromoCode, "Promo code HALF", -4000},
+			},
+			wantTotal: 4000,
+		},
+		{
+			name: "percent promo rounds half a cent up",
+			in:   Input{Base: 1001, Promo: &Promo{Code: "P", Percent: true, Value: 5000}},
+			wantItems: []Item{
+				{KindBase, "Registration fee", 1001},
+				{KindPromoCode, "Promo code P", -501},
+			},
+			wantTotal: 500,
+		},
+		{
+			name: "percent promo rounds down below half a cent",
+			in:   Input{Base: 999, Promo: &Promo{Code: "P", Percent: true, Value: 1550}},
+			wantItems: []Item{
+				{KindBase, "Registration fee", 999},
+				{KindPromoCode, "Promo code P", -155},
+			},
+			wantTotal: 844,
+		},
+		{
+			name: "full percent promo",
+			in:   Input{Base: 12345, Promo: &Promo{Code: "ALL", Percent: true, Value: 10000}},
+			wantItems: []Item{
+				{KindBase, "Registration fee", 12345},
+				{KindPromoCode, "Promo code ALL", -12345},
+			},
+			wantTotal: 0,
+		},
+		{
+			name:      "percent promo on a free registration",
+			in:        Input{Base: 0, Promo: &Promo{Code: "P", Percent: true, Value: 2000}},
+			wantItems: []Item{{KindBase, "Registration fee", 0}},
+			wantTotal: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, total := Assess(tt.in)
+			if !reflect.DeepEqual(items, tt.wantItems) {
+				t.Errorf("Assess() items = %+v, want %+v", items, tt.wantItems)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("Assess() total = %d, want %d", total, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gbart/fcabl-api/internal/auth"
+	"github.com/gbart/fcabl-api/internal/mailer"
 	"github.com/gbart/fcabl-api/internal/repository"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
@@ -97,7 +101,7 @@ func (h *Handler) Register(c *gin.Context) {
 		PasswordHash: hashedPassword,
 		FirstName:    req.FirstName,
 		LastName:     req.LastName,
-		Role:         "normal", // Default role
+		Role:         auth.RoleNormal, // Default role
 	})
 	if err != nil {
 		slog.Error("Failed to create user", "error", err)
@@ -113,17 +117,13 @@ func (h *Handler) Register(c *gin.Context) {
 		return
 	}
 
-	// Generate JWT token
-	token, err := h.jwtService.GenerateToken(user.ID, user.Email, user.Role)
-	if err != nil {
-		slog.Error("Failed to generate token", "error", err)
+	// Start a session, the tokens are set in HTTP-only cookies
+	if err := h.startSession(c, user.ID, user.Email, user.Role); err != nil {
+		slog.Error("Failed to start session", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Registration failed"})
 		return
 	}
 
-	// Set HTTP-only cookie
-	setAuthCookie(c, token, h.config.JWTExpirationHours)
-
 	// Return response (token is in HTTP-only cookie, not in body)
 	userResponse := UserResponse{
 		ID:          fmt.Sprintf("%d", user.ID),
@@ -171,17 +171,13 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
-	// Generate JWT token
-	token, err := h.jwtService.GenerateToken(user.ID, user.Email, user.Role)
-	if err != nil {
-		slog.Error("Failed to generate token", "error", err)
+	// Start a session, the tokens are set in HTTP-only cookies
+	if err := h.startSession(c, user.ID, user.Email, user.Role); err != nil {
+		slog.Error("Failed to start session", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Login failed"})
 		return
 	}
 
-	// Set HTTP-only cookie
-	setAuthCookie(c, token, h.config.JWTExpirationHours)
-
 	// Return response (token is in HTTP-only cookie, not in body)
 	userResponse := UserResponse{
 		ID:          fmt.Sprintf("%d", user.ID),
@@ -222,6 +218,18 @@ func (h *Handler) VerifyToken(c *gin.Context) {
 		return
 	}
 
+	// Check the session has not been revoked
+	active, err := h.queries.IsSessionActive(c.Request.Context(), claims.SessionID)
+	if err != nil {
+		slog.Error("Database error during token verification", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Verification failed"})
+		return
+	}
+	if !active {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked"})
+		return
+	}
+
 	// Fetch user from database
 	user, err := h.queries.GetUserById(c.Request.Context(), claims.UserID)
 	if err != nil {
@@ -248,8 +256,17 @@ func (h *Handler) VerifyToken(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": userResponse})
 }
 
-// Logout clears the authentication cookie
+// Logout revokes the current session and clears the authentication cookies
 func (h *Handler) Logout(c *gin.Context) {
+	if refreshToken, err := c.Cookie(refreshCookieName); err == nil {
+		err := h.queries.RevokeSessionByRefreshHash(c.Request.Context(), auth.HashRefreshToken(refreshToken))
+		if err != nil {
+			slog.Error("Failed to revoke session", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Logout failed"})
+			return
+		}
+	}
+
 	clearAuthCookie(c)
 	c.JSON(http.StatusOK, gin.H{"data": gin.H{"message": "Logged out successfully"}})
 }
@@ -295,16 +312,19 @@ func (h *Handler) RequestPasswordReset(c *gin.Context) {
 		return
 	}
 
-	// TODO: Send email with reset link
-	// For now, log the token (REMOVE IN PRODUCTION!)
-	slog.Info("Password reset requested", "email", req.Email, "token", resetToken)
-
-	c.JSON(http.StatusOK, gin.H{
-		"data": gin.H{
-			"message":    "If the email exists, a reset link has been sent",
-			"resetToken": resetToken, // REMOVE IN PRODUCTION - only for development
-		},
+	resetURL := fmt.Sprintf("%s/reset-password?%s",
+		strings.TrimRight(h.config.FrontendURL, "/"),
+		url.Values{"token": {resetToken}}.Encode(),
+	)
+	h.sendEmail(user.Email, "password_reset", mailer.PasswordResetData{
+		FirstName:        user.FirstName,
+		ResetURL:         resetURL,
+		ExpiresInMinutes: h.config.ResetTokenExpirationMin,
 	})
+
+	slog.Info("Password reset requested", "userId", user.ID)
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"message": "If the email exists, a reset link has been sent"}})
 }
 
 // ResetPassword completes the password reset flow
@@ -352,16 +372,51 @@ func (h *Handler) ResetPassword(c *gin.Context) {
 		// Continue anyway, password was updated
 	}
 
+	// Log out every session, the old password may have been compromised
+	if _, err := h.queries.RevokeAllUserSessions(c.Request.Context(), resetToken.UserID); err != nil {
+		slog.Error("Failed to revoke sessions after password reset", "error", err)
+		// Continue anyway, password was updated
+	}
+
+	// Let the user know, in case they did not make the change
+	if user, err := h.queries.GetUserById(c.Request.Context(), resetToken.UserID); err != nil {
+		slog.Error("Failed to fetch user for password changed email", "error", err)
+	} else {
+		h.sendEmail(user.Email, "password_changed", mailer.PasswordChangedData{
+			FirstName: user.FirstName,
+		})
+	}
+
 	c.JSON(http.StatusOK, gin.H{"data": gin.H{"message": "Password reset successfully"}})
 }
 
 // Helper functions
 
-func setAuthCookie(c *gin.Context, token string, expirationHours int) {
+// sendEmail renders the named email and sends it in the background, so the
+// response does not wait on the mail server and its timing does not reveal
+// whether an account exists. Failures are logged.
+func (h *Handler) sendEmail(to, name string, data any) {
+	msg, err := mailer.Render(name, to, data)
+	if err != nil {
+		slog.Error("Failed to render email", "template", name, "error", err)
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mailer.SendTimeout)
+		defer cancel()
+
+		if err := h.mailer.Send(ctx, msg); err != nil {
+			slog.Error("Failed to send email", "template", name, "error", err)
+		}
+	}()
+}
+
+func setAuthCookie(c *gin.Context, token string, expirationMinutes int) {
 	c.SetCookie(
 		"auth_token",         // name
 		token,                // value
-		expirationHours*3600, // maxAge in seconds
+		expirationMinutes*60, // maxAge in seconds
 		"/",                  // path
 		"",                   // domain (empty = current domain)
 		false,                // secure (set to true in production with HTTPS)
@@ -369,6 +424,20 @@ func setAuthCookie(c *gin.Context, token string, expirationHours int) {
 	)
 }
 
+// setRefreshCookie sets the refresh token cookie. It is only sent to the auth
+// routes, so it is not exposed on every request.
+func setRefreshCookie(c *gin.Context, token string, expirationHours int) {
+	c.SetCookie(
+		refreshCookieName,
+		token,
+		expirationHours*3600,
+		refreshCookiePath,
+		"",
+		false,
+		true,
+	)
+}
+
 func clearAuthCookie(c *gin.Context) {
 	c.SetCookie(
 		"auth_token",
@@ -379,4 +448,13 @@ func clearAuthCookie(c *gin.Context) {
 		false,
 		true,
 	)
+	c.SetCookie(
+		refreshCookieName,
+		"",
+		-1,
+		refreshCookiePath,
+		"",
+		false,
+		true,
+	)
 }
@@ -0,0 +1,411 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gbart/fcabl-api/internal/bracket"
+	"github.com/gbart/fcabl-api/internal/models"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	errBracketDraw          = errors.New("bracket games cannot end in a draw")
+	errBracketNextScheduled = errors.New("next bracket match is already scheduled")
+)
+
+// bracketError translates an error from advancing a bracket into a status code
+// and client facing message. A 500 status means the error was unexpected.
+func bracketError(err error) (int, string) {
+	switch {
+	case errors.Is(err, errBracketDraw):
+		return http.StatusBadRequest, "Playoff games cannot end in a draw."
+	case errors.Is(err, errBracketNextScheduled):
+		return http.StatusConflict, "The winner's next playoff game is already scheduled against a different team."
+	}
+	return http.StatusInternalServerError, ""
+}
+
+// advanceBracket records the winner of a completed bracket game and moves them
+// into the next match of the bracket. Games outside a bracket, and games that
+// are not completed, are left alone.
+func advanceBracket(ctx context.Context, q *repository.Queries, gameID int64) error {
+	match, err := q.GetBracketMatchByGameId(ctx, pgtype.Int8{Int64: gameID, Valid: true})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	game, err := q.GetGameById(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if game.Status != "completed" {
+		return nil
+	}
+	if game.HomeScore == game.AwayScore {
+		return errBracketDraw
+	}
+
+	winnerID := game.HomeTeamID
+	if game.AwayScore > game.HomeScore {
+		winnerID = game.AwayTeamID
+	}
+	winnerSeed := match.AwaySeed
+	if match.HomeTeamID.Valid && match.HomeTeamID.Int64 == winnerID {
+		winnerSeed = match.HomeSeed
+	}
+	winner := pgtype.Int8{Int64: winnerID, Valid: true}
+
+	if err := q.SetBracketMatchWinner(ctx, repository.SetBracketMatchWinnerParams{
+		WinnerTeamID: winner,
+		ID:           match.ID,
+	}); err != nil {
+		return err
+	}
+
+	if !match.NextMatchID.Valid {
+		return nil
+	}
+
+	var advanced int64
+	if match.NextMatchSlot.String == string(bracket.Home) {
+		advanced, err = q.AdvanceToHomeSlot(ctx, repository.AdvanceToHomeSlotParams{
+			HomeTeamID: winner,
+			HomeSeed:   winnerSeed,
+			ID:         match.NextMatchID.Int64,
+		})
+	} else {
+		advanced, err = q.AdvanceToAwaySlot(ctx, repository.AdvanceToAwaySlotParams{
+			AwayTeamID: winner,
+			AwaySeed:   winnerSeed,
+			ID:         match.NextMatchID.Int64,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	if advanced == 0 {
+		return errBracketNextScheduled
+	}
+
+	return nil
+}
+
+// bracketTree loads a bracket with its matches grouped by round
+func (h *Handler) bracketTree(ctx context.Context, bracketID int64) (models.BracketTree, error) {
+	b, err := h.queries.GetBracketById(ctx, bracketID)
+	if err != nil {
+		return models.BracketTree{}, err
+	}
+
+	matches, err := h.queries.ListBracketMatches(ctx, bracketID)
+	if err != nil {
+		return models.BracketTree{}, err
+	}
+
+	tree := models.BracketTree{
+		Bracket: b,
+		Rounds:  []models.BracketRound{},
+	}
+	for _, m := range matches {
+		if len(tree.Rounds) == 0 || tree.Rounds[len(tree.Rounds)-1].Round != m.Round {
+			tree.Rounds = append(tree.Rounds, models.BracketRound{Round: m.Round})
+		}
+		last := &tree.Rounds[len(tree.Rounds)-1]
+		last.Matches = append(last.Matches, m)
+	}
+
+	return tree, nil
+}
+
+// GetBracket handles GET requests for a playoff bracket and its matches by round
+func (h *Handler) GetBracket(c *gin.Context) {
+	bracketIDStr := c.Query("id")
+	slog.Info("Starting GetBracket", "bracketIdStr", bracketIDStr)
+
+	if bracketIDStr == "" {
+		slog.Warn("Bracket ID is empty.")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Please provide a bracket id.",
+		})
+		return
+	}
+
+	bracketID, err := strconv.ParseInt(bracketIDStr, 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse bracket id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse bracket id. Please provide a valid id.",
+		})
+		return
+	}
+
+	tree, err := h.bracketTree(c.Request.Context(), bracketID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			slog.Warn("No bracket found.")
+			c.JSON(http.StatusOK, gin.H{
+				"data": []models.BracketTree{},
+			})
+		} else {
+			slog.Error("Error retrieving bracket", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Error retrieving bracket.",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": tree,
+	})
+}
+
+// ListBrackets handles GET requests to list the playoff brackets of a season
+func (h *Handler) ListBrackets(c *gin.Context) {
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	brackets, err := h.queries.ListBrackets(c.Request.Context(), repository.ListBracketsParams(scope))
+	if err != nil {
+		slog.Error("Failed to fetch brackets", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch brackets",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": brackets,
+	})
+}
+
+// CreateBracket handles POST requests to create a single elimination bracket
+// seeded from the current standings
+func (h *Handler) CreateBracket(c *gin.Context) {
+	var createBracketRequest models.CreateBracketRequest
+	if err := c.ShouldBindJSON(&createBracketRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for creating bracket.",
+		})
+		return
+	}
+
+	scope, ok := h.resolveSeasonScope(c, createBracketRequest.SeasonID, createBracketRequest.DivisionID)
+	if !ok {
+		return
+	}
+
+	standings, err := h.queries.GetTeamStandings(c.Request.Context(), repository.GetTeamStandingsParams(scope))
+	if err != nil {
+		slog.Error("Failed to fetch team standings", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch team standings",
+		})
+		return
+	}
+
+	if teamCount := createBracketRequest.TeamCount; teamCount > 0 {
+		if teamCount > len(standings) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Only %d teams are in the standings.", len(standings)),
+			})
+			return
+		}
+		standings = standings[:teamCount]
+	}
+
+	matches, err := bracket.SingleElimination(len(standings))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "A bracket needs at least two teams.",
+		})
+		return
+	}
+
+	// seedParams maps a seed to the team holding it, 0 meaning no team yet
+	seedParams := func(seed int) (pgtype.Int4, pgtype.Int8) {
+		if seed == 0 {
+			return pgtype.Int4{}, pgtype.Int8{}
+		}
+		return pgtype.Int4{Int32: int32(seed), Valid: true},
+			pgtype.Int8{Int64: standings[seed-1].ID, Valid: true}
+	}
+
+	var bracketID int64
+	err = h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		newBracket, err := q.CreateBracket(c.Request.Context(), repository.CreateBracketParams{
+			SeasonID:   scope.SeasonID,
+			DivisionID: scope.DivisionID,
+			Name:       createBracketRequest.Name,
+		})
+		if err != nil {
+			return err
+		}
+		bracketID = newBracket.ID
+
+		// Later rounds are created first so each match can point at the one
+		// its winner advances to.
+		type key struct{ round, position int }
+		ids := make(map[key]int64, len(matches))
+		for i := len(matches) - 1; i >= 0; i-- {
+			m := matches[i]
+			params := repository.CreateBracketMatchParams{
+				BracketID: bracketID,
+				Round:     int32(m.Round),
+				Position:  int32(m.Position),
+			}
+			params.HomeSeed, params.HomeTeamID = seedParams(m.HomeSeed)
+			params.AwaySeed, params.AwayTeamID = seedParams(m.AwaySeed)
+			if m.NextPosition != 0 {
+				params.NextMatchID = pgtype.Int8{Int64: ids[key{m.Round + 1, m.NextPosition}], Valid: true}
+				params.NextMatchSlot = pgtype.Text{String: string(m.NextSlot), Valid: true}
+			}
+
+			created, err := q.CreateBracketMatch(c.Request.Context(), params)
+			if err != nil {
+				return err
+			}
+			ids[key{m.Round, m.Position}] = created.ID
+		}
+		return nil
+	})
+	if err != nil {
+		if code, constraint := pgErrorCode(err); code == pgUniqueViolation && constraint == "unique_bracket_name_per_season" {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "A bracket with that name already exists in this season.",
+			})
+			return
+		}
+		slog.Error("Failed to create bracket", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create bracket.",
+		})
+		return
+	}
+
+	tree, err := h.bracketTree(c.Request.Context(), bracketID)
+	if err != nil {
+		slog.Error("Failed to fetch bracket", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Bracket created, but failed to fetch it.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": tree,
+	})
+}
+
+// ScheduleBracketMatch handles POST requests to create the game for a bracket
+// match once both of its teams are known. The better seed is the home team.
+func (h *Handler) ScheduleBracketMatch(c *gin.Context) {
+	var scheduleBracketMatchRequest models.ScheduleBracketMatchRequest
+	if err := c.ShouldBindJSON(&scheduleBracketMatchRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for scheduling bracket match.",
+		})
+		return
+	}
+
+	var game repository.Game
+	status, message := http.StatusOK, ""
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		match, err := q.GetBracketMatchById(c.Request.Context(), scheduleBracketMatchRequest.MatchID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				status, message = http.StatusNotFound, "Bracket match not found."
+			}
+			return err
+		}
+		if !match.HomeTeamID.Valid || !match.AwayTeamID.Valid {
+			status, message = http.StatusBadRequest, "Both teams must be decided before the match can be scheduled."
+			return errors.New("bracket match teams not decided")
+		}
+
+		home, away := match.HomeTeamID.Int64, match.AwayTeamID.Int64
+		if match.AwaySeed.Int32 < match.HomeSeed.Int32 {
+			home, away = away, home
+		}
+
+		game, err = q.CreateGame(c.Request.Context(), repository.CreateGameParams{
+			HomeTeamID: home,
+			AwayTeamID: away,
+			GameTime:   scheduleBracketMatchRequest.GameTime,
+		})
+		if err != nil {
+			return err
+		}
+
+		scheduled, err := q.SetBracketMatchGame(c.Request.Context(), repository.SetBracketMatchGameParams{
+			GameID: pgtype.Int8{Int64: game.ID, Valid: true},
+			ID:     match.ID,
+		})
+		if err != nil {
+			return err
+		}
+		if scheduled == 0 {
+			status, message = http.StatusConflict, "This bracket match is already scheduled."
+			return errors.New("bracket match already scheduled")
+		}
+		return nil
+	})
+	if err != nil {
+		if status == http.StatusOK {
+			slog.Error("Failed to schedule bracket match", "error", err)
+			status, message = http.StatusInternalServerError, "Failed to schedule bracket match."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": game,
+	})
+}
+
+// DeleteBracket handles DELETE requests to delete a bracket along with the
+// games that were scheduled for it
+func (h *Handler) DeleteBracket(c *gin.Context) {
+	bracketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse bracket id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse bracket id. Please provide a valid id.",
+		})
+		return
+	}
+
+	err = h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		if err := q.DeleteBracketGames(c.Request.Context(), bracketID); err != nil {
+			return err
+		}
+		return q.DeleteBracket(c.Request.Context(), bracketID)
+	})
+	if err != nil {
+		slog.Error("Failed to delete bracket", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to delete bracket.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
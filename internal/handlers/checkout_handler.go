@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gbart/fcabl-api/internal/checkout"
+	"github.com/gbart/fcabl-api/internal/models"
+	"github.com/gbart/fcabl-api/internal/money"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CreateCheckoutSession handles POST requests from a signed in player to pay
+// their outstanding registration fee. A checkout session is created with the
+// payment provider and recorded as a pending payment, which the provider's
+// webhook completes. A still open session for the same amount is reused, so
+// repeated requests do not create duplicate payments. Open sessions for
+// another amount are expired and their payments marked failed.
+func (h *Handler) CreateCheckoutSession(c *gin.Context) {
+	userID := c.GetInt64("userID")
+	slog.Info("Starting CreateCheckoutSession", "userId", userID)
+
+	player, err := h.queries.GetPlayerByUserId(c.Request.Context(), userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "You are not registered as a player.",
+			})
+		} else {
+			slog.Error("Error retrieving player", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Error retrieving player.",
+			})
+		}
+		return
+	}
+
+	user, err := h.queries.GetUserById(c.Request.Context(), userID)
+	if err != nil {
+		slog.Error("Error retrieving user", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to start checkout.",
+		})
+		return
+	}
+
+	description := "FCABL registration fee"
+	if season, err := h.queries.GetSeasonById(c.Request.Context(), player.SeasonID); err == nil {
+		description = fmt.Sprintf("%s (%s)", description, season.Name)
+	}
+
+	// The player is locked while their sessions are checked and created, so
+	// concurrent requests can't each create one
+	var payment repository.Payment
+	var feeCents int64
+	created := false
+	status, message := http.StatusOK, ""
+	err = h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		player, err := q.GetPlayerByIdForUpdate(c.Request.Context(), player.ID)
+		if err != nil {
+			return err
+		}
+
+		feeCents = money.FromNumeric(player.RegistrationFeeDue)
+		if feeCents <= 0 {
+			status, message = http.StatusBadRequest, "No registration fee is due."
+			return nil
+		}
+
+		payment, err = q.GetOpenCheckoutPayment(c.Request.Context(), repository.GetOpenCheckoutPaymentParams{
+			PlayerID: player.ID,
+			Provider: h.checkout.Name(),
+			Amount:   money.ToNumeric(feeCents),
+		})
+		if err == nil {
+			return nil
+		}
+		if err != pgx.ErrNoRows {
+			return err
+		}
+
+		// Sessions for another amount are expired first, so the player can't
+		// pay both
+		openPayments, err := q.ListOpenCheckoutPayments(c.Request.Context(), repository.ListOpenCheckoutPaymentsParams{
+			PlayerID: player.ID,
+			Provider: h.checkout.Name(),
+		})
+		if err != nil {
+			return err
+		}
+		for _, open := range openPayments {
+			if err := h.checkout.ExpireSession(c.Request.Context(), open.StripeID); err != nil {
+				slog.Error("Failed to expire checkout session", "provider", h.checkout.Name(), "sessionId", open.StripeID, "error", err)
+				status, message = http.StatusBadGateway, "Failed to start checkout with the payment provider."
+				return nil
+			}
+			if _, err := failPayment(c.Request.Context(), q, open); err != nil {
+				return err
+			}
+		}
+
+		frontendURL := strings.TrimRight(h.config.FrontendURL, "/")
+		session, err := h.checkout.CreateSession(c.Request.Context(), checkout.Request{
+			AmountCents:   feeCents,
+			Currency:      h.config.CheckoutCurrency,
+			Description:   description,
+			CustomerEmail: user.Email,
+			// Stripe fills in {CHECKOUT_SESSION_ID} when redirecting back
+			SuccessURL:     frontendURL + "/registration/checkout/success?session_id={CHECKOUT_SESSION_ID}",
+			CancelURL:      frontendURL + "/registration/checkout/cancel",
+			Reference:      fmt.Sprintf("player:%d", player.ID),
+			IdempotencyKey: c.GetHeader("Idempotency-Key"),
+		})
+		if err != nil {
+			slog.Error("Failed to create checkout session", "provider", h.checkout.Name(), "error", err)
+			status, message = http.StatusBadGateway, "Failed to start checkout with the payment provider."
+			return nil
+		}
+
+		payment, err = q.CreateCheckoutPayment(c.Request.Context(), repository.CreateCheckoutPaymentParams{
+			PlayerID:          player.ID,
+			StripeID:          session.ID,
+			Amount:            money.ToNumeric(feeCents),
+			Provider:          h.checkout.Name(),
+			CheckoutUrl:       pgtype.Text{String: session.URL, Valid: true},
+			CheckoutExpiresAt: pgtype.Timestamp{Time: session.ExpiresAt, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("recording checkout session %s: %w", session.ID, err)
+		}
+		created = true
+		return nil
+	})
+	if err != nil {
+		slog.Error("Failed to start checkout", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to start checkout.",
+		})
+		return
+	}
+	if status != http.StatusOK {
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	if created {
+		slog.Info("Created checkout session", "playerId", player.ID, "paymentId", payment.ID, "amount", money.Format(feeCents))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": models.NewCheckoutSessionResponse(payment),
+	})
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gbart/fcabl-api/internal/models"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// ListDivisions handles GET requests to list the divisions of a season.
+// Defaults to the current season when no seasonId is given.
+func (h *Handler) ListDivisions(c *gin.Context) {
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	divisions, err := h.queries.ListDivisionsBySeason(c.Request.Context(), scope.SeasonID)
+	if err != nil {
+		slog.Error("Failed to fetch divisions", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch divisions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": divisions,
+	})
+}
+
+// CreateDivision handles POST requests to add a division to a season
+func (h *Handler) CreateDivision(c *gin.Context) {
+	var createDivisionRequest models.CreateDivisionRequest
+	if err := c.ShouldBindJSON(&createDivisionRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for creating division.",
+		})
+		return
+	}
+
+	newDivision, err := h.queries.CreateDivision(c.Request.Context(), createDivisionRequest.IntoDBModel())
+	if err != nil {
+		status, message := seasonConstraintError(err)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to create division", "error", err)
+			message = "Failed to create division."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": newDivision,
+	})
+}
+
+// UpdateDivision handles PUT requests to rename a division or change its
+// registration fee. The season's registrations are reconciled against the fee.
+func (h *Handler) UpdateDivision(c *gin.Context) {
+	var updateDivisionRequest models.UpdateDivisionRequest
+	if err := c.ShouldBindJSON(&updateDivisionRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for updating division.",
+		})
+		return
+	}
+
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		seasonID, err := q.UpdateDivision(c.Request.Context(), updateDivisionRequest.IntoDBModel())
+		if err != nil {
+			return err
+		}
+		return reconcileSeason(c.Request.Context(), q, seasonID)
+	})
+	if err == pgx.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Division not found.",
+		})
+		return
+	}
+	if err != nil {
+		status, message := seasonConstraintError(err)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to update division", "error", err)
+			message = "Failed to update division."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// DeleteDivision handles DELETE requests to delete a division. Its teams stay
+// in the season without a division, and their players fall back to the
+// season's registration fee.
+func (h *Handler) DeleteDivision(c *gin.Context) {
+	divisionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse division id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse division id. Please provide a valid id.",
+		})
+		return
+	}
+
+	err = h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		seasonID, err := q.DeleteDivision(c.Request.Context(), divisionID)
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return reconcileSeason(c.Request.Context(), q, seasonID)
+	})
+	if err != nil {
+		slog.Error("Failed to delete division", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to delete division.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
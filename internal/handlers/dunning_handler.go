@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gbart/fcabl-api/internal/models"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// rosterLockedPlayer returns the first of the players who is locked out of
+// game rosters for not paying and is not already in the game's box score, or
+// 0 when there is none. Players already in a box score can still be corrected.
+func rosterLockedPlayer(ctx context.Context, q *repository.Queries, gameID int64, playerIDs ...int64) (int64, error) {
+	locked, err := q.ListRosterLockedPlayersForGame(ctx, gameID)
+	if err != nil {
+		return 0, err
+	}
+
+	lockedIDs := make(map[int64]bool, len(locked))
+	for _, id := range locked {
+		lockedIDs[id] = true
+	}
+	for _, id := range playerIDs {
+		if lockedIDs[id] {
+			return id, nil
+		}
+	}
+	return 0, nil
+}
+
+// rosterLockedMessage is the error for a player locked out of game rosters
+func rosterLockedMessage(playerID int64) string {
+	return fmt.Sprintf("Player %d is locked out of game rosters until their registration fee is paid.", playerID)
+}
+
+// SetPaymentRemindersOptOut handles PATCH requests from a signed in user to
+// turn their registration fee reminder emails off or back on
+func (h *Handler) SetPaymentRemindersOptOut(c *gin.Context) {
+	var remindersRequest models.PaymentRemindersRequest
+	if err := c.ShouldBindJSON(&remindersRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for payment reminders.",
+		})
+		return
+	}
+	userID := c.GetInt64("userID")
+	slog.Info("Starting SetPaymentRemindersOptOut", "userId", userID, "optOut", remindersRequest.OptOut)
+
+	if err := h.queries.SetPaymentRemindersOptOut(c.Request.Context(), repository.SetPaymentRemindersOptOutParams{
+		PaymentRemindersOptOut: remindersRequest.OptOut,
+		ID:                     userID,
+	}); err != nil {
+		slog.Error("Failed to update payment reminders", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to update payment reminders.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// ListPaymentReminders handles GET requests for the history of registration
+// fee reminders. A single player's are listed with the playerId query
+// parameter, otherwise the season's with player names.
+func (h *Handler) ListPaymentReminders(c *gin.Context) {
+	if playerIDStr := c.Query("playerId"); playerIDStr != "" {
+		playerID, err := strconv.ParseInt(playerIDStr, 10, 64)
+		if err != nil {
+			slog.Error("Failed to parse player id", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Failed to parse player id. Please provide a valid id.",
+			})
+			return
+		}
+
+		reminders, err := h.queries.ListPaymentRemindersByPlayer(c.Request.Context(), playerID)
+		if err != nil {
+			slog.Error("Failed to fetch payment reminders", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to fetch payment reminders.",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data": reminders,
+		})
+		return
+	}
+
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	reminders, err := h.queries.ListPaymentReminders(c.Request.Context(), repository.ListPaymentRemindersParams(scope))
+	if err != nil {
+		slog.Error("Failed to fetch payment reminders", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch payment reminders.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": reminders,
+	})
+}
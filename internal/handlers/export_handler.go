@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gbart/fcabl-api/internal/export"
+	"github.com/gbart/fcabl-api/internal/money"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Export formats, chosen with the format query parameter
+const (
+	exportCSV = "csv"
+	exportIIF = "iif"
+)
+
+// exportContentTypes are the content types of the export formats
+var exportContentTypes = map[string]string{
+	exportCSV: "text/csv; charset=utf-8",
+	exportIIF: "application/vnd.intu.iif",
+}
+
+// parseExportFormat reads the format query parameter, csv by default. If it
+// is not one of formats an error response is written and ok is false.
+func parseExportFormat(c *gin.Context, formats ...string) (format string, ok bool) {
+	format = c.DefaultQuery("format", exportCSV)
+	for _, allowed := range formats {
+		if format == allowed {
+			return format, true
+		}
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": fmt.Sprintf("Unsupported export format. Please provide one of %v.", formats),
+	})
+	return format, false
+}
+
+// sendExport writes an export as a download named for what it holds and the
+// dates it covers. The response is written as the export is, so a failure
+// part way can only be logged.
+func (h *Handler) sendExport(c *gin.Context, name, format string, from, to time.Time, write func(w io.Writer) error) {
+	filename := fmt.Sprintf("%s-%s-to-%s.%s", name, from.Format(time.DateOnly), to.AddDate(0, 0, -1).Format(time.DateOnly), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Content-Type", exportContentTypes[format])
+	c.Status(http.StatusOK)
+
+	if err := write(c.Writer); err != nil {
+		slog.Error("Failed to write export", "export", name, "error", err)
+	}
+}
+
+// ExportPayments handles GET requests to download the completed and refunded
+// payments made between the from and to query parameters (YYYY-MM-DD,
+// inclusive, the last 30 days by default). The format query parameter is csv,
+// or iif for QuickBooks deposits.
+func (h *Handler) ExportPayments(c *gin.Context) {
+	format, ok := parseExportFormat(c, exportCSV, exportIIF)
+	if !ok {
+		return
+	}
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+	slog.Info("Starting ExportPayments", "from", from, "to", to, "format", format)
+
+	rows, err := h.queries.ListPaymentsForExport(c.Request.Context(), repository.ListPaymentsForExportParams{
+		FromDate: pgtype.Timestamp{Time: from, Valid: true},
+		ToDate:   pgtype.Timestamp{Time: to, Valid: true},
+	})
+	if err != nil {
+		slog.Error("Failed to fetch payments for export", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to export payments.",
+		})
+		return
+	}
+
+	payments := make([]export.Payment, 0, len(rows))
+	for _, row := range rows {
+		payments = append(payments, export.Payment{
+			ID:            row.ID,
+			Date:          row.PaymentDate.Time,
+			PlayerID:      row.PlayerID,
+			FirstName:     row.FirstName,
+			LastName:      row.LastName,
+			Email:         row.Email,
+			Season:        row.SeasonName,
+			Provider:      row.Provider,
+			Reference:     row.StripeID,
+			PaymentIntent: row.PaymentIntentID.String,
+			Status:        row.Status,
+			AmountCents:   money.FromNumeric(row.Amount),
+			RefundedCents: money.FromNumeric(row.AmountRefunded),
+		})
+	}
+
+	h.sendExport(c, "payments", format, from, to, func(w io.Writer) error {
+		if format == exportIIF {
+			return export.WritePaymentsIIF(w, payments, h.accounts())
+		}
+		return export.WritePaymentsCSV(w, payments)
+	})
+}
+
+// ExportRefunds handles GET requests to download the refunds that succeeded
+// between the from and to query parameters. The format query parameter is
+// csv, or iif for QuickBooks checks.
+func (h *Handler) ExportRefunds(c *gin.Context) {
+	format, ok := parseExportFormat(c, exportCSV, exportIIF)
+	if !ok {
+		return
+	}
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+	slog.Info("Starting ExportRefunds", "from", from, "to", to, "format", format)
+
+	rows, err := h.queries.ListRefundsForExport(c.Request.Context(), repository.ListRefundsForExportParams{
+		FromDate: pgtype.Timestamp{Time: from, Valid: true},
+		ToDate:   pgtype.Timestamp{Time: to, Valid: true},
+	})
+	if err != nil {
+		slog.Error("Failed to fetch refunds for export", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to export refunds.",
+		})
+		return
+	}
+
+	refunds := make([]export.Refund, 0, len(rows))
+	for _, row := range rows {
+		refunds = append(refunds, export.Refund{
+			ID:               row.ID,
+			Date:             row.CreatedAt.Time,
+			PaymentID:        row.PaymentID,
+			PlayerID:         row.PlayerID,
+			FirstName:        row.FirstName,
+			LastName:         row.LastName,
+			Email:            row.Email,
+			Season:           row.SeasonName,
+			PaymentReference: row.StripeID,
+			Reference:        row.ProviderRefundID.String,
+			Reason:           row.Reason,
+			AmountCents:      money.FromNumeric(row.Amount),
+		})
+	}
+
+	h.sendExport(c, "refunds", format, from, to, func(w io.Writer) error {
+		if format == exportIIF {
+			return export.WriteRefundsIIF(w, refunds, h.accounts())
+		}
+		return export.WriteRefundsCSV(w, refunds)
+	})
+}
+
+// ExportBalances handles GET requests to download, as CSV, the balance of
+// every player in a season who owes or is owed money, from the ledger as of
+// the end of the to query parameter
+func (h *Handler) ExportBalances(c *gin.Context) {
+	if _, ok := parseExportFormat(c, exportCSV); !ok {
+		return
+	}
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+	slog.Info("Starting ExportBalances", "seasonId", scope.SeasonID, "to", to)
+
+	rows, err := h.queries.ListLedgerPlayerBalances(c.Request.Context(), repository.ListLedgerPlayerBalancesParams{
+		SeasonID: scope.SeasonID,
+		Before:   pgtype.Timestamp{Time: to, Valid: true},
+	})
+	if err != nil {
+		slog.Error("Failed to fetch balances for export", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to export balances.",
+		})
+		return
+	}
+
+	balances := make([]export.Balance, 0, len(rows))
+	for _, row := range rows {
+		balances = append(balances, export.Balance{
+			PlayerID:     row.PlayerID.Int64,
+			FirstName:    row.FirstName.String,
+			LastName:     row.LastName.String,
+			BalanceCents: money.FromNumeric(row.Balance),
+		})
+	}
+
+	h.sendExport(c, "balances", exportCSV, from, to, func(w io.Writer) error {
+		return export.WriteBalancesCSV(w, balances)
+	})
+}
+
+// accounts are the QuickBooks accounts IIF exports post to
+func (h *Handler) accounts() export.Accounts {
+	return export.Accounts{
+		Bank:   h.config.QuickBooksBankAccount,
+		Income: h.config.QuickBooksIncomeAccount,
+	}
+}
@@ -0,0 +1,599 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gbart/fcabl-api/internal/fees"
+	"github.com/gbart/fcabl-api/internal/models"
+	"github.com/gbart/fcabl-api/internal/money"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// registrationError is a problem with a player's registration the client can
+// fix. Returning it from a transaction rolls the registration back.
+type registrationError struct {
+	message string
+}
+
+func (e registrationError) Error() string {
+	return e.message
+}
+
+// asRegistrationError reports whether err is a registrationError and returns
+// its message for the client
+func asRegistrationError(err error) (string, bool) {
+	var regErr registrationError
+	if errors.As(err, &regErr) {
+		return regErr.message, true
+	}
+	return "", false
+}
+
+// feeConstraintError maps constraint violations on fee schedules and promo
+// codes to a status and message for the client, like seasonConstraintError.
+func feeConstraintError(err error) (int, string) {
+	code, constraint := pgErrorCode(err)
+	if code == pgForeignKeyViolation {
+		if constraint == "promo_code_redemptions_promo_code_id_fkey" {
+			return http.StatusConflict, "Promo code has been redeemed, deactivate it instead."
+		}
+		return http.StatusBadRequest, "Referenced season does not exist."
+	}
+
+	switch constraint {
+	case "fee_schedule_amounts_not_negative":
+		return http.StatusBadRequest, "Fees and discounts must not be negative."
+	case "fee_schedule_deadlines_ordered":
+		return http.StatusBadRequest, "Late deadline must be on or after the early-bird deadline."
+	case "promo_codes_code_key":
+		return http.StatusConflict, "A promo code with that code already exists."
+	case "promo_code_discount_valid":
+		return http.StatusBadRequest, "Discount must be more than zero, and at most 100 for percent discounts."
+	case "promo_code_redemption_limit":
+		return http.StatusBadRequest, "Redemption limit must not be below the redemptions so far."
+	case "promo_code_dates_ordered":
+		return http.StatusBadRequest, "Promo code must end on or after the day it starts."
+	}
+	return http.StatusInternalServerError, ""
+}
+
+// assessPlayer assesses the player's registration fee from the division's or
+// season's fee, the season's fee schedule and their promo code, as of the day
+// they registered or were re-activated. The breakdown replaces the previous
+// one and the player's balance is reconciled against the new fee.
+func assessPlayer(ctx context.Context, q *repository.Queries, playerID int64) error {
+	feeContext, err := q.GetPlayerFeeContext(ctx, playerID)
+	if err != nil {
+		return err
+	}
+
+	input := fees.Input{
+		Base:      money.FromNumeric(feeContext.BaseFee),
+		Date:      feeContext.FeeAssessedOn.Time,
+		Returning: feeContext.IsReturning,
+		Sibling:   feeContext.HasSibling,
+	}
+
+	schedule, err := q.GetFeeScheduleBySeason(ctx, feeContext.SeasonID)
+	if err != nil && err != pgx.ErrNoRows {
+		return err
+	}
+	if err == nil {
+		input.Schedule = &fees.Schedule{
+			EarlyBirdDeadline:       schedule.EarlyBirdDeadline.Time,
+			EarlyBirdDiscount:       money.FromNumeric(schedule.EarlyBirdDiscount),
+			LateDeadline:            schedule.LateDeadline.Time,
+			LateFee:                 money.FromNumeric(schedule.LateFee),
+			ReturningPlayerDiscount: money.FromNumeric(schedule.ReturningPlayerDiscount),
+			SiblingDiscount:         money.FromNumeric(schedule.SiblingDiscount),
+		}
+	}
+
+	promo, err := q.GetPlayerPromoCode(ctx, playerID)
+	if err != nil && err != pgx.ErrNoRows {
+		return err
+	}
+	if err == nil {
+		input.Promo = &fees.Promo{
+			Code:    promo.Code,
+			Percent: promo.DiscountType == "percent",
+			Value:   money.FromNumeric(promo.DiscountValue),
+		}
+	}
+
+	items, total := fees.Assess(input)
+
+	if err := q.DeleteRegistrationFeeItems(ctx, playerID); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := q.CreateRegistrationFeeItem(ctx, repository.CreateRegistrationFeeItemParams{
+			PlayerID:    playerID,
+			Kind:        item.Kind,
+			Description: item.Description,
+			Amount:      money.ToNumeric(item.Amount),
+		}); err != nil {
+			return err
+		}
+	}
+	if err := q.SetPlayerRegistrationFee(ctx, repository.SetPlayerRegistrationFeeParams{
+		RegistrationFee: money.ToNumeric(total),
+		ID:              playerID,
+	}); err != nil {
+		return err
+	}
+
+	return reconcilePlayer(ctx, q, playerID)
+}
+
+// redeemPromoCode redeems a promo code for the player, to be applied by the
+// next assessPlayer. A code that cannot be redeemed is a registrationError.
+func redeemPromoCode(ctx context.Context, q *repository.Queries, player repository.Player, code string, date time.Time) error {
+	promo, err := q.GetPromoCodeByCode(ctx, strings.ToUpper(strings.TrimSpace(code)))
+	if err == pgx.ErrNoRows {
+		return registrationError{"Promo code not found."}
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !promo.IsActive:
+		return registrationError{"Promo code is no longer active."}
+	case promo.SeasonID.Valid && promo.SeasonID.Int64 != player.SeasonID:
+		return registrationError{"Promo code is not valid for this season."}
+	case promo.ValidFrom.Valid && date.Before(promo.ValidFrom.Time):
+		return registrationError{"Promo code is not valid yet."}
+	case promo.ValidUntil.Valid && !date.Before(promo.ValidUntil.Time.AddDate(0, 0, 1)):
+		return registrationError{"Promo code has expired."}
+	}
+
+	if _, err := q.GetPlayerPromoCode(ctx, player.ID); err != pgx.ErrNoRows {
+		if err != nil {
+			return err
+		}
+		return registrationError{"A promo code has already been applied to this registration."}
+	}
+
+	if _, err := q.RedeemPromoCode(ctx, promo.ID); err != nil {
+		if err == pgx.ErrNoRows {
+			return registrationError{"Promo code has reached its redemption limit."}
+		}
+		return err
+	}
+	return q.CreatePromoCodeRedemption(ctx, repository.CreatePromoCodeRedemptionParams{
+		PromoCodeID: promo.ID,
+		PlayerID:    player.ID,
+	})
+}
+
+// registrationFees returns the player's fee breakdown
+func registrationFees(ctx context.Context, q *repository.Queries, playerID int64) (models.RegistrationFees, error) {
+	player, err := q.GetPlayerById(ctx, playerID)
+	if err != nil {
+		return models.RegistrationFees{}, err
+	}
+	items, err := q.ListRegistrationFeeItems(ctx, playerID)
+	if err != nil {
+		return models.RegistrationFees{}, err
+	}
+	return models.RegistrationFees{
+		PlayerID:           player.ID,
+		RegistrationFee:    player.RegistrationFee,
+		RegistrationFeeDue: player.RegistrationFeeDue,
+		IsFullyRegistered:  player.IsFullyRegistered,
+		Items:              items,
+	}, nil
+}
+
+// GetMyRegistrationFees handles GET requests from a signed in player for the
+// breakdown of their registration fee
+func (h *Handler) GetMyRegistrationFees(c *gin.Context) {
+	player, err := h.queries.GetPlayerByUserId(c.Request.Context(), c.GetInt64("userID"))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "You are not registered as a player.",
+			})
+		} else {
+			slog.Error("Error retrieving player", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Error retrieving player.",
+			})
+		}
+		return
+	}
+
+	breakdown, err := registrationFees(c.Request.Context(), h.queries, player.ID)
+	if err != nil {
+		slog.Error("Failed to fetch registration fees", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch registration fees.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": breakdown,
+	})
+}
+
+// ApplyPromoCode handles POST requests from a signed in player to redeem a
+// promo code against their registration fee before paying it
+func (h *Handler) ApplyPromoCode(c *gin.Context) {
+	var applyPromoCodeRequest models.ApplyPromoCodeRequest
+	if err := c.ShouldBindJSON(&applyPromoCodeRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for applying promo code.",
+		})
+		return
+	}
+
+	userID := c.GetInt64("userID")
+	slog.Info("Starting ApplyPromoCode", "userId", userID)
+
+	var breakdown models.RegistrationFees
+	status, message := http.StatusOK, ""
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		player, err := q.GetPlayerByUserId(c.Request.Context(), userID)
+		if err == pgx.ErrNoRows {
+			status, message = http.StatusNotFound, "You are not registered as a player."
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if player.IsFullyRegistered {
+			status, message = http.StatusBadRequest, "Your registration is already paid."
+			return nil
+		}
+
+		now := time.Now().UTC()
+		if err := redeemPromoCode(c.Request.Context(), q, player, applyPromoCodeRequest.Code, now); err != nil {
+			return err
+		}
+		if err := assessPlayer(c.Request.Context(), q, player.ID); err != nil {
+			return err
+		}
+		breakdown, err = registrationFees(c.Request.Context(), q, player.ID)
+		return err
+	})
+	if message, ok := asRegistrationError(err); ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": message,
+		})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to apply promo code", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to apply promo code.",
+		})
+		return
+	}
+	if status != http.StatusOK {
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": breakdown,
+	})
+}
+
+// GetRegistrationFees handles GET requests for the breakdown of a player's
+// registration fee
+func (h *Handler) GetRegistrationFees(c *gin.Context) {
+	playerID, err := strconv.ParseInt(c.Query("playerId"), 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse player id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse player id. Please provide a valid id.",
+		})
+		return
+	}
+
+	breakdown, err := registrationFees(c.Request.Context(), h.queries, playerID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Player not found.",
+			})
+		} else {
+			slog.Error("Failed to fetch registration fees", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to fetch registration fees.",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": breakdown,
+	})
+}
+
+// AssessRegistrationFee handles POST requests to re-assess a player's fee as
+// of today, e.g. after the fee schedule changed, optionally redeeming a promo
+// code for them first
+func (h *Handler) AssessRegistrationFee(c *gin.Context) {
+	var assessRequest models.AssessRegistrationFeeRequest
+	if err := c.ShouldBindJSON(&assessRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for assessing registration fee.",
+		})
+		return
+	}
+	slog.Info("Starting AssessRegistrationFee", "playerId", assessRequest.PlayerID)
+
+	var breakdown models.RegistrationFees
+	status, message := http.StatusOK, ""
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		player, err := q.GetPlayerById(c.Request.Context(), assessRequest.PlayerID)
+		if err == pgx.ErrNoRows {
+			status, message = http.StatusNotFound, "Player not found."
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		if assessRequest.PromoCode != "" {
+			if err := redeemPromoCode(c.Request.Context(), q, player, assessRequest.PromoCode, now); err != nil {
+				return err
+			}
+		}
+		if err := assessPlayer(c.Request.Context(), q, player.ID); err != nil {
+			return err
+		}
+		breakdown, err = registrationFees(c.Request.Context(), q, player.ID)
+		return err
+	})
+	if message, ok := asRegistrationError(err); ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": message,
+		})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to assess registration fee", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to assess registration fee.",
+		})
+		return
+	}
+	if status != http.StatusOK {
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": breakdown,
+	})
+}
+
+// GetFeeSchedule handles GET requests for a season's fee schedule. Defaults
+// to the current season when no seasonId is given.
+func (h *Handler) GetFeeSchedule(c *gin.Context) {
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.queries.GetFeeScheduleBySeason(c.Request.Context(), scope.SeasonID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Season has no fee schedule.",
+			})
+		} else {
+			slog.Error("Failed to fetch fee schedule", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to fetch fee schedule.",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": schedule,
+	})
+}
+
+// UpsertFeeSchedule handles PUT requests to set a season's fee schedule.
+// Players already registered keep their assessed fee until re-assessed.
+func (h *Handler) UpsertFeeSchedule(c *gin.Context) {
+	var upsertFeeScheduleRequest models.UpsertFeeScheduleRequest
+	if err := c.ShouldBindJSON(&upsertFeeScheduleRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for setting fee schedule.",
+		})
+		return
+	}
+
+	schedule, err := h.queries.UpsertFeeSchedule(c.Request.Context(), upsertFeeScheduleRequest.IntoDBModel())
+	if err != nil {
+		status, message := feeConstraintError(err)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to set fee schedule", "error", err)
+			message = "Failed to set fee schedule."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": schedule,
+	})
+}
+
+// DeleteFeeSchedule handles DELETE requests to remove a season's fee schedule
+func (h *Handler) DeleteFeeSchedule(c *gin.Context) {
+	seasonID, err := strconv.ParseInt(c.Param("seasonId"), 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse season id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse season id. Please provide a valid id.",
+		})
+		return
+	}
+
+	deleted, err := h.queries.DeleteFeeSchedule(c.Request.Context(), seasonID)
+	if err != nil {
+		slog.Error("Failed to delete fee schedule", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to delete fee schedule.",
+		})
+		return
+	}
+
+	if deleted == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Season has no fee schedule.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// ListPromoCodes handles GET requests to list the promo codes valid in a
+// season, including those valid in every season
+func (h *Handler) ListPromoCodes(c *gin.Context) {
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	promoCodes, err := h.queries.ListPromoCodes(c.Request.Context(), pgtype.Int8{Int64: scope.SeasonID, Valid: true})
+	if err != nil {
+		slog.Error("Failed to fetch promo codes", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch promo codes.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": promoCodes,
+	})
+}
+
+// CreatePromoCode handles POST requests to create a promo code
+func (h *Handler) CreatePromoCode(c *gin.Context) {
+	var createPromoCodeRequest models.CreatePromoCodeRequest
+	if err := c.ShouldBindJSON(&createPromoCodeRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for creating promo code.",
+		})
+		return
+	}
+
+	promoCode, err := h.queries.CreatePromoCode(c.Request.Context(), createPromoCodeRequest.IntoDBModel())
+	if err != nil {
+		status, message := feeConstraintError(err)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to create promo code", "error", err)
+			message = "Failed to create promo code."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": promoCode,
+	})
+}
+
+// UpdatePromoCode handles PUT requests to update or deactivate a promo code.
+// Players who already redeemed it keep the discount they were given.
+func (h *Handler) UpdatePromoCode(c *gin.Context) {
+	var updatePromoCodeRequest models.UpdatePromoCodeRequest
+	if err := c.ShouldBindJSON(&updatePromoCodeRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for updating promo code.",
+		})
+		return
+	}
+
+	promoCode, err := h.queries.UpdatePromoCode(c.Request.Context(), updatePromoCodeRequest.IntoDBModel())
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Promo code not found.",
+			})
+			return
+		}
+		status, message := feeConstraintError(err)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to update promo code", "error", err)
+			message = "Failed to update promo code."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": promoCode,
+	})
+}
+
+// DeletePromoCode handles DELETE requests to delete a promo code that has not
+// been redeemed
+func (h *Handler) DeletePromoCode(c *gin.Context) {
+	promoCodeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse promo code id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse promo code id. Please provide a valid id.",
+		})
+		return
+	}
+
+	deleted, err := h.queries.DeletePromoCode(c.Request.Context(), promoCodeID)
+	if err != nil {
+		status, message := feeConstraintError(err)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to delete promo code", "error", err)
+			message = "Failed to delete promo code."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	if deleted == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Promo code not found.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gbart/fcabl-api/internal/models"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// boxScoreError translates a database error for a box-score row into a status
+// code and client facing message. A 500 status means the error was unexpected.
+func boxScoreError(err error, playerID int64) (int, string) {
+	code, constraint := pgErrorCode(err)
+	switch {
+	case code == pgCheckViolation && constraint == "player_team_must_play_in_game":
+		return http.StatusBadRequest, fmt.Sprintf("Player %d's team is not playing in this game.", playerID)
+	case code == pgCheckViolation && constraint == "score_not_negative":
+		return http.StatusBadRequest, "Score cannot be negative."
+	case code == pgCheckViolation && constraint == "stats_not_negative":
+		return http.StatusBadRequest, "Stats cannot be negative."
+	case code == pgCheckViolation && constraint == "field_goals_made_within_attempted":
+		return http.StatusBadRequest, fmt.Sprintf("Player %d cannot make more field goals than they attempted.", playerID)
+	case code == pgCheckViolation && constraint == "three_pointers_made_within_attempted":
+		return http.StatusBadRequest, fmt.Sprintf("Player %d cannot make more three pointers than they attempted.", playerID)
+	case code == pgCheckViolation && constraint == "free_throws_made_within_attempted":
+		return http.StatusBadRequest, fmt.Sprintf("Player %d cannot make more free throws than they attempted.", playerID)
+	case code == pgCheckViolation && constraint == "three_pointers_within_field_goals":
+		return http.StatusBadRequest, fmt.Sprintf("Player %d's three pointers must also be counted as field goals.", playerID)
+	case code == pgCheckViolation && constraint == "score_matches_shots":
+		return http.StatusBadRequest, fmt.Sprintf("Player %d's score must add up from their made shots: 2 per field goal, 1 more per three pointer and 1 per free throw.", playerID)
+	case code == pgForeignKeyViolation:
+		return http.StatusBadRequest, fmt.Sprintf("Game or player %d does not exist.", playerID)
+	case code == pgUniqueViolation:
+		return http.StatusConflict, fmt.Sprintf("Player %d already has a box score entry for this game.", playerID)
+	}
+	return gameScoreError(err)
+}
+
+// GetGameBoxScore handles GET requests for the box score of a single game
+func (h *Handler) GetGameBoxScore(c *gin.Context) {
+	gameIDStr := c.Query("gameId")
+	slog.Info("Starting GetGameBoxScore", "gameIdStr", gameIDStr)
+
+	if gameIDStr == "" {
+		slog.Warn("Game ID is empty.")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Please provide a game id.",
+		})
+		return
+	}
+
+	gameID, err := strconv.ParseInt(gameIDStr, 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse game id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse game id. Please provide a valid id.",
+		})
+		return
+	}
+
+	boxScore, err := h.queries.ListGameDetailsVerboseByGame(c.Request.Context(), gameID)
+	if err != nil {
+		slog.Error("Failed to fetch box score", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch box score",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": boxScore,
+	})
+}
+
+// CreateGameDetails handles POST requests to add a single player's line to a box score
+func (h *Handler) CreateGameDetails(c *gin.Context) {
+	var createGameDetailsRequest models.CreateGameDetailsRequest
+	if err := c.ShouldBindJSON(&createGameDetailsRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for creating game details.",
+		})
+		return
+	}
+
+	if !h.authorizeGame(c, createGameDetailsRequest.GameID) {
+		return
+	}
+
+	lockedPlayerID, err := rosterLockedPlayer(c.Request.Context(), h.queries, createGameDetailsRequest.GameID, createGameDetailsRequest.PlayerID)
+	if err != nil {
+		slog.Error("Failed to check roster locks", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create game details.",
+		})
+		return
+	}
+	if lockedPlayerID != 0 {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": rosterLockedMessage(lockedPlayerID),
+		})
+		return
+	}
+
+	newDetails, err := h.queries.CreateGameDetails(c.Request.Context(), createGameDetailsRequest.IntoDBModel())
+	if err != nil {
+		status, message := boxScoreError(err, createGameDetailsRequest.PlayerID)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to create game details", "error", err)
+			message = "Failed to create game details."
+		} else {
+			slog.Warn("Rejected game details", "error", err)
+		}
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": newDetails,
+	})
+}
+
+// UpsertGameBoxScore handles PUT requests that submit a game's whole box score.
+// Every row is written in a single transaction, so either the full box score
+// is saved or nothing is.
+func (h *Handler) UpsertGameBoxScore(c *gin.Context) {
+	var upsertBoxScoreRequest models.UpsertBoxScoreRequest
+	if err := c.ShouldBindJSON(&upsertBoxScoreRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for box score.",
+		})
+		return
+	}
+
+	if !h.authorizeGame(c, upsertBoxScoreRequest.GameID) {
+		return
+	}
+
+	seen := make(map[int64]bool, len(upsertBoxScoreRequest.Players))
+	playerIDs := make([]int64, 0, len(upsertBoxScoreRequest.Players))
+	for _, entry := range upsertBoxScoreRequest.Players {
+		if seen[entry.PlayerID] {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Player %d appears more than once in the box score.", entry.PlayerID),
+			})
+			return
+		}
+		seen[entry.PlayerID] = true
+		playerIDs = append(playerIDs, entry.PlayerID)
+	}
+
+	var failedPlayerID, lockedPlayerID int64
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		var err error
+		lockedPlayerID, err = rosterLockedPlayer(c.Request.Context(), q, upsertBoxScoreRequest.GameID, playerIDs...)
+		if err != nil || lockedPlayerID != 0 {
+			return err
+		}
+
+		for _, params := range upsertBoxScoreRequest.IntoDBModels() {
+			if _, err := q.UpsertGameDetails(c.Request.Context(), params); err != nil {
+				failedPlayerID = params.PlayerID
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		status, message := boxScoreError(err, failedPlayerID)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to save box score", "error", err)
+			message = "Failed to save box score."
+		} else {
+			slog.Warn("Rejected box score", "playerId", failedPlayerID, "error", err)
+		}
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+	if lockedPlayerID != 0 {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": rosterLockedMessage(lockedPlayerID),
+		})
+		return
+	}
+
+	boxScore, err := h.queries.ListGameDetailsVerboseByGame(c.Request.Context(), upsertBoxScoreRequest.GameID)
+	if err != nil {
+		slog.Error("Failed to fetch box score", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Box score saved, but failed to fetch it.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": boxScore,
+	})
+}
+
+// UpdateGameDetailsScore handles PATCH requests to correct a player's score in a game
+func (h *Handler) UpdateGameDetailsScore(c *gin.Context) {
+	var updateGameDetailsScoreRequest models.UpdateGameDetailsScoreRequest
+	if err := c.ShouldBindJSON(&updateGameDetailsScoreRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for updating game details score.",
+		})
+		return
+	}
+
+	if !h.authorizeGame(c, updateGameDetailsScoreRequest.GameID) {
+		return
+	}
+
+	updated, err := h.queries.UpdateGameDetailsScore(c.Request.Context(), updateGameDetailsScoreRequest.IntoDBModel())
+	if err != nil {
+		status, message := boxScoreError(err, updateGameDetailsScoreRequest.PlayerID)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to update game details score", "error", err)
+			message = "Failed to update game details score."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	if updated == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "No box score entry found for that player and game.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// DeleteGameDetails handles DELETE requests to remove a player from a game's box score
+func (h *Handler) DeleteGameDetails(c *gin.Context) {
+	gameID, err := strconv.ParseInt(c.Param("gameId"), 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse game id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse game id. Please provide a valid id.",
+		})
+		return
+	}
+
+	playerID, err := strconv.ParseInt(c.Param("playerId"), 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse player id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse player id. Please provide a valid id.",
+		})
+		return
+	}
+
+	if !h.authorizeGame(c, gameID) {
+		return
+	}
+
+	if err := h.queries.DeleteGameDetailsByGameAndPlayer(c.Request.Context(), repository.DeleteGameDetailsByGameAndPlayerParams{
+		GameID:   gameID,
+		PlayerID: playerID,
+	}); err != nil {
+		status, message := boxScoreError(err, playerID)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to delete game details", "error", err)
+			message = "Failed to delete game details."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
@@ -0,0 +1,381 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gbart/fcabl-api/internal/models"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// gameEventError translates a database error for a play-by-play event into a
+// status code and client facing message. A 500 status means the error was
+// unexpected.
+func gameEventError(err error) (int, string) {
+	code, constraint := pgErrorCode(err)
+	switch {
+	case code == pgCheckViolation && constraint == "game_event_team_must_play_in_game":
+		return http.StatusBadRequest, "That team is not playing in this game, or the player is not on it."
+	case code == pgCheckViolation && constraint == "game_event_fields":
+		return http.StatusBadRequest, "Play is missing fields its kind needs, or has fields it doesn't."
+	case code == pgUniqueViolation && constraint == "game_events_undoes_event_id_key":
+		return http.StatusConflict, "That event has already been undone."
+	case code == pgForeignKeyViolation:
+		return http.StatusBadRequest, "Game, team or player does not exist."
+	}
+	return bracketError(err)
+}
+
+// gameScoreError translates a rejected change to a score that comes from a
+// game's play-by-play into a status code and client facing message. A 500
+// status means the error was something else.
+func gameScoreError(err error) (int, string) {
+	code, constraint := pgErrorCode(err)
+	if code == pgCheckViolation && (constraint == "game_score_from_events" || constraint == "game_details_score_from_events") {
+		return http.StatusConflict, "This game's points, fouls and made shots come from its play-by-play. Record or undo plays to change them."
+	}
+	return http.StatusInternalServerError, ""
+}
+
+// gameEventFieldsError checks a play has the fields its kind needs and no
+// others. It returns the client facing message, or "" when the play is valid.
+func gameEventFieldsError(rq models.RecordGameEventRequest) string {
+	switch rq.Kind {
+	case "score":
+		if !rq.PlayerID.Valid {
+			return "A score needs the playerId of the scorer."
+		}
+		if !rq.Points.Valid || rq.Points.Int32 < 1 || rq.Points.Int32 > 3 {
+			return "A score must be worth 1, 2 or 3 points."
+		}
+	case "foul":
+		if !rq.PlayerID.Valid {
+			return "A foul needs the playerId of the player who fouled."
+		}
+	case "substitution":
+		if !rq.PlayerID.Valid || !rq.PlayerOutID.Valid {
+			return "A substitution needs the playerId coming in and the playerOutId going out."
+		}
+		if rq.PlayerID.Int64 == rq.PlayerOutID.Int64 {
+			return "A player cannot be substituted for themselves."
+		}
+	case "timeout":
+		if !rq.TeamID.Valid {
+			return "A timeout needs the teamId that called it."
+		}
+	case "period_start", "period_end":
+		if rq.TeamID.Valid || rq.PlayerID.Valid {
+			return "The start or end of a period has no team or player."
+		}
+	}
+
+	if rq.Points.Valid && rq.Kind != "score" {
+		return "Only scores have points."
+	}
+	if rq.PlayerOutID.Valid && rq.Kind != "substitution" {
+		return "Only substitutions have a playerOutId."
+	}
+	if rq.PlayerID.Valid && rq.Kind == "timeout" {
+		return "A timeout has no player."
+	}
+	return ""
+}
+
+// gameEventPeriodError checks a play fits the game's periods: periods start
+// and end in order, one at a time, and other plays are recorded in a period
+// that has started. Plays in earlier periods can still be added as
+// corrections. started is false when no period has started. It returns the
+// client facing message, or "" when the play fits.
+func gameEventPeriodError(kind string, period int32, current repository.GetCurrentGamePeriodRow, started bool) string {
+	switch kind {
+	case "period_start":
+		if !started {
+			if period != 1 {
+				return "The game starts with period 1."
+			}
+			return ""
+		}
+		if current.Kind == "period_start" {
+			return fmt.Sprintf("Period %d has not ended.", current.Period)
+		}
+		if period != current.Period+1 {
+			return fmt.Sprintf("Period %d starts next.", current.Period+1)
+		}
+	case "period_end":
+		if !started || current.Kind != "period_start" {
+			return "No period is underway."
+		}
+		if period != current.Period {
+			return fmt.Sprintf("Period %d is underway.", current.Period)
+		}
+	default:
+		if !started || period > current.Period {
+			return fmt.Sprintf("Period %d has not started.", period)
+		}
+	}
+	return ""
+}
+
+// syncGameFromEvents derives a game's box score and score from its
+// play-by-play. A completed game also moves its bracket on and updates the
+// standings, as a late correction can change its result.
+func syncGameFromEvents(ctx context.Context, q *repository.Queries, game repository.Game) error {
+	if err := q.CreateGameDetailsFromEvents(ctx, game.ID); err != nil {
+		return err
+	}
+	if err := q.SyncGameDetailsFromEvents(ctx, game.ID); err != nil {
+		return err
+	}
+	if err := q.SyncGameScoreFromEvents(ctx, game.ID); err != nil {
+		return err
+	}
+	if game.Status != "completed" {
+		return nil
+	}
+	if err := advanceBracket(ctx, q, game.ID); err != nil {
+		return err
+	}
+	_, err := recomputeStandings(ctx, q)
+	return err
+}
+
+// ListGameEvents handles GET requests for a game's play-by-play, in game
+// order. Undone events and the undo events themselves are included, with
+// undone set on the events that no longer count.
+func (h *Handler) ListGameEvents(c *gin.Context) {
+	gameIDStr := c.Query("gameId")
+	slog.Info("Starting ListGameEvents", "gameIdStr", gameIDStr)
+
+	if gameIDStr == "" {
+		slog.Warn("Game ID is empty.")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Please provide a game id.",
+		})
+		return
+	}
+
+	gameID, err := strconv.ParseInt(gameIDStr, 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse game id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse game id. Please provide a valid id.",
+		})
+		return
+	}
+
+	events, err := h.queries.ListGameEvents(c.Request.Context(), gameID)
+	if err != nil {
+		slog.Error("Failed to fetch game events", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch game events.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": events,
+	})
+}
+
+// RecordGameEvent handles POST requests to add a play to a game's
+// play-by-play. The game's box score and score are derived again from the
+// log in the same transaction. Starting period 1 puts a scheduled game in
+// progress.
+func (h *Handler) RecordGameEvent(c *gin.Context) {
+	var recordGameEventRequest models.RecordGameEventRequest
+	if err := c.ShouldBindJSON(&recordGameEventRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for game event.",
+		})
+		return
+	}
+	slog.Info("Starting RecordGameEvent", "gameId", recordGameEventRequest.GameID, "kind", recordGameEventRequest.Kind)
+
+	if message := gameEventFieldsError(recordGameEventRequest); message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	if !h.authorizeGame(c, recordGameEventRequest.GameID) {
+		return
+	}
+
+	var created repository.GameEvent
+	status, message := http.StatusOK, ""
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		// Locked so plays recorded at once are checked against each other
+		game, err := q.GetGameByIdForUpdate(c.Request.Context(), recordGameEventRequest.GameID)
+		if err == pgx.ErrNoRows {
+			status, message = http.StatusNotFound, "Game not found."
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		hasEvents, err := q.GameHasEvents(c.Request.Context(), game.ID)
+		if err != nil {
+			return err
+		}
+		// The log would replace a score entered by hand
+		if !hasEvents && (game.Status == "completed" || game.HomeScore != 0 || game.AwayScore != 0) {
+			status, message = http.StatusConflict, "This game's score was entered by hand, so it cannot be given a play-by-play."
+			return nil
+		}
+
+		current, err := q.GetCurrentGamePeriod(c.Request.Context(), game.ID)
+		if err != nil && err != pgx.ErrNoRows {
+			return err
+		}
+		if message = gameEventPeriodError(recordGameEventRequest.Kind, recordGameEventRequest.Period, current, err == nil); message != "" {
+			status = http.StatusConflict
+			return nil
+		}
+
+		params := recordGameEventRequest.IntoDBModel(c.GetInt64("userID"))
+		if params.PlayerID.Valid {
+			player, err := q.GetPlayerById(c.Request.Context(), params.PlayerID.Int64)
+			if err == pgx.ErrNoRows {
+				status, message = http.StatusBadRequest, fmt.Sprintf("Player %d does not exist.", params.PlayerID.Int64)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if !params.TeamID.Valid {
+				if !player.TeamID.Valid {
+					status, message = http.StatusBadRequest, fmt.Sprintf("Player %d is not on a team. Please provide the team id.", player.ID)
+					return nil
+				}
+				params.TeamID = player.TeamID
+			}
+
+			lockedPlayerID, err := rosterLockedPlayer(c.Request.Context(), q, game.ID, player.ID)
+			if err != nil {
+				return err
+			}
+			if lockedPlayerID != 0 {
+				status, message = http.StatusForbidden, rosterLockedMessage(lockedPlayerID)
+				return nil
+			}
+		}
+
+		created, err = q.CreateGameEvent(c.Request.Context(), params)
+		if err != nil {
+			return err
+		}
+		if created.Kind == "period_start" && created.Period == 1 {
+			if err := q.StartGame(c.Request.Context(), game.ID); err != nil {
+				return err
+			}
+		}
+		return syncGameFromEvents(c.Request.Context(), q, game)
+	})
+	if err != nil {
+		status, message = gameEventError(err)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to record game event", "error", err)
+			message = "Failed to record game event."
+		} else {
+			slog.Warn("Rejected game event", "error", err)
+		}
+	}
+	if message != "" {
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": created,
+	})
+}
+
+// UndoGameEvent handles POST requests to undo a play recorded by mistake. The
+// play stays in the log, an undo event is added for it at the same game clock
+// and the game's box score and score are derived again.
+func (h *Handler) UndoGameEvent(c *gin.Context) {
+	var undoGameEventRequest models.UndoGameEventRequest
+	if err := c.ShouldBindJSON(&undoGameEventRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for undoing game event.",
+		})
+		return
+	}
+	slog.Info("Starting UndoGameEvent", "eventId", undoGameEventRequest.EventID)
+
+	event, err := h.queries.GetGameEvent(c.Request.Context(), undoGameEventRequest.EventID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Game event not found.",
+			})
+		} else {
+			slog.Error("Error retrieving game event", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Error retrieving game event.",
+			})
+		}
+		return
+	}
+
+	if !h.authorizeGame(c, event.GameID) {
+		return
+	}
+
+	if event.Kind == "undo" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "An undo cannot be undone. Record the play again instead.",
+		})
+		return
+	}
+
+	var created repository.GameEvent
+	err = h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		game, err := q.GetGameByIdForUpdate(c.Request.Context(), event.GameID)
+		if err != nil {
+			return err
+		}
+
+		created, err = q.CreateGameEvent(c.Request.Context(), repository.CreateGameEventParams{
+			GameID:        event.GameID,
+			Kind:          "undo",
+			Period:        event.Period,
+			ClockSeconds:  event.ClockSeconds,
+			UndoesEventID: pgtype.Int8{Int64: event.ID, Valid: true},
+			CreatedBy:     pgtype.Int8{Int64: c.GetInt64("userID"), Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		return syncGameFromEvents(c.Request.Context(), q, game)
+	})
+	if err != nil {
+		status, message := gameEventError(err)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to undo game event", "error", err)
+			message = "Failed to undo game event."
+		} else {
+			slog.Warn("Rejected game event undo", "error", err)
+		}
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": created,
+	})
+}
@@ -16,7 +16,12 @@ import (
 
 // ListGames handles GET requests to list all games
 func (h *Handler) ListGames(c *gin.Context) {
-	games, err := h.queries.ListGames(c.Request.Context())
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	games, err := h.queries.ListGames(c.Request.Context(), repository.ListGamesParams(scope))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			slog.Warn("No games found.")
@@ -93,9 +98,13 @@ func (h *Handler) CreateGame(c *gin.Context) {
 
 	newGame, err := h.queries.CreateGame(c.Request.Context(), createGameRequest.IntoDBModel())
 	if err != nil {
-		slog.Error("Failed to create game", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to create game.",
+		status, message := seasonConstraintError(err)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to create game", "error", err)
+			message = "Failed to create game."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
 		})
 		return
 	}
@@ -116,10 +125,30 @@ func (h *Handler) UpdateGame(c *gin.Context) {
 		return
 	}
 
-	if err := h.queries.UpdateGame(c.Request.Context(), updateGameRequest.IntoDBModel()); err != nil {
-		slog.Error("Failed to update game", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to update game.",
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		if err := q.UpdateGame(c.Request.Context(), updateGameRequest.IntoDBModel()); err != nil {
+			return err
+		}
+		if err := advanceBracket(c.Request.Context(), q, updateGameRequest.ID); err != nil {
+			return err
+		}
+		_, err := recomputeStandings(c.Request.Context(), q)
+		return err
+	})
+	if err != nil {
+		status, message := bracketError(err)
+		if status == http.StatusInternalServerError {
+			status, message = seasonConstraintError(err)
+		}
+		if status == http.StatusInternalServerError {
+			status, message = gameScoreError(err)
+		}
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to update game", "error", err)
+			message = "Failed to update game."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
 		})
 		return
 	}
@@ -140,7 +169,14 @@ func (h *Handler) DeleteGame(c *gin.Context) {
 		return
 	}
 
-	if err := h.queries.DeleteGame(c.Request.Context(), gameID); err != nil {
+	err = h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		if err := q.DeleteGame(c.Request.Context(), gameID); err != nil {
+			return err
+		}
+		_, err := recomputeStandings(c.Request.Context(), q)
+		return err
+	})
+	if err != nil {
 		slog.Error("Failed to delete game", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to delete game.",
@@ -153,7 +189,12 @@ func (h *Handler) DeleteGame(c *gin.Context) {
 
 // ListUpcomingGames handles GET requests to list upcoming games
 func (h *Handler) ListUpcomingGames(c *gin.Context) {
-	games, err := h.queries.ListUpcomingGames(c.Request.Context())
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	games, err := h.queries.ListUpcomingGames(c.Request.Context(), repository.ListUpcomingGamesParams(scope))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			slog.Warn("No upcoming games found.")
@@ -176,7 +217,12 @@ func (h *Handler) ListUpcomingGames(c *gin.Context) {
 
 // ListPastGames handles GET requests to list past games
 func (h *Handler) ListPastGames(c *gin.Context) {
-	games, err := h.queries.ListPastGames(c.Request.Context())
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	games, err := h.queries.ListPastGames(c.Request.Context(), repository.ListPastGamesParams(scope))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			slog.Warn("No past games found.")
@@ -300,7 +346,12 @@ type GameWithDetailsResponse struct {
 
 // ListGamesWithTeams handles GET requests to list all games with team details
 func (h *Handler) ListGamesWithTeams(c *gin.Context) {
-	games, err := h.queries.ListGamesWithTeams(c.Request.Context())
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	games, err := h.queries.ListGamesWithTeams(c.Request.Context(), repository.ListGamesWithTeamsParams(scope))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			slog.Warn("No games found.")
@@ -375,7 +426,23 @@ func (h *Handler) ListTeamSchedule(c *gin.Context) {
 		return
 	}
 
-	gameDetails, err := h.queries.ListGameDetailsVerbose(c.Request.Context())
+	team, err := h.queries.GetTeamById(c.Request.Context(), teamID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			slog.Warn("No team found.")
+			c.JSON(http.StatusOK, gin.H{
+				"data": []repository.ListTeamScheduleRow{},
+			})
+		} else {
+			slog.Error("Error retrieving team", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Error retrieving team.",
+			})
+		}
+		return
+	}
+
+	gameDetails, err := h.queries.ListGameDetailsVerbose(c.Request.Context(), team.SeasonID)
 	if err != nil {
 		slog.Error("Failed to fetch schedules", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -408,13 +475,7 @@ func (h *Handler) ListTeamSchedule(c *gin.Context) {
 		homeStats := slices.Collect(func(yield func(models.PlayerGameStats) bool) {
 			for _, detail := range gameDetails {
 				if detail.GameID == game.ID && game.HomeTeamID == detail.TeamID {
-					if !yield(models.PlayerGameStats{
-						PlayerID:        detail.PlayerID,
-						PlayerFirstName: detail.FirstName,
-						PlayerLastName:  detail.LastName,
-						Number:          detail.JerseyNumber,
-						Score:           detail.Score,
-					}) {
+					if !yield(models.NewPlayerGameStats(detail)) {
 						return
 					}
 				}
@@ -423,13 +484,7 @@ func (h *Handler) ListTeamSchedule(c *gin.Context) {
 		awayStats := slices.Collect(func(yield func(models.PlayerGameStats) bool) {
 			for _, detail := range gameDetails {
 				if detail.GameID == game.ID && game.AwayTeamID == detail.TeamID {
-					if !yield(models.PlayerGameStats{
-						PlayerID:        detail.PlayerID,
-						PlayerFirstName: detail.FirstName,
-						PlayerLastName:  detail.LastName,
-						Number:          detail.JerseyNumber,
-						Score:           detail.Score,
-					}) {
+					if !yield(models.NewPlayerGameStats(detail)) {
 						return
 					}
 				}
@@ -445,7 +500,12 @@ func (h *Handler) ListTeamSchedule(c *gin.Context) {
 
 // ListAllSchedules handles GET requests to list all schedules
 func (h *Handler) ListAllSchedules(c *gin.Context) {
-	gameDetails, err := h.queries.ListGameDetailsVerbose(c.Request.Context())
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	gameDetails, err := h.queries.ListGameDetailsVerbose(c.Request.Context(), scope.SeasonID)
 	if err != nil {
 		slog.Error("Failed to fetch schedules", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -454,7 +514,7 @@ func (h *Handler) ListAllSchedules(c *gin.Context) {
 		return
 	}
 
-	schedules, err := h.queries.ListGamesWithTeams(c.Request.Context())
+	schedules, err := h.queries.ListGamesWithTeams(c.Request.Context(), repository.ListGamesWithTeamsParams(scope))
 	if err != nil {
 		slog.Error("Failed to fetch schedules", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -470,13 +530,7 @@ func (h *Handler) ListAllSchedules(c *gin.Context) {
 		homeStats := slices.Collect(func(yield func(models.PlayerGameStats) bool) {
 			for _, detail := range gameDetails {
 				if detail.GameID == game.ID && game.HomeTeamID == detail.TeamID {
-					if !yield(models.PlayerGameStats{
-						PlayerID:        detail.PlayerID,
-						PlayerFirstName: detail.FirstName,
-						PlayerLastName:  detail.LastName,
-						Number:          detail.JerseyNumber,
-						Score:           detail.Score,
-					}) {
+					if !yield(models.NewPlayerGameStats(detail)) {
 						return
 					}
 				}
@@ -485,13 +539,7 @@ func (h *Handler) ListAllSchedules(c *gin.Context) {
 		awayStats := slices.Collect(func(yield func(models.PlayerGameStats) bool) {
 			for _, detail := range gameDetails {
 				if detail.GameID == game.ID && game.AwayTeamID == detail.TeamID {
-					if !yield(models.PlayerGameStats{
-						PlayerID:        detail.PlayerID,
-						PlayerFirstName: detail.FirstName,
-						PlayerLastName:  detail.LastName,
-						Number:          detail.JerseyNumber,
-						Score:           detail.Score,
-					}) {
+					if !yield(models.NewPlayerGameStats(detail)) {
 						return
 					}
 				}
@@ -528,6 +576,8 @@ func (h *Handler) UpdateGameTime(c *gin.Context) {
 }
 
 // UpdateGameScoreAndStatus handles PUT requests to update a game's score and status.
+// Team standings are recomputed in the same transaction, so they always match
+// the completed games.
 func (h *Handler) UpdateGameScoreAndStatus(c *gin.Context) {
 	var updateGameScoreAndStatusRequest models.UpdateGameScoreAndStatusRequest
 	if err := c.ShouldBindJSON(&updateGameScoreAndStatusRequest); err != nil {
@@ -538,10 +588,31 @@ func (h *Handler) UpdateGameScoreAndStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.queries.UpdateGameScoreAndStatus(c.Request.Context(), updateGameScoreAndStatusRequest.IntoDBModel()); err != nil {
-		slog.Error("Failed to update game score and status", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to update game score and status.",
+	if !h.authorizeGame(c, updateGameScoreAndStatusRequest.ID) {
+		return
+	}
+
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		if err := q.UpdateGameScoreAndStatus(c.Request.Context(), updateGameScoreAndStatusRequest.IntoDBModel()); err != nil {
+			return err
+		}
+		if err := advanceBracket(c.Request.Context(), q, updateGameScoreAndStatusRequest.ID); err != nil {
+			return err
+		}
+		_, err := recomputeStandings(c.Request.Context(), q)
+		return err
+	})
+	if err != nil {
+		status, message := bracketError(err)
+		if status == http.StatusInternalServerError {
+			status, message = gameScoreError(err)
+		}
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to update game score and status", "error", err)
+			message = "Failed to update game score and status."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
 		})
 		return
 	}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gbart/fcabl-api/internal/live"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// streamKeepAlive is how often a comment is sent on an idle stream, so proxies
+// don't close it
+const streamKeepAlive = 20 * time.Second
+
+// streamRetryMs is how long browsers wait before reconnecting a dropped stream
+const streamRetryMs = 3000
+
+// StreamGame handles GET requests to follow a game live over Server-Sent
+// Events. Sends a connected event, then score, status and box_score events as
+// they are recorded, each with the game's state or the player's line as JSON.
+// Clients reconnecting with the Last-Event-ID header, or the lastEventId query
+// parameter, are sent the events they missed first.
+func (h *Handler) StreamGame(c *gin.Context) {
+	gameIDStr := c.Query("id")
+	slog.Info("Starting StreamGame", "gameIdStr", gameIDStr)
+
+	gameID, err := strconv.ParseInt(gameIDStr, 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse game id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse game id. Please provide a valid id.",
+		})
+		return
+	}
+
+	if _, err := h.queries.GetGameById(c.Request.Context(), gameID); err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Game not found.",
+			})
+		} else {
+			slog.Error("Error retrieving game", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Error retrieving game.",
+			})
+		}
+		return
+	}
+
+	h.streamGameEvents(c, live.Filter{GameID: pgtype.Int8{Int64: gameID, Valid: true}})
+}
+
+// StreamGameDay handles GET requests to follow every game on a day live over
+// Server-Sent Events, the same as StreamGame. The date query parameter
+// (YYYY-MM-DD) defaults to today.
+func (h *Handler) StreamGameDay(c *gin.Context) {
+	dateStr := c.DefaultQuery("date", time.Now().Format(time.DateOnly))
+	slog.Info("Starting StreamGameDay", "date", dateStr)
+
+	date, err := time.Parse(time.DateOnly, dateStr)
+	if err != nil {
+		slog.Error("Failed to parse date", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse date. Please provide a date as YYYY-MM-DD.",
+		})
+		return
+	}
+
+	h.streamGameEvents(c, live.Filter{Date: pgtype.Date{Time: date, Valid: true}})
+}
+
+// streamGameEvents streams the events matching filter until the client goes
+// away. It subscribes before catching up on missed events, so none recorded in
+// between are lost, and skips live events already sent while catching up.
+func (h *Handler) streamGameEvents(c *gin.Context, filter live.Filter) {
+	lastIDStr := c.GetHeader("Last-Event-ID")
+	if lastIDStr == "" {
+		lastIDStr = c.Query("lastEventId")
+	}
+	var lastID int64
+	if lastIDStr != "" {
+		id, err := strconv.ParseInt(lastIDStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Failed to parse last event id. Please provide a valid id.",
+			})
+			return
+		}
+		lastID = id
+	}
+
+	sub := h.live.Subscribe(filter)
+	defer sub.Close()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Stops nginx buffering the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Render(http.StatusOK, sse.Event{Retry: streamRetryMs, Event: "connected", Data: "{}"})
+	c.Writer.Flush()
+
+	// Once streaming an error can only end the stream, the client reconnects
+	// and catches up from the last event it got. The events sent while
+	// catching up are remembered, the subscription may deliver them again.
+	sent := map[int64]struct{}{}
+	for catchingUp := lastID > 0; catchingUp; {
+		missed, err := h.queries.ListGameStreamEventsSince(c.Request.Context(), filter.Params(lastID))
+		if err != nil {
+			slog.Error("Failed to fetch missed game events", "error", err)
+			return
+		}
+		for _, event := range missed {
+			c.Render(-1, streamEvent(event))
+			sent[event.ID] = struct{}{}
+			lastID = event.ID
+		}
+		c.Writer.Flush()
+		catchingUp = len(missed) == live.MissedEventsPage
+	}
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-keepAlive.C:
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		case event, ok := <-sub.C:
+			if !ok {
+				// Fell behind, the client reconnects and catches up
+				return false
+			}
+			if _, ok := sent[event.ID]; ok {
+				delete(sent, event.ID)
+				return true
+			}
+			if event.ID > lastID {
+				// Everything sent while catching up has been passed
+				clear(sent)
+			}
+			c.Render(-1, streamEvent(event))
+			return true
+		}
+	})
+}
+
+// streamEvent is the Server-Sent Event for a game event
+func streamEvent(event repository.GameStreamEvent) sse.Event {
+	return sse.Event{
+		Id:    strconv.FormatInt(event.ID, 10),
+		Event: event.Kind,
+		Data:  event.Payload,
+	}
+}
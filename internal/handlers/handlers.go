@@ -4,24 +4,73 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+
 	"github.com/gbart/fcabl-api/internal/auth"
+	"github.com/gbart/fcabl-api/internal/checkout"
 	"github.com/gbart/fcabl-api/internal/config"
 	"github.com/gbart/fcabl-api/internal/db"
+	"github.com/gbart/fcabl-api/internal/live"
+	"github.com/gbart/fcabl-api/internal/mailer"
 	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // Handler holds dependencies for all HTTP handlers
 type Handler struct {
+	db         *pgxpool.Pool
 	queries    *repository.Queries
 	jwtService *auth.JWTService
 	config     *config.Config
+	mailer     mailer.Mailer
+	checkout   checkout.Provider
+	live       *live.Hub
 }
 
 // NewHandler creates a new Handler instance with the provided database connection
-func NewHandler(pg *db.Postgres, jwtService *auth.JWTService, cfg *config.Config) *Handler {
+func NewHandler(pg *db.Postgres, jwtService *auth.JWTService, cfg *config.Config, mail mailer.Mailer, payments checkout.Provider, hub *live.Hub) *Handler {
 	return &Handler{
+		db:         pg.DB,
 		queries:    repository.New(pg.DB),
 		jwtService: jwtService,
 		config:     cfg,
+		mailer:     mail,
+		checkout:   payments,
+		live:       hub,
+	}
+}
+
+// withTx runs fn inside a database transaction. The transaction is committed
+// if fn returns nil and rolled back otherwise.
+func (h *Handler) withTx(ctx context.Context, fn func(q *repository.Queries) error) error {
+	tx, err := h.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(h.queries.WithTx(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Postgres error codes used to turn constraint violations into client errors
+const (
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+	pgCheckViolation      = "23514"
+)
+
+// pgErrorCode returns the Postgres error code and constraint name wrapped in err,
+// or empty strings if err did not come from Postgres.
+func pgErrorCode(err error) (code string, constraint string) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code, pgErr.ConstraintName
 	}
+	return "", ""
 }
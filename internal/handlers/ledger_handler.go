@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gbart/fcabl-api/internal/models"
+	"github.com/gbart/fcabl-api/internal/money"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Ledger accounts, stored in ledger_entries.account. Players each have their
+// own receivable account, the others are the league's.
+const (
+	accountCash       = "cash"
+	accountReceivable = "receivable"
+	accountRevenue    = "revenue"
+	accountDiscounts  = "discounts"
+	accountWriteOffs  = "write_offs"
+)
+
+// accountTypes are the types of the ledger accounts, shown on the trial balance
+var accountTypes = map[string]string{
+	accountCash:       "asset",
+	accountReceivable: "asset",
+	accountRevenue:    "revenue",
+	accountDiscounts:  "expense",
+	accountWriteOffs:  "expense",
+}
+
+// GetPlayerLedger handles GET requests for a player's ledger account, every
+// transaction that changed what they owe with the balance after it
+func (h *Handler) GetPlayerLedger(c *gin.Context) {
+	playerIDStr := c.Query("playerId")
+	slog.Info("Starting GetPlayerLedger", "playerIdStr", playerIDStr)
+
+	playerID, err := strconv.ParseInt(playerIDStr, 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse player id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse player id. Please provide a valid id.",
+		})
+		return
+	}
+
+	transactions, err := h.queries.ListPlayerLedger(c.Request.Context(), playerID)
+	if err != nil {
+		slog.Error("Failed to fetch player ledger", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch player ledger.",
+		})
+		return
+	}
+
+	ledger := models.PlayerLedger{
+		PlayerID:     playerID,
+		Balance:      money.ToNumeric(0),
+		Transactions: transactions,
+	}
+	if len(transactions) > 0 {
+		ledger.Balance = transactions[len(transactions)-1].Balance
+	} else {
+		ledger.Transactions = []repository.ListPlayerLedgerRow{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": ledger,
+	})
+}
+
+// ListLedgerTransactions handles GET requests for a season's ledger
+// transactions, newest first. Can be filtered with the kind query parameter.
+func (h *Handler) ListLedgerTransactions(c *gin.Context) {
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	params := repository.ListLedgerTransactionsParams{SeasonID: scope.SeasonID}
+	if kind := c.Query("kind"); kind != "" {
+		params.Kind = pgtype.Text{String: kind, Valid: true}
+	}
+
+	transactions, err := h.queries.ListLedgerTransactions(c.Request.Context(), params)
+	if err != nil {
+		slog.Error("Failed to fetch ledger transactions", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch ledger transactions.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": transactions,
+	})
+}
+
+// GetTrialBalance handles GET requests for the trial balance of a season's
+// books, with the balance of every player account that is not settled. The
+// asOf query parameter (YYYY-MM-DD) limits it to transactions on or before
+// that day.
+func (h *Handler) GetTrialBalance(c *gin.Context) {
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	var asOf pgtype.Date
+	var before pgtype.Timestamp
+	if asOfStr := c.Query("asOf"); asOfStr != "" {
+		date, err := time.Parse(time.DateOnly, asOfStr)
+		if err != nil {
+			slog.Error("Failed to parse as of date", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Failed to parse as of date. Please provide a date as YYYY-MM-DD.",
+			})
+			return
+		}
+		asOf = pgtype.Date{Time: date, Valid: true}
+		before = pgtype.Timestamp{Time: date.AddDate(0, 0, 1), Valid: true}
+	}
+	slog.Info("Starting GetTrialBalance", "seasonId", scope.SeasonID, "asOf", asOf.Time)
+
+	rows, err := h.queries.GetTrialBalance(c.Request.Context(), repository.GetTrialBalanceParams{
+		SeasonID: scope.SeasonID,
+		Before:   before,
+	})
+	if err != nil {
+		slog.Error("Failed to fetch trial balance", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch trial balance.",
+		})
+		return
+	}
+
+	receivables, err := h.queries.ListLedgerPlayerBalances(c.Request.Context(), repository.ListLedgerPlayerBalancesParams{
+		SeasonID: scope.SeasonID,
+		Before:   before,
+	})
+	if err != nil {
+		slog.Error("Failed to fetch player balances", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch trial balance.",
+		})
+		return
+	}
+	if receivables == nil {
+		receivables = []repository.ListLedgerPlayerBalancesRow{}
+	}
+
+	var totalDebits, totalCredits int64
+	accounts := make([]models.TrialBalanceAccount, 0, len(rows))
+	for _, row := range rows {
+		debits, credits := money.FromNumeric(row.Debits), money.FromNumeric(row.Credits)
+		totalDebits += debits
+		totalCredits += credits
+		accounts = append(accounts, models.TrialBalanceAccount{
+			Account: row.Account,
+			Type:    accountTypes[row.Account],
+			Debits:  row.Debits,
+			Credits: row.Credits,
+			Balance: money.ToNumeric(debits - credits),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": models.TrialBalance{
+			SeasonID:     scope.SeasonID,
+			AsOf:         asOf,
+			Accounts:     accounts,
+			TotalDebits:  money.ToNumeric(totalDebits),
+			TotalCredits: money.ToNumeric(totalCredits),
+			Balanced:     totalDebits == totalCredits,
+			Receivables:  receivables,
+		},
+	})
+}
+
+// WriteOff handles POST requests to write off some or all of what a player
+// owes, e.g. a fee the league has waived or given up collecting. The player
+// is fully registered once nothing is left.
+func (h *Handler) WriteOff(c *gin.Context) {
+	var writeOffRequest models.WriteOffRequest
+	if err := c.ShouldBindJSON(&writeOffRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for writing off balance.",
+		})
+		return
+	}
+	slog.Info("Starting WriteOff", "playerId", writeOffRequest.PlayerID)
+
+	amount := money.FromNumeric(writeOffRequest.Amount)
+	var transaction repository.LedgerTransaction
+	status, message := http.StatusOK, ""
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		// Locks the player so concurrent write-offs can't together write
+		// off more than is owed
+		player, err := q.GetPlayerByIdForUpdate(c.Request.Context(), writeOffRequest.PlayerID)
+		if err == pgx.ErrNoRows {
+			status, message = http.StatusNotFound, "Player not found."
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// Brings the balance up to date before checking it
+		if err := reconcilePlayer(c.Request.Context(), q, player.ID); err != nil {
+			return err
+		}
+		balance, err := q.GetPlayerLedgerBalance(c.Request.Context(), player.ID)
+		if err != nil {
+			return err
+		}
+		owed := money.FromNumeric(balance)
+		if amount <= 0 || amount > owed {
+			status, message = http.StatusBadRequest, "Write-off amount must be more than $0.00 and at most the "+money.Format(max(owed, 0))+" the player owes."
+			return nil
+		}
+
+		transaction, err = q.CreateLedgerTransaction(c.Request.Context(), repository.CreateLedgerTransactionParams{
+			Kind:        "write_off",
+			PlayerID:    player.ID,
+			SeasonID:    player.SeasonID,
+			Amount:      money.ToNumeric(amount),
+			Description: writeOffRequest.Reason,
+			CreatedBy:   pgtype.Int8{Int64: c.GetInt64("userID"), Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		if err := q.CreateLedgerEntry(c.Request.Context(), repository.CreateLedgerEntryParams{
+			TransactionID: transaction.ID,
+			Account:       accountWriteOffs,
+			Amount:        money.ToNumeric(amount),
+		}); err != nil {
+			return err
+		}
+		if err := q.CreateLedgerEntry(c.Request.Context(), repository.CreateLedgerEntryParams{
+			TransactionID: transaction.ID,
+			Account:       accountReceivable,
+			PlayerID:      pgtype.Int8{Int64: player.ID, Valid: true},
+			Amount:        money.ToNumeric(-amount),
+		}); err != nil {
+			return err
+		}
+
+		return reconcilePlayer(c.Request.Context(), q, player.ID)
+	})
+	if err != nil {
+		slog.Error("Failed to write off balance", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to write off balance.",
+		})
+		return
+	}
+	if status != http.StatusOK {
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	slog.Info("Wrote off balance", "playerId", writeOffRequest.PlayerID, "amount", money.Format(amount))
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": transaction,
+	})
+}
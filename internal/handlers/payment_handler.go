@@ -13,7 +13,12 @@ import (
 
 // ListPayments handles GET requests to list all payments
 func (h *Handler) ListPayments(c *gin.Context) {
-	payments, err := h.queries.ListPayments(c.Request.Context())
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	payments, err := h.queries.ListPayments(c.Request.Context(), repository.ListPaymentsParams(scope))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			slog.Warn("No payments found.")
@@ -113,7 +118,21 @@ func (h *Handler) CreatePayment(c *gin.Context) {
 		return
 	}
 
-	newPayment, err := h.queries.CreatePayment(c.Request.Context(), createPaymentRequest.IntoDBModel())
+	var newPayment repository.Payment
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		var err error
+		newPayment, err = q.CreatePayment(c.Request.Context(), createPaymentRequest.IntoDBModel())
+		if err != nil {
+			return err
+		}
+		return reconcilePlayer(c.Request.Context(), q, newPayment.PlayerID)
+	})
+	if code, constraint := pgErrorCode(err); code == pgUniqueViolation && constraint == "payments_stripe_id_key" {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "A payment with this Stripe id has already been recorded.",
+		})
+		return
+	}
 	if err != nil {
 		slog.Error("Failed to create payment", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -127,7 +146,8 @@ func (h *Handler) CreatePayment(c *gin.Context) {
 	})
 }
 
-// UpdatePaymentStatus handles PATCH requests to update a payment's status
+// UpdatePaymentStatus handles PATCH requests to update a payment's status. The
+// player's registration balance is reconciled in the same transaction.
 func (h *Handler) UpdatePaymentStatus(c *gin.Context) {
 	var updatePaymentStatusRequest models.UpdatePaymentStatusRequest
 	if err := c.ShouldBindJSON(&updatePaymentStatusRequest); err != nil {
@@ -138,7 +158,25 @@ func (h *Handler) UpdatePaymentStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.queries.UpdatePaymentStatus(c.Request.Context(), updatePaymentStatusRequest.IntoDBModel()); err != nil {
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		playerID, err := q.UpdatePaymentStatus(c.Request.Context(), updatePaymentStatusRequest.IntoDBModel())
+		if err != nil {
+			return err
+		}
+		// A completed payment that had been refunded in full is still refunded
+		if err := q.SyncPaymentRefunds(c.Request.Context(), updatePaymentStatusRequest.ID); err != nil {
+			return err
+		}
+		return reconcilePlayer(c.Request.Context(), q, playerID)
+	})
+	if err == pgx.ErrNoRows {
+		slog.Warn("Payment not found", "paymentId", updatePaymentStatusRequest.ID)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Payment not found.",
+		})
+		return
+	}
+	if err != nil {
 		slog.Error("Failed to update payment status", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to update payment status.",
@@ -162,7 +200,21 @@ func (h *Handler) DeletePayment(c *gin.Context) {
 		return
 	}
 
-	if err := h.queries.DeletePayment(c.Request.Context(), paymentID); err != nil {
+	err = h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		playerID, err := q.DeletePayment(c.Request.Context(), paymentID)
+		if err != nil {
+			return err
+		}
+		return reconcilePlayer(c.Request.Context(), q, playerID)
+	})
+	if err == pgx.ErrNoRows {
+		slog.Warn("Payment not found", "paymentId", paymentID)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Payment not found.",
+		})
+		return
+	}
+	if err != nil {
 		slog.Error("Failed to delete payment", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to delete payment.",
@@ -229,7 +281,16 @@ func (h *Handler) ListPaymentsByStatus(c *gin.Context) {
 		return
 	}
 
-	payments, err := h.queries.ListPaymentsByStatus(c.Request.Context(), status)
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	payments, err := h.queries.ListPaymentsByStatus(c.Request.Context(), repository.ListPaymentsByStatusParams{
+		SeasonID:   scope.SeasonID,
+		DivisionID: scope.DivisionID,
+		Status:     status,
+	})
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			slog.Warn("No payments found with status.")
@@ -295,7 +356,12 @@ func (h *Handler) GetPaymentWithPlayer(c *gin.Context) {
 
 // ListPaymentsWithPlayerInfo handles GET requests to list all payments with player info
 func (h *Handler) ListPaymentsWithPlayerInfo(c *gin.Context) {
-	payments, err := h.queries.ListPaymentsWithPlayerInfo(c.Request.Context())
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	payments, err := h.queries.ListPaymentsWithPlayerInfo(c.Request.Context(), repository.ListPaymentsWithPlayerInfoParams(scope))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			slog.Warn("No payments found.")
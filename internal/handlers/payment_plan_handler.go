@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gbart/fcabl-api/internal/models"
+	"github.com/gbart/fcabl-api/internal/money"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// syncPaymentPlan applies the player's payments since their active payment
+// plan started to its installments in order, and completes the plan once every
+// installment is paid or the player owes nothing more. Players without an
+// active plan are left alone.
+func syncPaymentPlan(ctx context.Context, q *repository.Queries, playerID int64) error {
+	if _, err := q.SyncPaymentPlanInstallments(ctx, playerID); err != nil {
+		return err
+	}
+	_, err := q.CompletePaymentPlans(ctx, playerID)
+	return err
+}
+
+// paymentPlan loads a payment plan with its installments
+func paymentPlan(ctx context.Context, q *repository.Queries, plan repository.PaymentPlan) (models.PaymentPlan, error) {
+	installments, err := q.ListPaymentPlanInstallments(ctx, plan.ID)
+	if err != nil {
+		return models.PaymentPlan{}, err
+	}
+	if installments == nil {
+		installments = []repository.PaymentPlanInstallment{}
+	}
+	return models.PaymentPlan{PaymentPlan: plan, Installments: installments}, nil
+}
+
+// CreatePaymentPlan handles POST requests to put a player on a payment plan.
+// The installments are numbered in due date order and must add up to what the
+// player still owes. Payments already made do not count towards the plan.
+func (h *Handler) CreatePaymentPlan(c *gin.Context) {
+	var createPlanRequest models.CreatePaymentPlanRequest
+	if err := c.ShouldBindJSON(&createPlanRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for creating payment plan.",
+		})
+		return
+	}
+	slog.Info("Starting CreatePaymentPlan", "playerId", createPlanRequest.PlayerID)
+
+	installments := slices.Clone(createPlanRequest.Installments)
+	slices.SortStableFunc(installments, func(a, b models.InstallmentRequest) int {
+		return a.DueDate.Time.Compare(b.DueDate.Time)
+	})
+	var total int64
+	for _, installment := range installments {
+		amount := money.FromNumeric(installment.Amount)
+		if amount <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Installment amounts must be more than $0.00.",
+			})
+			return
+		}
+		total += amount
+	}
+
+	var plan models.PaymentPlan
+	status, message := http.StatusOK, ""
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		if err := reconcilePlayer(c.Request.Context(), q, createPlanRequest.PlayerID); err != nil {
+			return err
+		}
+		player, err := q.GetPlayerById(c.Request.Context(), createPlanRequest.PlayerID)
+		if err == pgx.ErrNoRows {
+			status, message = http.StatusNotFound, "Player not found."
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		due := money.FromNumeric(player.RegistrationFeeDue)
+		if due <= 0 {
+			status, message = http.StatusBadRequest, "Player has no registration fee due."
+			return nil
+		}
+		if total != due {
+			status, message = http.StatusBadRequest, "Installments must add up to the "+money.Format(due)+" the player owes."
+			return nil
+		}
+
+		paid, err := q.GetPlayerNetPaid(c.Request.Context(), player.ID)
+		if err != nil {
+			return err
+		}
+		created, err := q.CreatePaymentPlan(c.Request.Context(), repository.CreatePaymentPlanParams{
+			PlayerID:       player.ID,
+			TotalAmount:    money.ToNumeric(total),
+			PaidBeforePlan: paid,
+			Notes:          createPlanRequest.Notes,
+			CreatedBy:      pgtype.Int8{Int64: c.GetInt64("userID"), Valid: true},
+		})
+		if code, constraint := pgErrorCode(err); code == pgUniqueViolation && constraint == "unique_active_payment_plan_per_player" {
+			status, message = http.StatusConflict, "Player already has an active payment plan."
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for i, installment := range installments {
+			_, err := q.CreatePaymentPlanInstallment(c.Request.Context(), repository.CreatePaymentPlanInstallmentParams{
+				PlanID:            created.ID,
+				InstallmentNumber: int32(i + 1),
+				Amount:            installment.Amount,
+				DueDate:           installment.DueDate,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		// Marks installments already past their due date as overdue
+		if err := syncPaymentPlan(c.Request.Context(), q, player.ID); err != nil {
+			return err
+		}
+
+		plan, err = paymentPlan(c.Request.Context(), q, created)
+		return err
+	})
+	if err != nil {
+		slog.Error("Failed to create payment plan", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create payment plan.",
+		})
+		return
+	}
+	if status != http.StatusOK {
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": plan,
+	})
+}
+
+// GetPaymentPlan handles GET requests for a payment plan and its installments
+func (h *Handler) GetPaymentPlan(c *gin.Context) {
+	idStr := c.Query("id")
+	slog.Info("Starting GetPaymentPlan", "idStr", idStr)
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse payment plan id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse payment plan id. Please provide a valid id.",
+		})
+		return
+	}
+
+	plan, err := h.queries.GetPaymentPlanById(c.Request.Context(), id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Payment plan not found.",
+			})
+		} else {
+			slog.Error("Failed to fetch payment plan", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to fetch payment plan.",
+			})
+		}
+		return
+	}
+
+	detail, err := paymentPlan(c.Request.Context(), h.queries, plan)
+	if err != nil {
+		slog.Error("Failed to fetch payment plan installments", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch payment plan.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": detail,
+	})
+}
+
+// ListPaymentPlansByPlayer handles GET requests to list a player's payment
+// plans, newest first, with their installments
+func (h *Handler) ListPaymentPlansByPlayer(c *gin.Context) {
+	playerIDStr := c.Query("playerId")
+	slog.Info("Starting ListPaymentPlansByPlayer", "playerIdStr", playerIDStr)
+
+	playerID, err := strconv.ParseInt(playerIDStr, 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse player id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse player id. Please provide a valid id.",
+		})
+		return
+	}
+
+	plans, err := h.playerPaymentPlans(c.Request.Context(), playerID)
+	if err != nil {
+		slog.Error("Failed to fetch payment plans by player", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch payment plans by player.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": plans,
+	})
+}
+
+// GetMyPaymentPlans handles GET requests from a signed in player for their own
+// payment plans
+func (h *Handler) GetMyPaymentPlans(c *gin.Context) {
+	player, err := h.queries.GetPlayerByUserId(c.Request.Context(), c.GetInt64("userID"))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "You are not registered as a player.",
+			})
+		} else {
+			slog.Error("Error retrieving player", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Error retrieving player.",
+			})
+		}
+		return
+	}
+
+	plans, err := h.playerPaymentPlans(c.Request.Context(), player.ID)
+	if err != nil {
+		slog.Error("Failed to fetch payment plans", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch payment plans.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": plans,
+	})
+}
+
+func (h *Handler) playerPaymentPlans(ctx context.Context, playerID int64) ([]models.PaymentPlan, error) {
+	rows, err := h.queries.ListPaymentPlansByPlayer(ctx, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	plans := make([]models.PaymentPlan, 0, len(rows))
+	for _, row := range rows {
+		plan, err := paymentPlan(ctx, h.queries, row)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// ListPaymentPlans handles GET requests to list the payment plans of a season
+// with player details, their overdue installment count and next due date. Can
+// be filtered with the status query parameter.
+func (h *Handler) ListPaymentPlans(c *gin.Context) {
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	params := repository.ListPaymentPlansParams{
+		SeasonID:   scope.SeasonID,
+		DivisionID: scope.DivisionID,
+	}
+	if status := c.Query("status"); status != "" {
+		params.Status = pgtype.Text{String: status, Valid: true}
+	}
+
+	plans, err := h.queries.ListPaymentPlans(c.Request.Context(), params)
+	if err != nil {
+		slog.Error("Failed to fetch payment plans", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch payment plans.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": plans,
+	})
+}
+
+// CancelPaymentPlan handles PATCH requests to cancel an active payment plan.
+// The player still owes whatever the plan had left.
+func (h *Handler) CancelPaymentPlan(c *gin.Context) {
+	var cancelRequest models.CancelPaymentPlanRequest
+	if err := c.ShouldBindJSON(&cancelRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for cancelling payment plan.",
+		})
+		return
+	}
+	slog.Info("Starting CancelPaymentPlan", "id", cancelRequest.ID)
+
+	cancelled, err := h.queries.CancelPaymentPlan(c.Request.Context(), cancelRequest.ID)
+	if err != nil {
+		slog.Error("Failed to cancel payment plan", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to cancel payment plan.",
+		})
+		return
+	}
+	if cancelled == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "No active payment plan with that id.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
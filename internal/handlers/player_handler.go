@@ -4,7 +4,9 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/gbart/fcabl-api/internal/auth"
 	"github.com/gbart/fcabl-api/internal/models"
 	"github.com/gbart/fcabl-api/internal/repository"
 	"github.com/gin-gonic/gin"
@@ -14,7 +16,12 @@ import (
 
 // ListPlayers handles GET requests to list all players
 func (h *Handler) ListPlayers(c *gin.Context) {
-	players, err := h.queries.ListPlayers(c.Request.Context())
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	players, err := h.queries.ListPlayers(c.Request.Context(), repository.ListPlayersParams(scope))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			slog.Warn("No players found.")
@@ -124,15 +131,53 @@ func (h *Handler) CreatePlayer(c *gin.Context) {
 		return
 	}
 
-	newPlayer, err := h.queries.CreatePlayer(c.Request.Context(), createPlayerRequest.IntoDBModel())
+	// The new player's fee is assessed as of today, the day they registered
+	var newPlayer repository.Player
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		player, err := q.CreatePlayer(c.Request.Context(), createPlayerRequest.IntoDBModel())
+		if err != nil {
+			return err
+		}
+
+		if player.SiblingPlayerID.Valid {
+			sibling, err := q.GetPlayerById(c.Request.Context(), player.SiblingPlayerID.Int64)
+			if err != nil && err != pgx.ErrNoRows {
+				return err
+			}
+			if err == pgx.ErrNoRows || sibling.SeasonID != player.SeasonID {
+				return registrationError{"Sibling must be registered as a player in the same season."}
+			}
+		}
+
+		now := time.Now().UTC()
+		if createPlayerRequest.PromoCode != "" {
+			if err := redeemPromoCode(c.Request.Context(), q, player, createPlayerRequest.PromoCode, now); err != nil {
+				return err
+			}
+		}
+		if err := assessPlayer(c.Request.Context(), q, player.ID); err != nil {
+			return err
+		}
+		newPlayer, err = q.GetPlayerById(c.Request.Context(), player.ID)
+		return err
+	})
+	if message, ok := asRegistrationError(err); ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": message,
+		})
+		return
+	}
 	if err != nil {
-		slog.Error("Failed to create player", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to create player.",
+		status, message := seasonConstraintError(err)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to create player", "error", err)
+			message = "Failed to create player."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
 		})
 		return
 	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"data": newPlayer,
 	})
@@ -149,10 +194,34 @@ func (h *Handler) UpdatePlayer(c *gin.Context) {
 		return
 	}
 
-	if err := h.queries.UpdatePlayer(c.Request.Context(), updatePlayerRequest.IntoDBModel()); err != nil {
-		slog.Error("Failed to update player", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to update player.",
+	// A re-activated player's fee is assessed again as of today
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		previous, err := q.GetPlayerById(c.Request.Context(), updatePlayerRequest.ID)
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := q.UpdatePlayer(c.Request.Context(), updatePlayerRequest.IntoDBModel()); err != nil {
+			return err
+		}
+		if !previous.IsActive && updatePlayerRequest.IsActive {
+			if err := q.ResetPlayerFeeAssessedOn(c.Request.Context(), updatePlayerRequest.ID); err != nil {
+				return err
+			}
+			return assessPlayer(c.Request.Context(), q, updatePlayerRequest.ID)
+		}
+		return reconcilePlayer(c.Request.Context(), q, updatePlayerRequest.ID)
+	})
+	if err != nil {
+		status, message := seasonConstraintError(err)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to update player", "error", err)
+			message = "Failed to update player."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
 		})
 		return
 	}
@@ -186,7 +255,12 @@ func (h *Handler) DeletePlayer(c *gin.Context) {
 
 // ListActivePlayers handles GET requests to list all active players
 func (h *Handler) ListActivePlayers(c *gin.Context) {
-	players, err := h.queries.ListActivePlayers(c.Request.Context())
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	players, err := h.queries.ListActivePlayers(c.Request.Context(), repository.ListActivePlayersParams(scope))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			slog.Warn("No active players found.")
@@ -229,6 +303,10 @@ func (h *Handler) ListPlayersByTeam(c *gin.Context) {
 		return
 	}
 
+	if !h.authorizeTeam(c, teamID) {
+		return
+	}
+
 	// Convert int64 to pgtype.Int8
 	var teamIDPgType pgtype.Int8
 	teamIDPgType.Scan(teamID)
@@ -256,7 +334,12 @@ func (h *Handler) ListPlayersByTeam(c *gin.Context) {
 
 // ListFreeAgents handles GET requests to list players without teams
 func (h *Handler) ListFreeAgents(c *gin.Context) {
-	freeAgents, err := h.queries.ListFreeAgents(c.Request.Context())
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	freeAgents, err := h.queries.ListFreeAgents(c.Request.Context(), scope.SeasonID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			slog.Warn("No free agents found.")
@@ -365,7 +448,12 @@ func (h *Handler) GetPlayerWithTeam(c *gin.Context) {
 
 // ListPlayersWithUsers handles GET requests to list all players with user details
 func (h *Handler) ListPlayersWithUsers(c *gin.Context) {
-	players, err := h.queries.ListPlayersWithUsers(c.Request.Context())
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	players, err := h.queries.ListPlayersWithUsers(c.Request.Context(), repository.ListPlayersWithUsersParams(scope))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			slog.Warn("No players found.")
@@ -397,32 +485,44 @@ func (h *Handler) UpdatePlayerTeam(c *gin.Context) {
 		return
 	}
 
-	if err := h.queries.UpdatePlayerTeam(c.Request.Context(), updatePlayerTeamRequest.IntoDBModel()); err != nil {
-		slog.Error("Failed to update player team", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to update player team.",
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{})
-}
-
-// UpdatePlayerRegistrationStatus handles PATCH requests to update a player's registration status
-func (h *Handler) UpdatePlayerRegistrationStatus(c *gin.Context) {
-	var updatePlayerRegistrationStatusRequest models.UpdatePlayerRegistrationStatusRequest
-	if err := c.ShouldBindJSON(&updatePlayerRegistrationStatusRequest); err != nil {
-		slog.Error("Failed to bind JSON", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid parameters for updating player registration status.",
-		})
-		return
+	// Captains may only move players between their own team and free agency
+	if permissionScope(c) == auth.ScopeOwnTeam {
+		player, err := h.queries.GetPlayerById(c.Request.Context(), updatePlayerTeamRequest.ID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "Player not found.",
+				})
+			} else {
+				slog.Error("Error retrieving player", "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Error retrieving player.",
+				})
+			}
+			return
+		}
+		if player.TeamID.Valid && !h.authorizeTeam(c, player.TeamID.Int64) {
+			return
+		}
+		if updatePlayerTeamRequest.TeamID.Valid && !h.authorizeTeam(c, updatePlayerTeamRequest.TeamID.Int64) {
+			return
+		}
 	}
 
-	if err := h.queries.UpdatePlayerRegistrationStatus(c.Request.Context(), updatePlayerRegistrationStatusRequest.IntoDBModel()); err != nil {
-		slog.Error("Failed to update player registration status", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to update player registration status.",
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		if err := q.UpdatePlayerTeam(c.Request.Context(), updatePlayerTeamRequest.IntoDBModel()); err != nil {
+			return err
+		}
+		return reconcilePlayer(c.Request.Context(), q, updatePlayerTeamRequest.ID)
+	})
+	if err != nil {
+		status, message := seasonConstraintError(err)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to update player team", "error", err)
+			message = "Failed to update player team."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
 		})
 		return
 	}
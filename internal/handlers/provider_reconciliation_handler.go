@@ -0,0 +1,370 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gbart/fcabl-api/internal/checkout"
+	"github.com/gbart/fcabl-api/internal/models"
+	"github.com/gbart/fcabl-api/internal/money"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Kinds of provider reconciliation issues
+const (
+	issueMissingLocally    = "missing_locally"
+	issueMissingAtProvider = "missing_at_provider"
+	issueAmountMismatch    = "amount_mismatch"
+	issueStatusMismatch    = "status_mismatch"
+)
+
+// ReconcileProviderPayments handles POST requests to match the payment
+// provider's payments against the payments table by stripe_id, or payment
+// intent. The provider's payments come from a CSV export uploaded as the file
+// form field, or else from its API for the from and to query parameters
+// (YYYY-MM-DD, inclusive), which default to the last 30 days.
+//
+// Reports provider payments with no payment, payments with no provider
+// payment, and payments whose amount or status disagree. With apply=true the
+// fixable issues are fixed in one transaction: amounts and statuses are set to
+// the provider's, and missing payments whose checkout reference is a player
+// are recorded, refunded ones along with their refund. Payments missing at the
+// provider are left for a person to look into.
+func (h *Handler) ReconcileProviderPayments(c *gin.Context) {
+	apply := c.Query("apply") == "true"
+	report := models.ProviderReconciliation{
+		Provider: h.checkout.Name(),
+		Applied:  apply,
+	}
+
+	var records []checkout.Payment
+	if header, err := c.FormFile("file"); err == nil {
+		file, err := header.Open()
+		if err != nil {
+			slog.Error("Failed to open export", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Failed to read the uploaded export.",
+			})
+			return
+		}
+		defer file.Close()
+
+		records, err = checkout.ParseStripeExport(file)
+		if err != nil {
+			slog.Error("Failed to parse export", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Failed to parse the uploaded export: " + err.Error() + ".",
+			})
+			return
+		}
+		report.Source = "export"
+		for i, record := range records {
+			if i == 0 || record.Created.Before(report.From) {
+				report.From = record.Created
+			}
+			if i == 0 || !record.Created.Before(report.To) {
+				report.To = record.Created.Add(time.Second)
+			}
+		}
+	} else {
+		from, to, ok := parseDateRange(c)
+		if !ok {
+			return
+		}
+		report.Source = "api"
+		report.From, report.To = from, to
+
+		records, err = h.checkout.ListPayments(c.Request.Context(), from, to)
+		if err != nil {
+			slog.Error("Failed to list provider payments", "provider", h.checkout.Name(), "error", err)
+			c.JSON(http.StatusBadGateway, gin.H{
+				"error": "Failed to list payments from the payment provider.",
+			})
+			return
+		}
+	}
+	slog.Info("Starting ReconcileProviderPayments", "source", report.Source, "records", len(records), "apply", apply)
+
+	records = dedupeProviderPayments(records)
+	report.Checked = len(records)
+	report.Issues = []models.ProviderReconciliationIssue{}
+
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		matched := map[int64]bool{}
+		players := map[int64]bool{}
+
+		for _, record := range records {
+			payment, err := findProviderPayment(c.Request.Context(), q, record)
+			if err == pgx.ErrNoRows {
+				issue, err := h.missingLocally(c.Request.Context(), q, record, apply)
+				if err != nil {
+					return err
+				}
+				if issue != nil {
+					report.Issues = append(report.Issues, *issue)
+					if issue.Fixed {
+						players[issue.PlayerID.Int64] = true
+					}
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			matched[payment.ID] = true
+
+			issues := compareProviderPayment(payment, record)
+			if len(issues) > 0 && apply {
+				if err := q.UpdatePaymentFromProvider(c.Request.Context(), repository.UpdatePaymentFromProviderParams{
+					Amount: money.ToNumeric(record.AmountCents),
+					Status: localPaymentStatus(record.Status),
+					ID:     payment.ID,
+				}); err != nil {
+					return err
+				}
+				if err := q.SyncPaymentRefunds(c.Request.Context(), payment.ID); err != nil {
+					return err
+				}
+				players[payment.PlayerID] = true
+				for i := range issues {
+					issues[i].Fixed = true
+				}
+			}
+			report.Issues = append(report.Issues, issues...)
+		}
+		report.Matched = len(matched)
+
+		local, err := q.ListProviderPaymentsBetween(c.Request.Context(), repository.ListProviderPaymentsBetweenParams{
+			Provider: h.checkout.Name(),
+			FromDate: pgtype.Timestamp{Time: report.From, Valid: true},
+			ToDate:   pgtype.Timestamp{Time: report.To, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		for _, payment := range local {
+			if matched[payment.ID] {
+				continue
+			}
+			report.Issues = append(report.Issues, models.ProviderReconciliationIssue{
+				Kind:      issueMissingAtProvider,
+				StripeID:  payment.StripeID,
+				PaymentID: pgtype.Int8{Int64: payment.ID, Valid: true},
+				PlayerID:  pgtype.Int8{Int64: payment.PlayerID, Valid: true},
+				Amount:    payment.Amount,
+				Status:    payment.Status,
+			})
+		}
+
+		for playerID := range players {
+			if err := reconcilePlayer(c.Request.Context(), q, playerID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		slog.Error("Failed to reconcile provider payments", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to reconcile provider payments.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// parseDateRange parses the from and to query parameters into the start of
+// from and the end of to. Defaults to the last 30 days including today. If a
+// date is invalid an error response is written and ok is false.
+func parseDateRange(c *gin.Context) (from, to time.Time, ok bool) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	to = today.AddDate(0, 0, 1)
+	from = to.AddDate(0, 0, -30)
+
+	if toStr := c.Query("to"); toStr != "" {
+		date, err := time.Parse(time.DateOnly, toStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Failed to parse to date. Please provide a date as YYYY-MM-DD.",
+			})
+			return from, to, false
+		}
+		to = date.AddDate(0, 0, 1)
+	}
+	if fromStr := c.Query("from"); fromStr != "" {
+		date, err := time.Parse(time.DateOnly, fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Failed to parse from date. Please provide a date as YYYY-MM-DD.",
+			})
+			return from, to, false
+		}
+		from = date
+	}
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "From date must be on or before the to date.",
+		})
+		return from, to, false
+	}
+	return from, to, true
+}
+
+// dedupeProviderPayments keeps one payment per payment intent. Exports list
+// every attempt to charge a card, a failed attempt followed by a successful
+// one is the same payment.
+func dedupeProviderPayments(records []checkout.Payment) []checkout.Payment {
+	rank := map[string]int{
+		checkout.PaymentFailed:    0,
+		checkout.PaymentPending:   1,
+		checkout.PaymentCompleted: 2,
+		checkout.PaymentRefunded:  2,
+	}
+
+	deduped := make([]checkout.Payment, 0, len(records))
+	byIntent := map[string]int{}
+	for _, record := range records {
+		if record.PaymentIntentID == "" {
+			deduped = append(deduped, record)
+			continue
+		}
+		i, ok := byIntent[record.PaymentIntentID]
+		if !ok {
+			byIntent[record.PaymentIntentID] = len(deduped)
+			deduped = append(deduped, record)
+			continue
+		}
+		if rank[record.Status] > rank[deduped[i].Status] {
+			deduped[i] = record
+		}
+	}
+	return deduped
+}
+
+// findProviderPayment finds the payment for a provider payment by its id, or
+// by its payment intent
+func findProviderPayment(ctx context.Context, q *repository.Queries, record checkout.Payment) (repository.Payment, error) {
+	payment, err := q.GetPaymentByStripeIdForUpdate(ctx, record.ID)
+	if err == pgx.ErrNoRows && record.PaymentIntentID != "" {
+		return q.GetPaymentByStripeIdForUpdate(ctx, record.PaymentIntentID)
+	}
+	return payment, err
+}
+
+// compareProviderPayment returns the ways a payment disagrees with the provider
+func compareProviderPayment(payment repository.Payment, record checkout.Payment) []models.ProviderReconciliationIssue {
+	issue := models.ProviderReconciliationIssue{
+		StripeID:       payment.StripeID,
+		PaymentID:      pgtype.Int8{Int64: payment.ID, Valid: true},
+		PlayerID:       pgtype.Int8{Int64: payment.PlayerID, Valid: true},
+		Amount:         payment.Amount,
+		ProviderAmount: money.ToNumeric(record.AmountCents),
+		Status:         payment.Status,
+		ProviderStatus: record.Status,
+		Fixable:        true,
+	}
+
+	var issues []models.ProviderReconciliationIssue
+	if money.FromNumeric(payment.Amount) != record.AmountCents {
+		issue.Kind = issueAmountMismatch
+		issues = append(issues, issue)
+	}
+	if localPaymentStatus(record.Status) != localPaymentStatus(payment.Status) {
+		issue.Kind = issueStatusMismatch
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// localPaymentStatus is the status a payment should have for a provider
+// status. Refunds are recorded separately and set refunded themselves, so a
+// refunded payment is completed.
+func localPaymentStatus(status string) string {
+	if status == checkout.PaymentRefunded {
+		return checkout.PaymentCompleted
+	}
+	return status
+}
+
+// missingLocally reports a provider payment with no payment. Only money the
+// provider took is reported, a failed or unfinished checkout with no payment
+// is not an issue. It is fixable when its checkout reference is a player, and
+// recorded for them when applying, along with a refund of the whole amount
+// when the provider refunded it.
+func (h *Handler) missingLocally(ctx context.Context, q *repository.Queries, record checkout.Payment, apply bool) (*models.ProviderReconciliationIssue, error) {
+	if record.Status != checkout.PaymentCompleted && record.Status != checkout.PaymentRefunded {
+		return nil, nil
+	}
+
+	issue := &models.ProviderReconciliationIssue{
+		Kind:           issueMissingLocally,
+		StripeID:       record.ID,
+		ProviderAmount: money.ToNumeric(record.AmountCents),
+		ProviderStatus: record.Status,
+	}
+
+	// Checkout sessions are created with a player:ID reference
+	idStr, ok := strings.CutPrefix(record.Reference, "player:")
+	if !ok {
+		return issue, nil
+	}
+	playerID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return issue, nil
+	}
+	player, err := q.GetPlayerById(ctx, playerID)
+	if err == pgx.ErrNoRows {
+		return issue, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	issue.PlayerID = pgtype.Int8{Int64: player.ID, Valid: true}
+	issue.Fixable = true
+
+	if !apply {
+		return issue, nil
+	}
+	payment, err := q.CreateProviderPayment(ctx, repository.CreateProviderPaymentParams{
+		PlayerID:        player.ID,
+		StripeID:        record.ID,
+		Amount:          money.ToNumeric(record.AmountCents),
+		Status:          localPaymentStatus(record.Status),
+		Provider:        h.checkout.Name(),
+		PaymentIntentID: pgtype.Text{String: record.PaymentIntentID, Valid: record.PaymentIntentID != ""},
+		PaymentDate:     pgtype.Timestamp{Time: record.Created, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if record.Status == checkout.PaymentRefunded {
+		// Keyed like a charge.refunded event's refund, so the refund's own
+		// event claims it rather than recording it again
+		if _, err := q.UpsertProviderRefund(ctx, repository.UpsertProviderRefundParams{
+			PaymentID:        payment.ID,
+			Amount:           money.ToNumeric(record.AmountCents),
+			Reason:           "Refunded at the payment provider",
+			Status:           checkout.RefundSucceeded,
+			ProviderRefundID: pgtype.Text{String: fmt.Sprintf("charge:%s:%d", record.ID, record.AmountCents), Valid: true},
+		}); err != nil {
+			return nil, err
+		}
+		if err := q.SyncPaymentRefunds(ctx, payment.ID); err != nil {
+			return nil, err
+		}
+	}
+	issue.PaymentID = pgtype.Int8{Int64: payment.ID, Valid: true}
+	issue.Fixed = true
+	return issue, nil
+}
@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gbart/fcabl-api/internal/money"
+	"github.com/gbart/fcabl-api/internal/receipt"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// league is the letterhead of receipts and invoices
+func (h *Handler) league() receipt.League {
+	return receipt.League{
+		Name:    h.config.LeagueName,
+		Address: h.config.LeagueAddress,
+		Email:   h.config.LeagueEmail,
+	}
+}
+
+// sendPDF responds with a PDF download
+func sendPDF(c *gin.Context, filename string, data []byte) {
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/pdf", data)
+}
+
+// GetPaymentReceipt handles GET requests for the PDF receipt of a payment by
+// its id. Only completed and refunded payments have receipts.
+func (h *Handler) GetPaymentReceipt(c *gin.Context) {
+	idStr := c.Query("id")
+	slog.Info("Starting GetPaymentReceipt", "idStr", idStr)
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse payment id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse payment id. Please provide a valid id.",
+		})
+		return
+	}
+
+	payment, err := h.queries.GetPaymentReceipt(c.Request.Context(), id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Payment not found.",
+			})
+		} else {
+			slog.Error("Error retrieving payment", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Error retrieving payment.",
+			})
+		}
+		return
+	}
+
+	h.sendReceipt(c, payment)
+}
+
+// GetMyPayments handles GET requests from a signed in user for their payment
+// history, newest first, across every season they have played
+func (h *Handler) GetMyPayments(c *gin.Context) {
+	payments, err := h.queries.ListPaymentsByUser(c.Request.Context(), c.GetInt64("userID"))
+	if err != nil {
+		slog.Error("Failed to fetch payments", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch payments.",
+		})
+		return
+	}
+	if payments == nil {
+		payments = []repository.ListPaymentsByUserRow{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": payments,
+	})
+}
+
+// GetMyPaymentReceipt handles GET requests from a signed in user for the PDF
+// receipt of one of their own payments
+func (h *Handler) GetMyPaymentReceipt(c *gin.Context) {
+	idStr := c.Query("id")
+	userID := c.GetInt64("userID")
+	slog.Info("Starting GetMyPaymentReceipt", "idStr", idStr, "userId", userID)
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse payment id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse payment id. Please provide a valid id.",
+		})
+		return
+	}
+
+	payment, err := h.queries.GetPaymentReceipt(c.Request.Context(), id)
+	if err != nil && err != pgx.ErrNoRows {
+		slog.Error("Error retrieving payment", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error retrieving payment.",
+		})
+		return
+	}
+	// Someone else's payment is reported as missing, not forbidden, so ids
+	// can't be probed
+	if err == pgx.ErrNoRows || payment.UserID != userID {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Payment not found.",
+		})
+		return
+	}
+
+	h.sendReceipt(c, payment)
+}
+
+// sendReceipt renders a payment's receipt and responds with it
+func (h *Handler) sendReceipt(c *gin.Context, payment repository.GetPaymentReceiptRow) {
+	if payment.Status != "completed" && payment.Status != "refunded" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Receipts are only available for completed payments.",
+		})
+		return
+	}
+
+	data, err := receipt.RenderReceipt(receipt.Receipt{
+		League:        h.league(),
+		PaymentID:     payment.ID,
+		Date:          payment.PaymentDate.Time,
+		PlayerName:    payment.FirstName + " " + payment.LastName,
+		PlayerEmail:   payment.Email,
+		SeasonName:    payment.SeasonName,
+		AmountCents:   money.FromNumeric(payment.Amount),
+		RefundedCents: money.FromNumeric(payment.AmountRefunded),
+		Status:        payment.Status,
+		Provider:      payment.Provider,
+		Reference:     payment.StripeID,
+		PaymentIntent: payment.PaymentIntentID.String,
+		IssuedAt:      time.Now(),
+	})
+	if err != nil {
+		slog.Error("Failed to render receipt", "paymentId", payment.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to generate receipt.",
+		})
+		return
+	}
+
+	sendPDF(c, fmt.Sprintf("receipt-%s.pdf", receipt.ReceiptNumber(payment.ID)), data)
+}
+
+// GetPlayerInvoice handles GET requests for the PDF invoice of a player's
+// season, listing their charges and payments with the balance due
+func (h *Handler) GetPlayerInvoice(c *gin.Context) {
+	playerIDStr := c.Query("playerId")
+	slog.Info("Starting GetPlayerInvoice", "playerIdStr", playerIDStr)
+
+	playerID, err := strconv.ParseInt(playerIDStr, 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse player id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse player id. Please provide a valid id.",
+		})
+		return
+	}
+
+	h.sendInvoice(c, playerID)
+}
+
+// GetMyInvoice handles GET requests from a signed in player for the PDF
+// invoice of their season. Defaults to the current season, or the seasonId
+// query parameter picks another they played.
+func (h *Handler) GetMyInvoice(c *gin.Context) {
+	userID := c.GetInt64("userID")
+	slog.Info("Starting GetMyInvoice", "userId", userID, "seasonId", c.Query("seasonId"))
+
+	var player repository.Player
+	var err error
+	if seasonIDStr := c.Query("seasonId"); seasonIDStr != "" {
+		seasonID, parseErr := strconv.ParseInt(seasonIDStr, 10, 64)
+		if parseErr != nil {
+			slog.Error("Failed to parse season id", "error", parseErr)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Failed to parse season id. Please provide a valid id.",
+			})
+			return
+		}
+		player, err = h.queries.GetPlayerByUserAndSeason(c.Request.Context(), repository.GetPlayerByUserAndSeasonParams{
+			UserID:   userID,
+			SeasonID: seasonID,
+		})
+	} else {
+		player, err = h.queries.GetPlayerByUserId(c.Request.Context(), userID)
+	}
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "You are not registered as a player.",
+			})
+		} else {
+			slog.Error("Error retrieving player", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Error retrieving player.",
+			})
+		}
+		return
+	}
+
+	h.sendInvoice(c, player.ID)
+}
+
+// sendInvoice renders a player's invoice and responds with it
+func (h *Handler) sendInvoice(c *gin.Context, playerID int64) {
+	invoice, err := h.invoice(c.Request.Context(), playerID)
+	if err == pgx.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Player not found.",
+		})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to build invoice", "playerId", playerID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to generate invoice.",
+		})
+		return
+	}
+
+	data, err := receipt.RenderInvoice(invoice)
+	if err != nil {
+		slog.Error("Failed to render invoice", "playerId", playerID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to generate invoice.",
+		})
+		return
+	}
+
+	sendPDF(c, fmt.Sprintf("invoice-%s.pdf", receipt.InvoiceNumber(playerID)), data)
+}
+
+// invoice builds a player's invoice from their ledger account, followed by the
+// changes reconciling would post, so payments made since the last
+// reconciliation show without writing anything
+func (h *Handler) invoice(ctx context.Context, playerID int64) (receipt.Invoice, error) {
+	player, err := h.queries.GetPlayerWithUser(ctx, playerID)
+	if err != nil {
+		return receipt.Invoice{}, err
+	}
+	season, err := h.queries.GetSeasonById(ctx, player.SeasonID)
+	if err != nil {
+		return receipt.Invoice{}, err
+	}
+	transactions, err := h.queries.ListPlayerLedger(ctx, player.ID)
+	if err != nil {
+		return receipt.Invoice{}, err
+	}
+	unposted, err := h.queries.ListPlayerUnpostedLedger(ctx, player.ID)
+	if err != nil {
+		return receipt.Invoice{}, err
+	}
+
+	invoice := receipt.Invoice{
+		League:      h.league(),
+		PlayerID:    player.ID,
+		PlayerName:  player.FirstName + " " + player.LastName,
+		PlayerEmail: player.Email,
+		SeasonName:  season.Name,
+		IssuedAt:    time.Now(),
+	}
+	for _, transaction := range transactions {
+		change := money.FromNumeric(transaction.BalanceChange)
+		if change == 0 {
+			continue
+		}
+		invoice.Lines = append(invoice.Lines, receipt.InvoiceLine{
+			Date:        transaction.CreatedAt.Time,
+			Description: transaction.Description,
+			AmountCents: change,
+		})
+		invoice.BalanceCents += change
+	}
+	for _, transaction := range unposted {
+		change := money.FromNumeric(transaction.BalanceChange)
+		invoice.Lines = append(invoice.Lines, receipt.InvoiceLine{
+			Date:        invoice.IssuedAt,
+			Description: transaction.Description,
+			AmountCents: change,
+		})
+		invoice.BalanceCents += change
+	}
+	return invoice, nil
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gbart/fcabl-api/internal/checkout"
+	"github.com/gbart/fcabl-api/internal/models"
+	"github.com/gbart/fcabl-api/internal/money"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// syncRefunds brings the payment's refunded total and status in line with its
+// succeeded refunds, then reconciles the player's registration balance. The
+// payment must be locked by the caller's transaction.
+func syncRefunds(ctx context.Context, q *repository.Queries, payment repository.Payment) error {
+	if err := q.SyncPaymentRefunds(ctx, payment.ID); err != nil {
+		return err
+	}
+	return reconcilePlayer(ctx, q, payment.PlayerID)
+}
+
+// CreateRefund handles POST requests to refund all or part of a completed
+// payment through the payment provider. The refund is recorded as pending
+// before the provider is called, so concurrent refunds cannot add up to more
+// than the payment, and is then updated with the provider's result. Refunds
+// the provider leaves pending are completed by its webhook.
+func (h *Handler) CreateRefund(c *gin.Context) {
+	var createRefundRequest models.CreateRefundRequest
+	if err := c.ShouldBindJSON(&createRefundRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for creating refund.",
+		})
+		return
+	}
+	slog.Info("Starting CreateRefund", "paymentId", createRefundRequest.PaymentID)
+
+	var payment repository.Payment
+	var refund repository.Refund
+	var amountCents int64
+	status, message := http.StatusOK, ""
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		var err error
+		payment, err = q.GetPaymentByIdForUpdate(c.Request.Context(), createRefundRequest.PaymentID)
+		if err == pgx.ErrNoRows {
+			status, message = http.StatusNotFound, "Payment not found."
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if payment.Status != "completed" {
+			status, message = http.StatusBadRequest, "Only completed payments can be refunded."
+			return nil
+		}
+		if payment.Provider != h.checkout.Name() {
+			status, message = http.StatusBadRequest, "Payment was not made through the "+h.checkout.Name()+" payment provider."
+			return nil
+		}
+
+		committed, err := q.GetCommittedRefundAmount(c.Request.Context(), payment.ID)
+		if err != nil {
+			return err
+		}
+		refundable := money.FromNumeric(payment.Amount) - money.FromNumeric(committed)
+
+		amountCents = refundable
+		if createRefundRequest.Amount.Valid {
+			amountCents = money.FromNumeric(createRefundRequest.Amount)
+		}
+		if amountCents <= 0 || amountCents > refundable {
+			status, message = http.StatusBadRequest, "Refund amount must be more than $0.00 and at most "+money.Format(refundable)+"."
+			return nil
+		}
+
+		refund, err = q.CreateRefund(c.Request.Context(), repository.CreateRefundParams{
+			PaymentID: payment.ID,
+			Amount:    money.ToNumeric(amountCents),
+			Reason:    createRefundRequest.Reason,
+			CreatedBy: pgtype.Int8{Int64: c.GetInt64("userID"), Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		slog.Error("Failed to create refund", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create refund.",
+		})
+		return
+	}
+	if status != http.StatusOK {
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	// Payments made through checkout are refunded by their payment intent
+	providerPaymentID := payment.StripeID
+	if payment.PaymentIntentID.Valid {
+		providerPaymentID = payment.PaymentIntentID.String
+	}
+	result, err := h.checkout.Refund(c.Request.Context(), checkout.RefundRequest{
+		PaymentID:      providerPaymentID,
+		AmountCents:    amountCents,
+		Reason:         createRefundRequest.Reason,
+		Reference:      strconv.FormatInt(refund.ID, 10),
+		IdempotencyKey: "refund-" + strconv.FormatInt(refund.ID, 10),
+	})
+	if err != nil {
+		slog.Error("Failed to issue refund", "provider", h.checkout.Name(), "refundId", refund.ID, "error", err)
+		if _, err := h.queries.SetRefundProviderResult(c.Request.Context(), repository.SetRefundProviderResultParams{
+			Status: checkout.RefundFailed,
+			ID:     refund.ID,
+		}); err != nil {
+			slog.Error("Failed to mark refund failed", "refundId", refund.ID, "error", err)
+		}
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error": "Failed to issue the refund with the payment provider.",
+		})
+		return
+	}
+
+	err = h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		locked, err := q.GetPaymentByIdForUpdate(c.Request.Context(), payment.ID)
+		if err != nil {
+			return err
+		}
+		refund, err = q.SetRefundProviderResult(c.Request.Context(), repository.SetRefundProviderResultParams{
+			ProviderRefundID: pgtype.Text{String: result.ID, Valid: true},
+			Status:           result.Status,
+			ID:               refund.ID,
+		})
+		if err != nil {
+			return err
+		}
+		return syncRefunds(c.Request.Context(), q, locked)
+	})
+	if err != nil {
+		// The provider has the refund, its webhook will record the result
+		slog.Error("Failed to record refund result", "refundId", refund.ID, "providerRefundId", result.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Refund was issued but could not be recorded.",
+		})
+		return
+	}
+
+	slog.Info("Issued refund", "paymentId", payment.ID, "refundId", refund.ID, "amount", money.Format(amountCents), "status", refund.Status)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": refund,
+	})
+}
+
+// ListRefunds handles GET requests to list refunds. The refunds of a single
+// payment are listed with the paymentId query parameter, otherwise the refunds
+// of the season with player details.
+func (h *Handler) ListRefunds(c *gin.Context) {
+	if paymentIDStr := c.Query("paymentId"); paymentIDStr != "" {
+		paymentID, err := strconv.ParseInt(paymentIDStr, 10, 64)
+		if err != nil {
+			slog.Error("Failed to parse payment id", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Failed to parse payment id. Please provide a valid id.",
+			})
+			return
+		}
+
+		refunds, err := h.queries.ListRefundsByPayment(c.Request.Context(), paymentID)
+		if err != nil {
+			slog.Error("Failed to fetch refunds", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to fetch refunds.",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data": refunds,
+		})
+		return
+	}
+
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	refunds, err := h.queries.ListRefundsWithPlayerInfo(c.Request.Context(), repository.ListRefundsWithPlayerInfoParams(scope))
+	if err != nil {
+		slog.Error("Failed to fetch refunds", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch refunds.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": refunds,
+	})
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// reconcilePlayer posts changes to a player's fee, payments and refunds to the
+// ledger, recomputes their registration fee due and fully registered flag from
+// their ledger balance, and applies the payments to their payment plan. It is
+// called in the same transaction as every change to a payment.
+func reconcilePlayer(ctx context.Context, q *repository.Queries, playerID int64) error {
+	if _, err := reconcile(ctx, q, pgtype.Int8{Int64: playerID, Valid: true}, pgtype.Int8{}); err != nil {
+		return err
+	}
+	return syncPaymentPlan(ctx, q, playerID)
+}
+
+// reconcileSeason reconciles every player in a season, after its fee schedule
+// changes.
+func reconcileSeason(ctx context.Context, q *repository.Queries, seasonID int64) error {
+	_, err := reconcile(ctx, q, pgtype.Int8{}, pgtype.Int8{Int64: seasonID, Valid: true})
+	return err
+}
+
+// reconcile posts to the ledger and updates the registration of a player or a
+// season's players, returning the number of players whose stored values changed.
+// The players are locked first, so concurrent reconciles of the same player
+// each see the other's ledger entries.
+func reconcile(ctx context.Context, q *repository.Queries, playerID, seasonID pgtype.Int8) (int64, error) {
+	if err := q.LockPlayers(ctx, repository.LockPlayersParams{
+		PlayerID: playerID,
+		SeasonID: seasonID,
+	}); err != nil {
+		return 0, err
+	}
+	_, err := q.PostLedgerTransactions(ctx, repository.PostLedgerTransactionsParams{
+		PlayerID: playerID,
+		SeasonID: seasonID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return q.ReconcilePlayerRegistrations(ctx, repository.ReconcilePlayerRegistrationsParams{
+		PlayerID: playerID,
+		SeasonID: seasonID,
+	})
+}
+
+// ReconcileRegistrations handles POST requests to post unposted fee, payment
+// and refund changes to the ledger and recompute registration fees due. A
+// single player can be given with the playerId query parameter, otherwise
+// every player in the season is reconciled. Responds with the number of
+// players whose stored values changed.
+func (h *Handler) ReconcileRegistrations(c *gin.Context) {
+	params := repository.ReconcilePlayerRegistrationsParams{}
+
+	if playerIDStr := c.Query("playerId"); playerIDStr != "" {
+		playerID, err := strconv.ParseInt(playerIDStr, 10, 64)
+		if err != nil {
+			slog.Error("Failed to parse player id", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Failed to parse player id. Please provide a valid id.",
+			})
+			return
+		}
+		params.PlayerID = pgtype.Int8{Int64: playerID, Valid: true}
+	} else {
+		scope, ok := h.parseSeasonScope(c)
+		if !ok {
+			return
+		}
+		params.SeasonID = pgtype.Int8{Int64: scope.SeasonID, Valid: true}
+	}
+	slog.Info("Starting ReconcileRegistrations", "playerId", params.PlayerID.Int64, "seasonId", params.SeasonID.Int64)
+
+	var updated int64
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		var err error
+		updated, err = reconcile(c.Request.Context(), q, params.PlayerID, params.SeasonID)
+		return err
+	})
+	if err != nil {
+		slog.Error("Failed to reconcile registrations", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to reconcile registrations.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{"updated": updated},
+	})
+}
+
+// ListRegistrationMismatches handles GET requests to list the players whose
+// stored registration fee due or fully registered flag disagree with their
+// ledger balance, including changes not yet posted.
+func (h *Handler) ListRegistrationMismatches(c *gin.Context) {
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	mismatches, err := h.queries.ListRegistrationMismatches(c.Request.Context(), repository.ListRegistrationMismatchesParams(scope))
+	if err != nil {
+		slog.Error("Failed to fetch registration mismatches", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch registration mismatches.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": mismatches,
+	})
+}
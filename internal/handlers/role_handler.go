@@ -0,0 +1,351 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gbart/fcabl-api/internal/auth"
+	"github.com/gbart/fcabl-api/internal/models"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// permissionScope returns the scope the route's permission was granted in,
+// as set by middleware.RequirePermission
+func permissionScope(c *gin.Context) auth.Scope {
+	value, _ := c.Get("permissionScope")
+	scope, _ := value.(auth.Scope)
+	return scope
+}
+
+// authorizeTeam reports whether the user may act on the team. Users whose
+// permission is scoped to their own team must captain it. If not, an error
+// response is written.
+func (h *Handler) authorizeTeam(c *gin.Context, teamID int64) bool {
+	switch permissionScope(c) {
+	case auth.ScopeAll:
+		return true
+	case auth.ScopeOwnTeam:
+		isCaptain, err := h.queries.IsTeamCaptain(c.Request.Context(), repository.IsTeamCaptainParams{
+			TeamID: teamID,
+			UserID: c.GetInt64("userID"),
+		})
+		if err != nil {
+			slog.Error("Failed to check team captain", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to check permissions.",
+			})
+			return false
+		}
+		if isCaptain {
+			return true
+		}
+	}
+
+	c.JSON(http.StatusForbidden, gin.H{
+		"error": "You are not a captain of this team.",
+	})
+	return false
+}
+
+// authorizeGame reports whether the user may act on the game. Users whose
+// permission is scoped to assigned games must be assigned to it. If not, an
+// error response is written.
+func (h *Handler) authorizeGame(c *gin.Context, gameID int64) bool {
+	switch permissionScope(c) {
+	case auth.ScopeAll:
+		return true
+	case auth.ScopeAssignedGames:
+		isAssigned, err := h.queries.IsAssignedToGame(c.Request.Context(), repository.IsAssignedToGameParams{
+			GameID: gameID,
+			UserID: c.GetInt64("userID"),
+		})
+		if err != nil {
+			slog.Error("Failed to check game assignment", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to check permissions.",
+			})
+			return false
+		}
+		if isAssigned {
+			return true
+		}
+	}
+
+	c.JSON(http.StatusForbidden, gin.H{
+		"error": "You are not assigned to this game.",
+	})
+	return false
+}
+
+// requireUserRole checks the user exists and has the given role, so captains
+// and game assignments are only given to users whose role can use them. If not,
+// an error response is written.
+func (h *Handler) requireUserRole(c *gin.Context, userID int64, roles ...string) bool {
+	user, err := h.queries.GetUserById(c.Request.Context(), userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "User does not exist.",
+			})
+		} else {
+			slog.Error("Error retrieving user", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Error retrieving user.",
+			})
+		}
+		return false
+	}
+
+	for _, role := range roles {
+		if user.Role == role {
+			return true
+		}
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": "User does not have a role that can be assigned here.",
+	})
+	return false
+}
+
+// GetUserAccess handles GET requests for the signed in user's role, its
+// permissions, and the teams and games those permissions are scoped to
+func (h *Handler) GetUserAccess(c *gin.Context) {
+	userID := c.GetInt64("userID")
+	role := c.GetString("userRole")
+
+	teams, err := h.queries.ListCaptainedTeamsByUser(c.Request.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to fetch captained teams", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch user access.",
+		})
+		return
+	}
+
+	games, err := h.queries.ListAssignedGamesByUser(c.Request.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to fetch assigned games", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch user access.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": models.UserAccess{
+			Role:        role,
+			Permissions: auth.RolePermissions(role),
+			Teams:       teams,
+			Games:       games,
+		},
+	})
+}
+
+// ListTeamCaptains handles GET requests to list a team's captains
+func (h *Handler) ListTeamCaptains(c *gin.Context) {
+	teamIDStr := c.Query("teamId")
+	slog.Info("Starting ListTeamCaptains", "teamIdStr", teamIDStr)
+
+	if teamIDStr == "" {
+		slog.Warn("Team ID is empty.")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Please provide a team id.",
+		})
+		return
+	}
+
+	teamID, err := strconv.ParseInt(teamIDStr, 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse team id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse team id. Please provide a valid id.",
+		})
+		return
+	}
+
+	captains, err := h.queries.ListTeamCaptains(c.Request.Context(), teamID)
+	if err != nil {
+		slog.Error("Failed to fetch team captains", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch team captains",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": captains,
+	})
+}
+
+// AddTeamCaptain handles POST requests to make a captain user a captain of a team
+func (h *Handler) AddTeamCaptain(c *gin.Context) {
+	var teamCaptainRequest models.TeamCaptainRequest
+	if err := c.ShouldBindJSON(&teamCaptainRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for adding team captain.",
+		})
+		return
+	}
+
+	if !h.requireUserRole(c, teamCaptainRequest.UserID, auth.RoleCaptain) {
+		return
+	}
+
+	if err := h.queries.AddTeamCaptain(c.Request.Context(), teamCaptainRequest.IntoDBModel()); err != nil {
+		if code, _ := pgErrorCode(err); code == pgForeignKeyViolation {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Team does not exist.",
+			})
+			return
+		}
+		slog.Error("Failed to add team captain", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to add team captain.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// RemoveTeamCaptain handles DELETE requests to remove a captain from a team
+func (h *Handler) RemoveTeamCaptain(c *gin.Context) {
+	teamID, err := strconv.ParseInt(c.Param("teamId"), 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse team id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse team id. Please provide a valid id.",
+		})
+		return
+	}
+
+	userID, err := strconv.ParseInt(c.Param("userId"), 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse user id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse user id. Please provide a valid id.",
+		})
+		return
+	}
+
+	if err := h.queries.RemoveTeamCaptain(c.Request.Context(), repository.RemoveTeamCaptainParams{
+		TeamID: teamID,
+		UserID: userID,
+	}); err != nil {
+		slog.Error("Failed to remove team captain", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to remove team captain.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// ListGameAssignments handles GET requests to list the scorekeepers and
+// referees assigned to a game
+func (h *Handler) ListGameAssignments(c *gin.Context) {
+	gameIDStr := c.Query("gameId")
+	slog.Info("Starting ListGameAssignments", "gameIdStr", gameIDStr)
+
+	if gameIDStr == "" {
+		slog.Warn("Game ID is empty.")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Please provide a game id.",
+		})
+		return
+	}
+
+	gameID, err := strconv.ParseInt(gameIDStr, 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse game id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse game id. Please provide a valid id.",
+		})
+		return
+	}
+
+	assignments, err := h.queries.ListGameAssignments(c.Request.Context(), gameID)
+	if err != nil {
+		slog.Error("Failed to fetch game assignments", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch game assignments",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": assignments,
+	})
+}
+
+// AssignUserToGame handles POST requests to assign a scorekeeper or referee to a game
+func (h *Handler) AssignUserToGame(c *gin.Context) {
+	var gameAssignmentRequest models.GameAssignmentRequest
+	if err := c.ShouldBindJSON(&gameAssignmentRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for assigning user to game.",
+		})
+		return
+	}
+
+	if !h.requireUserRole(c, gameAssignmentRequest.UserID, auth.RoleScorekeeper, auth.RoleReferee) {
+		return
+	}
+
+	if err := h.queries.AssignUserToGame(c.Request.Context(), gameAssignmentRequest.IntoDBModel()); err != nil {
+		if code, _ := pgErrorCode(err); code == pgForeignKeyViolation {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Game does not exist.",
+			})
+			return
+		}
+		slog.Error("Failed to assign user to game", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to assign user to game.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// UnassignUserFromGame handles DELETE requests to remove a user's assignment to a game
+func (h *Handler) UnassignUserFromGame(c *gin.Context) {
+	gameID, err := strconv.ParseInt(c.Param("gameId"), 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse game id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse game id. Please provide a valid id.",
+		})
+		return
+	}
+
+	userID, err := strconv.ParseInt(c.Param("userId"), 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse user id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse user id. Please provide a valid id.",
+		})
+		return
+	}
+
+	if err := h.queries.UnassignUserFromGame(c.Request.Context(), repository.UnassignUserFromGameParams{
+		GameID: gameID,
+		UserID: userID,
+	}); err != nil {
+		slog.Error("Failed to unassign user from game", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to unassign user from game.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gbart/fcabl-api/internal/models"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gbart/fcabl-api/internal/schedule"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// GenerateSchedule handles POST requests to generate a round-robin season.
+// A dry run returns the schedule without saving it, otherwise every game is
+// created in a single transaction.
+func (h *Handler) GenerateSchedule(c *gin.Context) {
+	var generateScheduleRequest models.GenerateScheduleRequest
+	if err := c.ShouldBindJSON(&generateScheduleRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for generating a schedule.",
+		})
+		return
+	}
+
+	opts, err := generateScheduleRequest.IntoScheduleOptions()
+	if err != nil {
+		slog.Warn("Invalid schedule options", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Invalid parameters for generating a schedule: %s.", err),
+		})
+		return
+	}
+
+	teamNames := make(map[int64]string, len(opts.TeamIDs))
+	var seasonID int64
+	for _, id := range opts.TeamIDs {
+		team, err := h.queries.GetTeamById(c.Request.Context(), id)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": fmt.Sprintf("Team %d does not exist.", id),
+				})
+			} else {
+				slog.Error("Error retrieving team", "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Error retrieving team.",
+				})
+			}
+			return
+		}
+
+		if seasonID != 0 && team.SeasonID != seasonID {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "All teams must be in the same season.",
+			})
+			return
+		}
+		seasonID = team.SeasonID
+		teamNames[team.ID] = team.Name
+	}
+
+	generated, err := schedule.RoundRobin(opts)
+	if err != nil {
+		slog.Warn("Failed to generate schedule", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Failed to generate schedule: %s.", err),
+		})
+		return
+	}
+
+	games := make([]models.ScheduledGame, len(generated.Games))
+	for i, m := range generated.Games {
+		games[i] = models.ScheduledGame{
+			Round:        m.Round,
+			HomeTeamID:   m.HomeTeamID,
+			HomeTeamName: teamNames[m.HomeTeamID],
+			AwayTeamID:   m.AwayTeamID,
+			AwayTeamName: teamNames[m.AwayTeamID],
+			GameTime:     pgtype.Timestamp{Time: m.GameTime, Valid: true},
+		}
+	}
+
+	if !generateScheduleRequest.DryRun {
+		err = h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+			for i := range games {
+				game, err := q.CreateGame(c.Request.Context(), repository.CreateGameParams{
+					HomeTeamID: games[i].HomeTeamID,
+					AwayTeamID: games[i].AwayTeamID,
+					GameTime:   games[i].GameTime,
+				})
+				if err != nil {
+					return err
+				}
+				games[i].ID = game.ID
+			}
+			return nil
+		})
+		if err != nil {
+			slog.Error("Failed to create scheduled games", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to create scheduled games.",
+			})
+			return
+		}
+		slog.Info("Created season schedule", "games", len(games))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": models.GeneratedSchedule{
+			DryRun: generateScheduleRequest.DryRun,
+			Games:  games,
+			Byes:   generated.Byes,
+		},
+	})
+}
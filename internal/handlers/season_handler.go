@@ -0,0 +1,405 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gbart/fcabl-api/internal/models"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const pgNotNullViolation = "23502"
+
+// seasonScope is the season, and optionally the division, that list endpoints
+// are filtered by. Its fields match the generated list query params, so it can
+// be converted straight into them.
+type seasonScope struct {
+	SeasonID   int64
+	DivisionID pgtype.Int8
+}
+
+// parseSeasonScope reads the optional seasonId and divisionId query parameters
+// and resolves them with resolveSeasonScope. If the parameters are invalid an
+// error response is written and ok is false.
+func (h *Handler) parseSeasonScope(c *gin.Context) (scope seasonScope, ok bool) {
+	var seasonID int64
+	var divisionID pgtype.Int8
+
+	if seasonIDStr := c.Query("seasonId"); seasonIDStr != "" {
+		id, err := strconv.ParseInt(seasonIDStr, 10, 64)
+		if err != nil {
+			slog.Error("Failed to parse season id", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Failed to parse season id. Please provide a valid id.",
+			})
+			return scope, false
+		}
+		seasonID = id
+	}
+
+	if divisionIDStr := c.Query("divisionId"); divisionIDStr != "" {
+		id, err := strconv.ParseInt(divisionIDStr, 10, 64)
+		if err != nil {
+			slog.Error("Failed to parse division id", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Failed to parse division id. Please provide a valid id.",
+			})
+			return scope, false
+		}
+		divisionID = pgtype.Int8{Int64: id, Valid: true}
+	}
+
+	return h.resolveSeasonScope(c, seasonID, divisionID)
+}
+
+// resolveSeasonScope fills in the season for a scope. Without a season id the
+// division's season is used, or else the current season. If the ids are
+// invalid an error response is written and ok is false.
+func (h *Handler) resolveSeasonScope(c *gin.Context, seasonID int64, divisionID pgtype.Int8) (scope seasonScope, ok bool) {
+	scope.SeasonID = seasonID
+
+	if divisionID.Valid {
+		division, err := h.queries.GetDivisionById(c.Request.Context(), divisionID.Int64)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Division does not exist.",
+				})
+			} else {
+				slog.Error("Error retrieving division", "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Error retrieving division.",
+				})
+			}
+			return scope, false
+		}
+
+		if scope.SeasonID != 0 && scope.SeasonID != division.SeasonID {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Division does not belong to that season.",
+			})
+			return scope, false
+		}
+		scope.SeasonID = division.SeasonID
+		scope.DivisionID = divisionID
+	}
+
+	if scope.SeasonID == 0 {
+		season, err := h.queries.GetCurrentSeason(c.Request.Context())
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				slog.Warn("No current season set.")
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "No current season is set. Please provide a season id.",
+				})
+			} else {
+				slog.Error("Error retrieving current season", "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Error retrieving current season.",
+				})
+			}
+			return scope, false
+		}
+		scope.SeasonID = season.ID
+	}
+
+	return scope, true
+}
+
+// seasonConstraintError translates a violation of the season and division
+// constraints into a status code and client facing message. A 500 status
+// means the error was unexpected.
+func seasonConstraintError(err error) (int, string) {
+	code, constraint := pgErrorCode(err)
+	switch {
+	case code == pgNotNullViolation:
+		return http.StatusBadRequest, "No season was given and no current season is set."
+	case code == pgForeignKeyViolation:
+		return http.StatusBadRequest, "Referenced season, division or team does not exist."
+	}
+
+	switch constraint {
+	case "season_dates_ordered":
+		return http.StatusBadRequest, "Season end date must be on or after its start date."
+	case "seasons_name_key":
+		return http.StatusConflict, "A season with that name already exists."
+	case "unique_division_name_per_season":
+		return http.StatusConflict, "A division with that name already exists in this season."
+	case "unique_team_name_per_season":
+		return http.StatusConflict, "A team with that name already exists in this season."
+	case "unique_player_per_season":
+		return http.StatusConflict, "That user is already registered as a player this season."
+	case "team_division_must_be_in_season":
+		return http.StatusBadRequest, "Division must belong to the team's season."
+	case "player_team_must_be_in_season":
+		return http.StatusBadRequest, "Player's team must belong to the player's season."
+	case "game_teams_must_share_season":
+		return http.StatusBadRequest, "Both teams must be in the same season."
+	case "season_fee_not_negative", "division_fee_not_negative":
+		return http.StatusBadRequest, "Registration fee must not be negative."
+	}
+	return http.StatusInternalServerError, ""
+}
+
+// seasonDeleteError translates an error deleting a season. Teams and players
+// keep their season, so a season that still has them can't be deleted.
+func seasonDeleteError(err error) (int, string) {
+	code, constraint := pgErrorCode(err)
+	if code == pgForeignKeyViolation &&
+		(constraint == "teams_season_id_fkey" || constraint == "players_season_id_fkey") {
+		return http.StatusConflict, "The season still has teams or players and cannot be deleted."
+	}
+	return seasonConstraintError(err)
+}
+
+// ListSeasons handles GET requests to list all seasons, newest first
+func (h *Handler) ListSeasons(c *gin.Context) {
+	seasons, err := h.queries.ListSeasons(c.Request.Context())
+	if err != nil {
+		slog.Error("Failed to fetch seasons", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch seasons",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": seasons,
+	})
+}
+
+// GetSeason handles GET requests for a single season by ID
+func (h *Handler) GetSeason(c *gin.Context) {
+	seasonIDStr := c.Query("id")
+	slog.Info("Starting GetSeason", "seasonIdStr", seasonIDStr)
+
+	if seasonIDStr == "" {
+		slog.Warn("Season ID is empty.")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Please provide a season id.",
+		})
+		return
+	}
+
+	seasonID, err := strconv.ParseInt(seasonIDStr, 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse season id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse season id. Please provide a valid id.",
+		})
+		return
+	}
+
+	season, err := h.queries.GetSeasonById(c.Request.Context(), seasonID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			slog.Warn("No season found.")
+			c.JSON(http.StatusOK, gin.H{
+				"data": []repository.Season{},
+			})
+		} else {
+			slog.Error("Error retrieving season", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Error retrieving season.",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": season,
+	})
+}
+
+// GetCurrentSeason handles GET requests for the current season
+func (h *Handler) GetCurrentSeason(c *gin.Context) {
+	season, err := h.queries.GetCurrentSeason(c.Request.Context())
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			slog.Warn("No current season set.")
+			c.JSON(http.StatusOK, gin.H{
+				"data": []repository.Season{},
+			})
+		} else {
+			slog.Error("Error retrieving current season", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Error retrieving current season.",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": season,
+	})
+}
+
+// CreateSeason handles POST requests to create a new season
+func (h *Handler) CreateSeason(c *gin.Context) {
+	var createSeasonRequest models.CreateSeasonRequest
+	if err := c.ShouldBindJSON(&createSeasonRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for creating season.",
+		})
+		return
+	}
+
+	newSeason, err := h.queries.CreateSeason(c.Request.Context(), createSeasonRequest.IntoDBModel())
+	if err != nil {
+		status, message := seasonConstraintError(err)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to create season", "error", err)
+			message = "Failed to create season."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": newSeason,
+	})
+}
+
+// UpdateSeason handles PUT requests to update a season's name, dates and
+// registration fee. Its players' registrations are reconciled against the fee.
+func (h *Handler) UpdateSeason(c *gin.Context) {
+	var updateSeasonRequest models.UpdateSeasonRequest
+	if err := c.ShouldBindJSON(&updateSeasonRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for updating season.",
+		})
+		return
+	}
+
+	var updated int64
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		var err error
+		updated, err = q.UpdateSeason(c.Request.Context(), updateSeasonRequest.IntoDBModel())
+		if err != nil || updated == 0 {
+			return err
+		}
+		return reconcileSeason(c.Request.Context(), q, updateSeasonRequest.ID)
+	})
+	if err != nil {
+		status, message := seasonConstraintError(err)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to update season", "error", err)
+			message = "Failed to update season."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	if updated == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Season not found.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// SetCurrentSeason handles PATCH requests to change which season is current.
+// List endpoints default to the current season.
+func (h *Handler) SetCurrentSeason(c *gin.Context) {
+	var setCurrentSeasonRequest models.SetCurrentSeasonRequest
+	if err := c.ShouldBindJSON(&setCurrentSeasonRequest); err != nil {
+		slog.Error("Failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid parameters for setting the current season.",
+		})
+		return
+	}
+
+	var updated int64
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		if err := q.ClearCurrentSeason(c.Request.Context()); err != nil {
+			return err
+		}
+		var err error
+		updated, err = q.SetCurrentSeason(c.Request.Context(), setCurrentSeasonRequest.ID)
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			// Roll back so the previous current season is kept
+			return pgx.ErrNoRows
+		}
+		return nil
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Season not found.",
+			})
+			return
+		}
+		slog.Error("Failed to set current season", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to set current season.",
+		})
+		return
+	}
+
+	slog.Info("Changed current season", "seasonId", setCurrentSeasonRequest.ID)
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// DeleteSeason handles DELETE requests to delete a season along with its
+// divisions. The current season, and seasons that still have teams or
+// players, cannot be deleted.
+func (h *Handler) DeleteSeason(c *gin.Context) {
+	seasonID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse season id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse season id. Please provide a valid id.",
+		})
+		return
+	}
+
+	season, err := h.queries.GetSeasonById(c.Request.Context(), seasonID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusOK, gin.H{})
+		} else {
+			slog.Error("Error retrieving season", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Error retrieving season.",
+			})
+		}
+		return
+	}
+
+	if season.IsCurrent {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "The current season cannot be deleted.",
+		})
+		return
+	}
+
+	if err := h.queries.DeleteSeason(c.Request.Context(), seasonID); err != nil {
+		status, message := seasonDeleteError(err)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to delete season", "error", err)
+			message = "Failed to delete season."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gbart/fcabl-api/internal/auth"
+	"github.com/gbart/fcabl-api/internal/models"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	refreshCookieName = "refresh_token"
+	refreshCookiePath = "/api/auth"
+)
+
+// startSession creates a session for the user and sets the access and refresh
+// token cookies.
+func (h *Handler) startSession(c *gin.Context, userID int64, email, role string) error {
+	refreshToken, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return err
+	}
+
+	sessionID, err := h.queries.CreateSession(c.Request.Context(), repository.CreateSessionParams{
+		UserID:           userID,
+		RefreshTokenHash: auth.HashRefreshToken(refreshToken),
+		UserAgent:        c.Request.UserAgent(),
+		IpAddress:        c.ClientIP(),
+		ExpiresAt: pgtype.Timestamp{
+			Time:  time.Now().Add(time.Hour * time.Duration(h.config.RefreshTokenExpirationHours)),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	token, err := h.jwtService.GenerateToken(userID, email, role, sessionID)
+	if err != nil {
+		return err
+	}
+
+	setAuthCookie(c, token, h.config.AccessTokenExpirationMin)
+	setRefreshCookie(c, refreshToken, h.config.RefreshTokenExpirationHours)
+	return nil
+}
+
+// RefreshSession exchanges the refresh token cookie for a new access token.
+// The refresh token is rotated on every use. Presenting a refresh token that
+// was already rotated means it was copied, so the session is revoked.
+func (h *Handler) RefreshSession(c *gin.Context) {
+	refreshToken, err := c.Cookie(refreshCookieName)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "No refresh token provided"})
+		return
+	}
+
+	newRefreshToken, err := auth.GenerateRefreshToken()
+	if err != nil {
+		slog.Error("Failed to generate refresh token", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh session"})
+		return
+	}
+
+	oldHash := auth.HashRefreshToken(refreshToken)
+	session, err := h.queries.RotateSessionToken(c.Request.Context(), repository.RotateSessionTokenParams{
+		NewHash: auth.HashRefreshToken(newRefreshToken),
+		OldHash: oldHash,
+	})
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			slog.Error("Failed to rotate refresh token", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh session"})
+			return
+		}
+
+		revoked, err := h.queries.RevokeSessionByPreviousHash(c.Request.Context(), pgtype.Text{String: oldHash, Valid: true})
+		if err != nil {
+			slog.Error("Failed to revoke session", "error", err)
+		} else if revoked > 0 {
+			slog.Warn("Refresh token reused, session revoked")
+		}
+
+		clearAuthCookie(c)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		return
+	}
+
+	user, err := h.queries.GetUserById(c.Request.Context(), session.UserID)
+	if err != nil {
+		slog.Error("Failed to fetch session user", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh session"})
+		return
+	}
+
+	token, err := h.jwtService.GenerateToken(user.ID, user.Email, user.Role, session.ID)
+	if err != nil {
+		slog.Error("Failed to generate token", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh session"})
+		return
+	}
+
+	setAuthCookie(c, token, h.config.AccessTokenExpirationMin)
+	setRefreshCookie(c, newRefreshToken, h.config.RefreshTokenExpirationHours)
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"message": "Session refreshed"}})
+}
+
+// ListSessions handles GET requests to list the current user's active sessions
+func (h *Handler) ListSessions(c *gin.Context) {
+	userID := c.GetInt64("userID")
+	currentSessionID := c.GetInt64("sessionID")
+
+	sessions, err := h.queries.ListActiveSessionsByUser(c.Request.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to fetch sessions", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch sessions",
+		})
+		return
+	}
+
+	activeSessions := make([]models.ActiveSession, 0, len(sessions))
+	for _, session := range sessions {
+		activeSessions = append(activeSessions, models.ActiveSession{
+			ListActiveSessionsByUserRow: session,
+			Current:                     session.ID == currentSessionID,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": activeSessions,
+	})
+}
+
+// RevokeSession handles DELETE requests to revoke one of the current user's
+// sessions. Revoking the current session logs the user out.
+func (h *Handler) RevokeSession(c *gin.Context) {
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse session id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse session id. Please provide a valid id.",
+		})
+		return
+	}
+
+	revoked, err := h.queries.RevokeUserSession(c.Request.Context(), repository.RevokeUserSessionParams{
+		ID:     sessionID,
+		UserID: c.GetInt64("userID"),
+	})
+	if err != nil {
+		slog.Error("Failed to revoke session", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to revoke session.",
+		})
+		return
+	}
+
+	if revoked == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Session not found.",
+		})
+		return
+	}
+
+	if sessionID == c.GetInt64("sessionID") {
+		clearAuthCookie(c)
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// LogoutAll handles POST requests to revoke every session of the current user,
+// logging them out everywhere
+func (h *Handler) LogoutAll(c *gin.Context) {
+	userID := c.GetInt64("userID")
+
+	revoked, err := h.queries.RevokeAllUserSessions(c.Request.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to revoke sessions", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to log out of all sessions.",
+		})
+		return
+	}
+
+	slog.Info("Logged out of all sessions", "userId", userID, "sessionsRevoked", revoked)
+	clearAuthCookie(c)
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"message": "Logged out of all sessions"}})
+}
+
+// ListUserSessions handles GET requests from admins to list a user's active sessions
+func (h *Handler) ListUserSessions(c *gin.Context) {
+	userIDStr := c.Query("userId")
+	slog.Info("Starting ListUserSessions", "userIdStr", userIDStr)
+
+	if userIDStr == "" {
+		slog.Warn("User ID is empty.")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Please provide a user id.",
+		})
+		return
+	}
+
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse user id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse user id. Please provide a valid id.",
+		})
+		return
+	}
+
+	sessions, err := h.queries.ListActiveSessionsByUser(c.Request.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to fetch sessions", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch sessions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": sessions,
+	})
+}
+
+// RevokeUserSessions handles DELETE requests from admins to revoke every
+// session of a user
+func (h *Handler) RevokeUserSessions(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("userId"), 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse user id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse user id. Please provide a valid id.",
+		})
+		return
+	}
+
+	revoked, err := h.queries.RevokeAllUserSessions(c.Request.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to revoke sessions", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to revoke sessions.",
+		})
+		return
+	}
+
+	slog.Info("Revoked user sessions", "userId", userID, "sessionsRevoked", revoked)
+	c.JSON(http.StatusOK, gin.H{})
+}
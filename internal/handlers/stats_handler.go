@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gbart/fcabl-api/internal/models"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gbart/fcabl-api/internal/stats"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Leaderboard types, chosen with the type query parameter
+const (
+	leadersPerGame    = "perGame"
+	leadersTotal      = "total"
+	leadersPercentage = "percentage"
+)
+
+// leaderKinds are the leaderboard types and how ListStatLeaders ranks them
+var leaderKinds = map[string]string{
+	leadersPerGame:    "per_game",
+	leadersTotal:      "total",
+	leadersPercentage: "percentage",
+}
+
+// leaderCategories are the stats with leaderboards, chosen with the category
+// query parameter, and the stat ListStatLeaders ranks for them
+var leaderCategories = map[string]string{
+	"points":        "points",
+	"rebounds":      "rebounds",
+	"assists":       "assists",
+	"steals":        "steals",
+	"blocks":        "blocks",
+	"turnovers":     "turnovers",
+	"fouls":         "fouls",
+	"fieldGoals":    "field_goals",
+	"threePointers": "three_pointers",
+	"freeThrows":    "free_throws",
+}
+
+// shootingCategories are the categories with attempts, so percentages
+var shootingCategories = map[string]bool{
+	"fieldGoals":    true,
+	"threePointers": true,
+	"freeThrows":    true,
+}
+
+// Leaderboard defaults and limits
+const (
+	defaultLeaders     = 10
+	maxLeaders         = 100
+	defaultMinGames    = 1
+	defaultMinAttempts = 1
+)
+
+// GetPlayerGameStats handles GET requests for a player's stats in each game
+// they have a box score line for, with the game's teams and score
+func (h *Handler) GetPlayerGameStats(c *gin.Context) {
+	playerIDStr := c.Query("id")
+	slog.Info("Starting GetPlayerGameStats", "playerIdStr", playerIDStr)
+
+	if playerIDStr == "" {
+		slog.Warn("Player ID is empty.")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Please provide a player id.",
+		})
+		return
+	}
+
+	playerID, err := strconv.ParseInt(playerIDStr, 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse player id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse player id. Please provide a valid id.",
+		})
+		return
+	}
+
+	games, err := h.queries.ListPlayerGameStats(c.Request.Context(), playerID)
+	if err != nil {
+		slog.Error("Failed to fetch player stats", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch player stats.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": games,
+	})
+}
+
+// GetPlayerSeasonStats handles GET requests for a player's games played,
+// totals, per game averages and highs over the completed games of their
+// season, with the games themselves
+func (h *Handler) GetPlayerSeasonStats(c *gin.Context) {
+	playerID, ok := parsePlayerID(c)
+	if !ok {
+		return
+	}
+	slog.Info("Starting GetPlayerSeasonStats", "playerId", playerID)
+
+	career, err := h.playerCareer(c.Request.Context(), playerID)
+	if err != nil {
+		slog.Error("Failed to fetch player stats", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch player stats.",
+		})
+		return
+	}
+
+	for _, season := range career.Seasons {
+		if season.PlayerID == playerID {
+			c.JSON(http.StatusOK, gin.H{
+				"data": season,
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{
+		"error": "Player not found.",
+	})
+}
+
+// GetPlayerCareerStats handles GET requests for the stats of every season
+// played by the person behind a player, and their career totals. Any of
+// their players' ids can be given.
+func (h *Handler) GetPlayerCareerStats(c *gin.Context) {
+	playerID, ok := parsePlayerID(c)
+	if !ok {
+		return
+	}
+	slog.Info("Starting GetPlayerCareerStats", "playerId", playerID)
+
+	career, err := h.playerCareer(c.Request.Context(), playerID)
+	if err != nil {
+		slog.Error("Failed to fetch player stats", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch player stats.",
+		})
+		return
+	}
+	if len(career.Seasons) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Player not found.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": career,
+	})
+}
+
+// parsePlayerID reads the id query parameter. If it is missing or invalid an
+// error response is written and ok is false.
+func parsePlayerID(c *gin.Context) (playerID int64, ok bool) {
+	playerIDStr := c.Query("id")
+	if playerIDStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Please provide a player id.",
+		})
+		return 0, false
+	}
+
+	playerID, err := strconv.ParseInt(playerIDStr, 10, 64)
+	if err != nil {
+		slog.Error("Failed to parse player id", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to parse player id. Please provide a valid id.",
+		})
+		return 0, false
+	}
+	return playerID, true
+}
+
+// playerCareer summarizes the completed games of every season played by the
+// person behind playerID. Seasons is empty when there is no such player.
+func (h *Handler) playerCareer(ctx context.Context, playerID int64) (models.PlayerCareerStats, error) {
+	var career models.PlayerCareerStats
+
+	seasons, err := h.queries.ListPlayerCareerSeasons(ctx, playerID)
+	if err != nil {
+		return career, err
+	}
+	games, err := h.queries.ListPlayerCareerGameStats(ctx, playerID)
+	if err != nil {
+		return career, err
+	}
+
+	career.Seasons = make([]models.PlayerSeasonStats, 0, len(seasons))
+	for _, season := range seasons {
+		career.FirstName, career.LastName = season.FirstName, season.LastName
+		playerSeason := models.PlayerSeasonStats{
+			PlayerID:     season.ID,
+			FirstName:    season.FirstName,
+			LastName:     season.LastName,
+			SeasonID:     season.SeasonID,
+			SeasonName:   season.SeasonName,
+			TeamID:       season.TeamID,
+			TeamName:     season.TeamName,
+			JerseyNumber: season.JerseyNumber,
+			Games:        []repository.ListPlayerCareerGameStatsRow{},
+		}
+		for _, game := range games {
+			if game.PlayerID != season.ID {
+				continue
+			}
+			line := gameLine(game)
+			playerSeason.Add(line)
+			career.Career.Add(line)
+			playerSeason.Games = append(playerSeason.Games, game)
+		}
+		career.Seasons = append(career.Seasons, playerSeason)
+	}
+	return career, nil
+}
+
+// gameLine is a player's box score line for a game
+func gameLine(game repository.ListPlayerCareerGameStatsRow) stats.Line {
+	return stats.Line{
+		Points:                 int(game.Score),
+		Rebounds:               int(game.Rebounds),
+		Assists:                int(game.Assists),
+		Steals:                 int(game.Steals),
+		Blocks:                 int(game.Blocks),
+		Turnovers:              int(game.Turnovers),
+		Fouls:                  int(game.Fouls),
+		FieldGoalsMade:         int(game.FieldGoalsMade),
+		FieldGoalsAttempted:    int(game.FieldGoalsAttempted),
+		ThreePointersMade:      int(game.ThreePointersMade),
+		ThreePointersAttempted: int(game.ThreePointersAttempted),
+		FreeThrowsMade:         int(game.FreeThrowsMade),
+		FreeThrowsAttempted:    int(game.FreeThrowsAttempted),
+	}
+}
+
+// GetStatLeaders handles GET requests for a season's leaders in a stat over
+// completed games, per game, in total or by shooting percentage. Players must
+// have played minGames games, and taken minAttempts shots for percentages, to
+// qualify. Tied players share a rank and are listed by name. The leaders can
+// be narrowed to a division or a team.
+func (h *Handler) GetStatLeaders(c *gin.Context) {
+	category := c.DefaultQuery("category", "points")
+	kind := c.DefaultQuery("type", leadersPerGame)
+	slog.Info("Starting GetStatLeaders", "category", category, "type", kind)
+
+	stat, ok := leaderCategories[category]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Unsupported category. Please provide one of points, rebounds, assists, steals, blocks, turnovers, fouls, fieldGoals, threePointers or freeThrows.",
+		})
+		return
+	}
+	if _, ok := leaderKinds[kind]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Unsupported type. Please provide one of perGame, total or percentage.",
+		})
+		return
+	}
+	if kind == leadersPercentage && !shootingCategories[category] {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Percentages are only available for fieldGoals, threePointers and freeThrows.",
+		})
+		return
+	}
+
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	var teamID pgtype.Int8
+	if teamIDStr := c.Query("teamId"); teamIDStr != "" {
+		id, err := strconv.ParseInt(teamIDStr, 10, 64)
+		if err != nil {
+			slog.Error("Failed to parse team id", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Failed to parse team id. Please provide a valid id.",
+			})
+			return
+		}
+		teamID = pgtype.Int8{Int64: id, Valid: true}
+	}
+
+	minGames, ok := parseCountQuery(c, "minGames", defaultMinGames, 1, math.MaxInt32)
+	if !ok {
+		return
+	}
+	minAttempts := 0
+	if kind == leadersPercentage {
+		minAttempts, ok = parseCountQuery(c, "minAttempts", defaultMinAttempts, 1, math.MaxInt32)
+		if !ok {
+			return
+		}
+	}
+	limit, ok := parseCountQuery(c, "limit", defaultLeaders, 1, maxLeaders)
+	if !ok {
+		return
+	}
+
+	leaders, err := h.queries.ListStatLeaders(c.Request.Context(), repository.ListStatLeadersParams{
+		Kind:        leaderKinds[kind],
+		Stat:        stat,
+		SeasonID:    scope.SeasonID,
+		DivisionID:  scope.DivisionID,
+		TeamID:      teamID,
+		MinGames:    int32(minGames),
+		MinAttempts: int32(minAttempts),
+		MaxRows:     int32(limit),
+	})
+	if err != nil {
+		slog.Error("Failed to fetch stat leaders", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch stat leaders.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": leaders,
+	})
+}
+
+// parseCountQuery reads a whole number query parameter between lo and hi,
+// def when it is missing. If it is invalid an error response is written and
+// ok is false.
+func parseCountQuery(c *gin.Context, name string, def, lo, hi int) (n int, ok bool) {
+	str := c.Query(name)
+	if str == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(str)
+	if err != nil || n < lo || n > hi {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Please provide a %s between %d and %d.", name, lo, hi),
+		})
+		return 0, false
+	}
+	return n, true
+}
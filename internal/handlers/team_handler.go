@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -14,7 +15,12 @@ import (
 
 // ListTeams handles GET requests to list all teams
 func (h *Handler) ListTeams(c *gin.Context) {
-	teams, err := h.queries.ListTeams(c.Request.Context())
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	teams, err := h.queries.ListTeams(c.Request.Context(), repository.ListTeamsParams(scope))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			slog.Warn("No teams found.")
@@ -91,9 +97,13 @@ func (h *Handler) CreateTeam(c *gin.Context) {
 
 	newTeam, err := h.queries.CreateTeam(c.Request.Context(), createTeamRequest.IntoDBModel())
 	if err != nil {
-		slog.Error("Failed to create team", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to create team.",
+		status, message := seasonConstraintError(err)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to create team", "error", err)
+			message = "Failed to create team."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
 		})
 		return
 	}
@@ -114,10 +124,25 @@ func (h *Handler) UpdateTeam(c *gin.Context) {
 		return
 	}
 
-	if err := h.queries.UpdateTeam(c.Request.Context(), updateTeamRequest.IntoDBModel()); err != nil {
-		slog.Error("Failed to update team", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to update team.",
+	// Moving the team to another division can change its players' fee
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		seasonID, err := q.UpdateTeam(c.Request.Context(), updateTeamRequest.IntoDBModel())
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return reconcileSeason(c.Request.Context(), q, seasonID)
+	})
+	if err != nil {
+		status, message := seasonConstraintError(err)
+		if status == http.StatusInternalServerError {
+			slog.Error("Failed to update team", "error", err)
+			message = "Failed to update team."
+		}
+		c.JSON(status, gin.H{
+			"error": message,
 		})
 		return
 	}
@@ -151,7 +176,12 @@ func (h *Handler) DeleteTeam(c *gin.Context) {
 
 // GetTeamStandings handles GET requests for team standings
 func (h *Handler) GetTeamStandings(c *gin.Context) {
-	standings, err := h.queries.GetTeamStandings(c.Request.Context())
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	standings, err := h.queries.GetTeamStandings(c.Request.Context(), repository.GetTeamStandingsParams(scope))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			slog.Warn("No team standings found.")
@@ -172,6 +202,59 @@ func (h *Handler) GetTeamStandings(c *gin.Context) {
 	})
 }
 
+// RecomputeTeamStandings handles POST requests to rebuild every team's record
+// from completed games, repairing any counters that have drifted. Every season
+// is recomputed, the standings returned are for the requested season.
+func (h *Handler) RecomputeTeamStandings(c *gin.Context) {
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	var teamsUpdated int64
+	err := h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		var err error
+		teamsUpdated, err = recomputeStandings(c.Request.Context(), q)
+		return err
+	})
+	if err != nil {
+		slog.Error("Failed to recompute team standings", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to recompute team standings.",
+		})
+		return
+	}
+
+	slog.Info("Recomputed team standings", "teamsUpdated", teamsUpdated)
+
+	standings, err := h.queries.GetTeamStandings(c.Request.Context(), repository.GetTeamStandingsParams(scope))
+	if err != nil {
+		slog.Error("Failed to fetch team standings", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Standings recomputed, but failed to fetch them.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"teamsUpdated": teamsUpdated,
+			"standings":    standings,
+		},
+	})
+}
+
+// recomputeStandings rebuilds every team's wins, losses, draws and points from
+// completed games and returns how many teams changed. It takes a transaction
+// scoped lock first, so concurrent score updates are applied one after another
+// and each recompute sees the games committed before it.
+func recomputeStandings(ctx context.Context, q *repository.Queries) (int64, error) {
+	if err := q.LockTeamStandings(ctx); err != nil {
+		return 0, err
+	}
+	return q.RecomputeTeamStandings(ctx)
+}
+
 // GetTeamStats handles GET requests for team statistics by ID
 func (h *Handler) GetTeamStats(c *gin.Context) {
 	teamIDStr := c.Query("id")
@@ -265,7 +348,12 @@ func (h *Handler) GetTeamWithPlayers(c *gin.Context) {
 func (h *Handler) ListTeamsWithPlayers(c *gin.Context) {
 	slog.Info("Starting ListTeamsWithPlayers")
 
-	rows, err := h.queries.ListTeamsWithPlayers(c.Request.Context())
+	scope, ok := h.parseSeasonScope(c)
+	if !ok {
+		return
+	}
+
+	rows, err := h.queries.ListTeamsWithPlayers(c.Request.Context(), repository.ListTeamsWithPlayersParams(scope))
 	if err != nil {
 		slog.Error("Error retrieving teams with players", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gbart/fcabl-api/internal/checkout"
+	"github.com/gbart/fcabl-api/internal/money"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gbart/fcabl-api/internal/stripe"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxWebhookBytes bounds the size of a webhook payload. Stripe events are far
+// smaller than this.
+const maxWebhookBytes = 1 << 20
+
+// StripeWebhook handles POST requests from Stripe. The signature is verified
+// with the configured webhook secret, then the event is recorded and applied
+// in one transaction. Events already recorded are acknowledged without being
+// applied again, so redeliveries are harmless. Errors respond with a 5xx so
+// Stripe retries the event.
+func (h *Handler) StripeWebhook(c *gin.Context) {
+	if h.config.StripeWebhookSecret == "" {
+		slog.Error("Received Stripe webhook but STRIPE_WEBHOOK_SECRET is not set")
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Webhooks are not configured.",
+		})
+		return
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBytes))
+	if err != nil {
+		slog.Error("Failed to read webhook payload", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to read webhook payload.",
+		})
+		return
+	}
+
+	event, err := stripe.ConstructEvent(payload, c.GetHeader("Stripe-Signature"), h.config.StripeWebhookSecret, stripe.DefaultTolerance)
+	if err != nil {
+		slog.Warn("Rejected Stripe webhook", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid webhook signature.",
+		})
+		return
+	}
+
+	var duplicate bool
+	var ignored string
+	err = h.withTx(c.Request.Context(), func(q *repository.Queries) error {
+		eventRowID, err := q.RecordWebhookEvent(c.Request.Context(), repository.RecordWebhookEventParams{
+			Provider:  "stripe",
+			EventID:   event.ID,
+			EventType: event.Type,
+			Payload:   payload,
+		})
+		if err == pgx.ErrNoRows {
+			duplicate = true
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		ignored, err = applyStripeEvent(c.Request.Context(), q, event)
+		if err != nil {
+			return err
+		}
+		if ignored == "" {
+			return nil
+		}
+		return q.SetWebhookEventOutcome(c.Request.Context(), repository.SetWebhookEventOutcomeParams{
+			Outcome: "ignored",
+			Note:    ignored,
+			ID:      eventRowID,
+		})
+	})
+	if err != nil {
+		slog.Error("Failed to process Stripe webhook", "eventId", event.ID, "eventType", event.Type, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to process webhook.",
+		})
+		return
+	}
+
+	switch {
+	case duplicate:
+		slog.Info("Skipped duplicate Stripe webhook", "eventId", event.ID, "eventType", event.Type)
+	case ignored != "":
+		slog.Warn("Ignored Stripe webhook", "eventId", event.ID, "eventType", event.Type, "reason", ignored)
+	default:
+		slog.Info("Processed Stripe webhook", "eventId", event.ID, "eventType", event.Type)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"received": true}})
+}
+
+// applyStripeEvent updates the payment the event is about and the player's
+// registration balance. If the event does not apply, the reason is returned
+// and nothing is changed.
+func applyStripeEvent(ctx context.Context, q *repository.Queries, event stripe.Event) (ignored string, err error) {
+	switch event.Type {
+	case stripe.EventPaymentIntentSucceeded, stripe.EventPaymentIntentFailed:
+		var intent stripe.PaymentIntent
+		if err := json.Unmarshal(event.Data.Object, &intent); err != nil {
+			return "", fmt.Errorf("decoding payment intent: %w", err)
+		}
+
+		payment, err := q.GetPaymentByStripeIdForUpdate(ctx, intent.ID)
+		if err == pgx.ErrNoRows {
+			return "no payment for payment intent " + intent.ID, nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if event.Type == stripe.EventPaymentIntentFailed {
+			return failPayment(ctx, q, payment)
+		}
+		return completePayment(ctx, q, payment, intent.AmountReceived)
+
+	case stripe.EventCheckoutSessionCompleted,
+		stripe.EventCheckoutSessionAsyncPaymentSucceeded,
+		stripe.EventCheckoutSessionAsyncPaymentFailed,
+		stripe.EventCheckoutSessionExpired:
+		var session stripe.CheckoutSession
+		if err := json.Unmarshal(event.Data.Object, &session); err != nil {
+			return "", fmt.Errorf("decoding checkout session: %w", err)
+		}
+
+		payment, err := q.GetPaymentByStripeIdForUpdate(ctx, session.ID)
+		if err == pgx.ErrNoRows {
+			return "no payment for checkout session " + session.ID, nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		// Refunds are reported against the payment intent, so remember it
+		if session.PaymentIntent != "" && !payment.PaymentIntentID.Valid {
+			if err := q.SetPaymentIntentId(ctx, repository.SetPaymentIntentIdParams{
+				PaymentIntentID: pgtype.Text{String: session.PaymentIntent, Valid: true},
+				ID:              payment.ID,
+			}); err != nil {
+				return "", err
+			}
+		}
+
+		switch event.Type {
+		case stripe.EventCheckoutSessionAsyncPaymentFailed, stripe.EventCheckoutSessionExpired:
+			return failPayment(ctx, q, payment)
+		}
+		if session.PaymentStatus != "paid" {
+			return "checkout session completed but not paid yet", nil
+		}
+		return completePayment(ctx, q, payment, session.AmountTotal)
+
+	case stripe.EventChargeRefunded:
+		var charge stripe.Charge
+		if err := json.Unmarshal(event.Data.Object, &charge); err != nil {
+			return "", fmt.Errorf("decoding charge: %w", err)
+		}
+
+		payment, err := q.GetPaymentByStripeIdForUpdate(ctx, charge.PaymentIntent)
+		if err == pgx.ErrNoRows {
+			return "no payment for payment intent " + charge.PaymentIntent, nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if payment.Status != "completed" && payment.Status != "refunded" {
+			return "payment is " + payment.Status, nil
+		}
+		return applyStripeChargeRefund(ctx, q, payment, charge)
+
+	case stripe.EventRefundCreated, stripe.EventRefundUpdated, stripe.EventRefundFailed:
+		var refund stripe.Refund
+		if err := json.Unmarshal(event.Data.Object, &refund); err != nil {
+			return "", fmt.Errorf("decoding refund: %w", err)
+		}
+
+		payment, err := q.GetPaymentByStripeIdForUpdate(ctx, refund.PaymentIntent)
+		if err == pgx.ErrNoRows {
+			return "no payment for payment intent " + refund.PaymentIntent, nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if payment.Status != "completed" && payment.Status != "refunded" {
+			return "payment is " + payment.Status, nil
+		}
+		return "", applyStripeRefund(ctx, q, payment, refund)
+	}
+
+	return "unhandled event type", nil
+}
+
+// completePayment marks a payment completed with the amount received and
+// reconciles the player's registration balance. A failed payment can still be
+// completed, since the player may retry it.
+func completePayment(ctx context.Context, q *repository.Queries, payment repository.Payment, amountCents int64) (ignored string, err error) {
+	if payment.Status == "completed" || payment.Status == "refunded" {
+		return "payment is already " + payment.Status, nil
+	}
+	if err := q.CompletePayment(ctx, repository.CompletePaymentParams{
+		Amount: money.ToNumeric(amountCents),
+		ID:     payment.ID,
+	}); err != nil {
+		return "", err
+	}
+	return "", reconcilePlayer(ctx, q, payment.PlayerID)
+}
+
+// applyStripeRefund records a refund reported by Stripe against the payment.
+// Refunds issued through the API are matched by the refund id in their
+// reference, refunds made in the Stripe dashboard are recorded by Stripe's id
+// the first time they are seen. Either way later events only update the status.
+func applyStripeRefund(ctx context.Context, q *repository.Queries, payment repository.Payment, refund stripe.Refund) error {
+	status := checkout.StripeRefundStatus(refund.Status)
+	providerRefundID := pgtype.Text{String: refund.ID, Valid: true}
+
+	matched := false
+	if refundID, err := strconv.ParseInt(refund.Metadata["reference"], 10, 64); err == nil {
+		existing, err := q.GetRefundById(ctx, refundID)
+		if err != nil && err != pgx.ErrNoRows {
+			return err
+		}
+		if err == nil && existing.PaymentID == payment.ID {
+			if _, err := q.SetRefundProviderResult(ctx, repository.SetRefundProviderResultParams{
+				ProviderRefundID: providerRefundID,
+				Status:           status,
+				ID:               existing.ID,
+			}); err != nil {
+				return err
+			}
+			matched = true
+		}
+	}
+
+	if !matched {
+		// A charge.refunded event may have recorded it already
+		_, err := q.ClaimChargeRefund(ctx, repository.ClaimChargeRefundParams{
+			ProviderRefundID: refund.ID,
+			Status:           status,
+			PaymentID:        payment.ID,
+			Amount:           money.ToNumeric(refund.Amount),
+		})
+		if err == nil {
+			matched = true
+		} else if err != pgx.ErrNoRows {
+			return err
+		}
+	}
+
+	if !matched {
+		if _, err := q.UpsertProviderRefund(ctx, repository.UpsertProviderRefundParams{
+			PaymentID:        payment.ID,
+			Amount:           money.ToNumeric(refund.Amount),
+			Reason:           "Refunded in Stripe",
+			Status:           status,
+			ProviderRefundID: providerRefundID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return syncRefunds(ctx, q, payment)
+}
+
+// applyStripeChargeRefund records the part of a charge's refunded total that
+// no refund accounts for yet, for refunds Stripe only reported on the charge.
+// It is recorded as a succeeded refund keyed by the charge and its total, so
+// redeliveries don't add it twice, and the refund's own event claims it if it
+// comes later.
+func applyStripeChargeRefund(ctx context.Context, q *repository.Queries, payment repository.Payment, charge stripe.Charge) (ignored string, err error) {
+	committed, err := q.GetCommittedRefundAmount(ctx, payment.ID)
+	if err != nil {
+		return "", err
+	}
+	delta := charge.AmountRefunded - money.FromNumeric(committed)
+	if delta <= 0 {
+		return "refunds are already recorded", nil
+	}
+
+	if _, err := q.UpsertProviderRefund(ctx, repository.UpsertProviderRefundParams{
+		PaymentID:        payment.ID,
+		Amount:           money.ToNumeric(delta),
+		Reason:           "Refunded in Stripe",
+		Status:           checkout.RefundSucceeded,
+		ProviderRefundID: pgtype.Text{String: fmt.Sprintf("charge:%s:%d", charge.ID, charge.AmountRefunded), Valid: true},
+	}); err != nil {
+		return "", err
+	}
+	return "", syncRefunds(ctx, q, payment)
+}
+
+// failPayment marks a pending payment failed
+func failPayment(ctx context.Context, q *repository.Queries, payment repository.Payment) (ignored string, err error) {
+	if payment.Status != "pending" {
+		return "payment is already " + payment.Status, nil
+	}
+	_, err = q.UpdatePaymentStatus(ctx, repository.UpdatePaymentStatusParams{
+		Status: "failed",
+		ID:     payment.ID,
+	})
+	return "", err
+}
+
+// ListWebhookEvents handles GET requests to list the most recent webhook events
+func (h *Handler) ListWebhookEvents(c *gin.Context) {
+	events, err := h.queries.ListWebhookEvents(c.Request.Context(), 100)
+	if err != nil {
+		slog.Error("Failed to fetch webhook events", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch webhook events",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": events,
+	})
+}
@@ -0,0 +1,296 @@
+// Package jobs runs background jobs on an interval alongside the API.
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gbart/fcabl-api/internal/mailer"
+	"github.com/gbart/fcabl-api/internal/money"
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Job is a task run periodically in the background
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Start runs each job once and then on its interval, until ctx is cancelled.
+// A failed run is logged and retried on the next interval.
+func Start(ctx context.Context, jobs ...Job) {
+	for _, job := range jobs {
+		go run(ctx, job)
+	}
+}
+
+func run(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job.Run(ctx); err != nil {
+			slog.Error("Background job failed", "job", job.Name, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Exclusive wraps a job so only one API instance runs it at a time. Each run
+// holds a Postgres advisory lock named for the job on a connection of its own,
+// and is skipped while another instance holds it.
+func Exclusive(pool *pgxpool.Pool, job Job) Job {
+	run := job.Run
+	job.Run = func(ctx context.Context) error {
+		conn, err := pool.Acquire(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Release()
+
+		q := repository.New(conn)
+		locked, err := q.TryJobLock(ctx, job.Name)
+		if err != nil {
+			return err
+		}
+		if !locked {
+			slog.Info("Background job is running elsewhere, skipping", "job", job.Name)
+			return nil
+		}
+		defer func() {
+			if err := q.ReleaseJobLock(context.Background(), job.Name); err != nil {
+				// Closing the connection releases the lock instead
+				slog.Error("Failed to release background job lock", "job", job.Name, "error", err)
+				conn.Conn().Close(context.Background())
+			}
+		}()
+
+		return run(ctx)
+	}
+	return job
+}
+
+// MarkOverdueInstallments flags unpaid payment plan installments past their
+// due date. Installments are also updated whenever the player's payments
+// change, this catches due dates passing in between.
+func MarkOverdueInstallments(q *repository.Queries, interval time.Duration) Job {
+	return Job{
+		Name:     "mark-overdue-installments",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			marked, err := q.MarkOverdueInstallments(ctx)
+			if err != nil {
+				return err
+			}
+			if marked > 0 {
+				slog.Info("Marked installments overdue", "count", marked)
+			}
+			return nil
+		},
+	}
+}
+
+// PurgeIdempotencyKeys deletes idempotency keys that have expired, with the
+// responses stored for them
+func PurgeIdempotencyKeys(q *repository.Queries, interval time.Duration) Job {
+	return Job{
+		Name:     "purge-idempotency-keys",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			purged, err := q.DeleteExpiredIdempotencyKeys(ctx)
+			if err != nil {
+				return err
+			}
+			if purged > 0 {
+				slog.Info("Purged expired idempotency keys", "count", purged)
+			}
+			return nil
+		},
+	}
+}
+
+// PurgeExpiredSessions deletes sessions past their expiry, revoked or not.
+// Their refresh tokens can no longer be used, so they are only clutter.
+func PurgeExpiredSessions(q *repository.Queries, interval time.Duration) Job {
+	return Job{
+		Name:     "purge-expired-sessions",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			purged, err := q.DeleteExpiredSessions(ctx)
+			if err != nil {
+				return err
+			}
+			if purged > 0 {
+				slog.Info("Purged expired sessions", "count", purged)
+			}
+			return nil
+		},
+	}
+}
+
+// gameStreamEventRetention is how long live game events are kept for clients
+// reconnecting to catch up on
+const gameStreamEventRetention = 3 * 24 * time.Hour
+
+// PurgeGameStreamEvents deletes live game events too old to be caught up on
+func PurgeGameStreamEvents(q *repository.Queries, interval time.Duration) Job {
+	return Job{
+		Name:     "purge-game-stream-events",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			purged, err := q.DeleteGameStreamEventsBefore(ctx, pgtype.Timestamp{Time: time.Now().Add(-gameStreamEventRetention), Valid: true})
+			if err != nil {
+				return err
+			}
+			if purged > 0 {
+				slog.Info("Purged old live game events", "count", purged)
+			}
+			return nil
+		},
+	}
+}
+
+// Failed reminders are retried with exponential backoff, starting at
+// reminderRetryBackoff, up to maxReminderAttempts times per stage
+const (
+	maxReminderAttempts  = 5
+	reminderRetryBackoff = time.Hour
+)
+
+// DunningOptions configures payment reminders
+type DunningOptions struct {
+	// ReminderDays are the days after registering that each reminder is sent,
+	// in increasing order. The last reminder is the final notice.
+	ReminderDays []int
+	// LockoutDays is how many days after registering unpaid players are locked
+	// out of game rosters, 0 never locks them out
+	LockoutDays int
+	FrontendURL string
+}
+
+// SendPaymentReminders emails players who still owe their registration fee
+// in the current season, escalating through the reminders as time passes.
+// Only the latest reminder that is due is sent, so a player is never sent
+// several at once. Players on a payment plan are left alone unless an
+// installment is overdue. Every reminder is recorded, failed ones are retried
+// with backoff and given up on after maxReminderAttempts. Roster locks are
+// brought up to date first. Run it with Exclusive when there are several API
+// instances, so players aren't sent the same reminder more than once.
+func SendPaymentReminders(q *repository.Queries, mail mailer.Mailer, opts DunningOptions, interval time.Duration) Job {
+	frontendURL := strings.TrimRight(opts.FrontendURL, "/")
+
+	return Job{
+		Name:     "send-payment-reminders",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			var lockoutDays pgtype.Int4
+			if opts.LockoutDays > 0 {
+				lockoutDays = pgtype.Int4{Int32: int32(opts.LockoutDays), Valid: true}
+			}
+			changed, err := q.SyncRosterLocks(ctx, lockoutDays)
+			if err != nil {
+				return err
+			}
+			if changed > 0 {
+				slog.Info("Updated roster locks", "count", changed)
+			}
+
+			if len(opts.ReminderDays) == 0 {
+				return nil
+			}
+			players, err := q.ListPlayersDueReminders(ctx)
+			if err != nil {
+				return err
+			}
+
+			sent := 0
+			for _, player := range players {
+				stage := 0
+				for _, days := range opts.ReminderDays {
+					if int(player.DaysRegistered) >= days {
+						stage++
+					}
+				}
+				if stage <= int(player.LastStage) {
+					continue
+				}
+
+				failures, err := q.GetPaymentReminderFailures(ctx, repository.GetPaymentReminderFailuresParams{
+					PlayerID: player.PlayerID,
+					Stage:    int32(stage),
+				})
+				if err != nil {
+					return err
+				}
+				if failures.Attempts >= maxReminderAttempts {
+					continue
+				}
+				if failures.Attempts > 0 {
+					backoff := reminderRetryBackoff << (failures.Attempts - 1)
+					if time.Duration(failures.SecondsSinceLastAttempt)*time.Second < backoff {
+						continue
+					}
+				}
+
+				data := mailer.PaymentReminderData{
+					FirstName:   player.FirstName,
+					SeasonName:  player.SeasonName,
+					AmountDue:   money.Format(money.FromNumeric(player.RegistrationFeeDue)),
+					Stage:       stage,
+					FinalNotice: stage == len(opts.ReminderDays),
+					LockedOut:   player.RosterLockedAt.Valid,
+					PayURL:      frontendURL + "/registration",
+					SettingsURL: frontendURL + "/account",
+				}
+				if opts.LockoutDays > 0 {
+					data.LockoutDate = player.CreatedAt.Time.AddDate(0, 0, opts.LockoutDays).Format("January 2, 2006")
+				}
+
+				status, sendErr := "sent", sendReminder(ctx, mail, player.Email, data)
+				var errText pgtype.Text
+				if sendErr != nil {
+					slog.Error("Failed to send payment reminder", "playerId", player.PlayerID, "stage", stage, "error", sendErr)
+					status, errText = "failed", pgtype.Text{String: sendErr.Error(), Valid: true}
+				} else {
+					sent++
+				}
+
+				if _, err := q.CreatePaymentReminder(ctx, repository.CreatePaymentReminderParams{
+					PlayerID:  player.PlayerID,
+					Stage:     int32(stage),
+					AmountDue: player.RegistrationFeeDue,
+					SentTo:    player.Email,
+					Status:    status,
+					Error:     errText,
+				}); err != nil {
+					return err
+				}
+			}
+			if sent > 0 {
+				slog.Info("Sent payment reminders", "count", sent)
+			}
+			return nil
+		},
+	}
+}
+
+func sendReminder(ctx context.Context, mail mailer.Mailer, to string, data mailer.PaymentReminderData) error {
+	msg, err := mailer.Render("payment_reminder", to, data)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, mailer.SendTimeout)
+	defer cancel()
+	return mail.Send(ctx, msg)
+}
@@ -0,0 +1,195 @@
+// Package live delivers game events to the fans following games. Events are
+// recorded in game_stream_events by triggers and announced with NOTIFY, the
+// Hub listens for them and hands each to the subscribers it concerns.
+package live
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// channel is the channel new events are announced on, with their id
+const channel = "game_stream_events"
+
+// reconnectDelay is how long to wait before listening again after losing the
+// connection
+const reconnectDelay = 5 * time.Second
+
+// MissedEventsPage is the most events ListGameStreamEventsSince returns
+const MissedEventsPage = 1000
+
+// bufferSize is how many events a subscriber can fall behind by. A subscriber
+// further behind is dropped, and catches up by reconnecting.
+const bufferSize = 64
+
+// Filter selects the events of a game, or of every game on a day
+type Filter struct {
+	GameID pgtype.Int8
+	Date   pgtype.Date
+}
+
+// Params converts the filter to query parameters for events after afterID
+func (f Filter) Params(afterID int64) repository.ListGameStreamEventsSinceParams {
+	return repository.ListGameStreamEventsSinceParams{
+		AfterID:  afterID,
+		GameID:   f.GameID,
+		GameDate: f.Date,
+	}
+}
+
+func (f Filter) matches(event repository.GameStreamEvent) bool {
+	if f.GameID.Valid && event.GameID != f.GameID.Int64 {
+		return false
+	}
+	if f.Date.Valid && !event.GameDate.Time.Equal(f.Date.Time) {
+		return false
+	}
+	return true
+}
+
+// Subscription receives the events matching its filter on C. C is closed if
+// the subscriber falls too far behind.
+type Subscription struct {
+	C      <-chan repository.GameStreamEvent
+	events chan repository.GameStreamEvent
+	filter Filter
+	hub    *Hub
+}
+
+// Close stops delivering events to the subscription
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if _, ok := s.hub.subscribers[s]; ok {
+		delete(s.hub.subscribers, s)
+		close(s.events)
+	}
+}
+
+// Hub fans out game events to subscribers
+type Hub struct {
+	pool        *pgxpool.Pool
+	queries     *repository.Queries
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+}
+
+// NewHub creates a hub listening on the database pool
+func NewHub(pool *pgxpool.Pool) *Hub {
+	return &Hub{
+		pool:        pool,
+		queries:     repository.New(pool),
+		subscribers: map[*Subscription]struct{}{},
+	}
+}
+
+// Subscribe starts delivering the events matching filter
+func (h *Hub) Subscribe(filter Filter) *Subscription {
+	events := make(chan repository.GameStreamEvent, bufferSize)
+	sub := &Subscription{C: events, events: events, filter: filter, hub: h}
+
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+// Run listens for events until ctx is cancelled, reconnecting when the
+// connection is lost. Events recorded while reconnecting are delivered once
+// listening again.
+func (h *Hub) Run(ctx context.Context) {
+	var lastID int64
+	for {
+		err := h.listen(ctx, &lastID)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Error("Lost live game events connection", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (h *Hub) listen(ctx context.Context, lastID *int64) error {
+	conn, err := h.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+channel); err != nil {
+		return err
+	}
+	defer conn.Exec(context.Background(), "UNLISTEN "+channel)
+
+	// Catches up on events recorded while not listening. Ids follow commit
+	// order, so everything after the last event published was missed.
+	for catchingUp := *lastID > 0; catchingUp; {
+		missed, err := h.queries.ListGameStreamEventsSince(ctx, repository.ListGameStreamEventsSinceParams{AfterID: *lastID})
+		if err != nil {
+			return err
+		}
+		for _, event := range missed {
+			h.publish(event, lastID)
+		}
+		catchingUp = len(missed) == MissedEventsPage
+	}
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		id, err := strconv.ParseInt(notification.Payload, 10, 64)
+		if err != nil {
+			slog.Warn("Ignoring invalid live game event id", "payload", notification.Payload)
+			continue
+		}
+		if id <= *lastID {
+			// Already published while catching up
+			continue
+		}
+
+		event, err := h.queries.GetGameStreamEvent(ctx, id)
+		if err == pgx.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		h.publish(event, lastID)
+	}
+}
+
+// publish hands an event to the subscribers it concerns. A subscriber whose
+// buffer is full is dropped rather than holding up the rest.
+func (h *Hub) publish(event repository.GameStreamEvent, lastID *int64) {
+	*lastID = max(*lastID, event.ID)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subscribers {
+		if !sub.filter.matches(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			slog.Warn("Dropping slow live game subscriber", "gameId", sub.filter.GameID.Int64)
+			delete(h.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
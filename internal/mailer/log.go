@@ -0,0 +1,21 @@
+package mailer
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogMailer only logs that a message would have been sent. The body is not
+// logged, since it can contain links with secret tokens.
+type LogMailer struct{}
+
+// NewLogMailer creates a LogMailer
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+// Send logs the recipient and subject of the message
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	slog.Info("Email not sent, using log mail driver", "to", msg.To, "subject", msg.Subject)
+	return nil
+}
@@ -0,0 +1,45 @@
+// Package mailer sends outbound email. Messages are rendered from the
+// templates in the templates directory and delivered by one of the drivers,
+// chosen with MAIL_DRIVER: smtp, file or log.
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gbart/fcabl-api/internal/config"
+)
+
+// Message is a rendered email ready to be sent
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// SendTimeout bounds how long sending a single email may take
+const SendTimeout = 30 * time.Second
+
+// Mailer delivers email messages
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns the Mailer for the configured driver
+func New(cfg *config.Config) (Mailer, error) {
+	switch cfg.MailDriver {
+	case "smtp":
+		if cfg.SMTPHost == "" {
+			return nil, fmt.Errorf("SMTP_HOST is required for the smtp mail driver")
+		}
+		return NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom), nil
+	case "file":
+		return NewFileMailer(cfg.MailDir, cfg.MailFrom)
+	case "log":
+		return NewLogMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.MailDriver)
+	}
+}
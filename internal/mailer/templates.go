@@ -0,0 +1,69 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Each email in the templates directory has a NAME.txt.tmpl and a
+// NAME.html.tmpl file. The text template also defines NAME.subject.
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var (
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/*.txt.tmpl"))
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/*.html.tmpl"))
+)
+
+// Render builds the named email for a recipient from its templates
+func Render(name, to string, data any) (Message, error) {
+	var subject, text, html bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&subject, name+".subject", data); err != nil {
+		return Message{}, err
+	}
+	if err := textTemplates.ExecuteTemplate(&text, name+".txt.tmpl", data); err != nil {
+		return Message{}, err
+	}
+	if err := htmlTemplates.ExecuteTemplate(&html, name+".html.tmpl", data); err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		To:      to,
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    html.String(),
+	}, nil
+}
+
+// PasswordResetData fills the password_reset email
+type PasswordResetData struct {
+	FirstName        string
+	ResetURL         string
+	ExpiresInMinutes int
+}
+
+// PasswordChangedData fills the password_changed email
+type PasswordChangedData struct {
+	FirstName string
+}
+
+// PaymentReminderData fills the payment_reminder email. Stage counts up from
+// 1 for the first reminder, the last one is the final notice.
+type PaymentReminderData struct {
+	FirstName   string
+	SeasonName  string
+	AmountDue   string
+	Stage       int
+	FinalNotice bool
+	// LockoutDate is when an unpaid player is locked out of game rosters, empty
+	// when they will not be
+	LockoutDate string
+	LockedOut   bool
+	PayURL      string
+	SettingsURL string
+}
@@ -1,14 +1,22 @@
 package middleware
 
 import (
+	"context"
+	"log/slog"
 	"net/http"
 
 	"github.com/gbart/fcabl-api/internal/auth"
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware validates JWT tokens from HTTP-only cookies
-func AuthMiddleware(jwtService *auth.JWTService) gin.HandlerFunc {
+// SessionStore reports whether a login session is still active
+type SessionStore interface {
+	IsSessionActive(ctx context.Context, id int64) (bool, error)
+}
+
+// AuthMiddleware validates JWT tokens from HTTP-only cookies and rejects tokens
+// whose session has been revoked
+func AuthMiddleware(jwtService *auth.JWTService, sessions SessionStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get token from cookie
 		token, err := c.Cookie("auth_token")
@@ -26,31 +34,43 @@ func AuthMiddleware(jwtService *auth.JWTService) gin.HandlerFunc {
 			return
 		}
 
+		// Check the session has not been revoked
+		active, err := sessions.IsSessionActive(c.Request.Context(), claims.SessionID)
+		if err != nil {
+			slog.Error("Failed to check session", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Authentication failed"})
+			c.Abort()
+			return
+		}
+		if !active {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked"})
+			c.Abort()
+			return
+		}
+
 		// Store user info in context for handlers to use
 		c.Set("userID", claims.UserID)
 		c.Set("userEmail", claims.Email)
 		c.Set("userRole", claims.Role)
+		c.Set("sessionID", claims.SessionID)
 
 		c.Next()
 	}
 }
 
-// AdminMiddleware checks if user has admin role
-func AdminMiddleware() gin.HandlerFunc {
+// RequirePermission checks the user's role grants the permission. The scope
+// of the grant is stored in the context, handlers that allow scoped access
+// check the team or game being acted on themselves.
+func RequirePermission(permission auth.Permission) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("userRole")
-
-		// Debug logging
-		println("AdminMiddleware - role exists:", exists)
-		if exists {
-			println("AdminMiddleware - role value:", role)
-		}
-
-		if !exists || role != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		scope := auth.PermissionScope(c.GetString("userRole"), permission)
+		if scope == auth.ScopeNone {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
 			c.Abort()
 			return
 		}
+
+		c.Set("permissionScope", scope)
 		c.Next()
 	}
 }
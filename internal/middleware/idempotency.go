@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// IdempotencyKeyHeader is the request header carrying an idempotency key
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set on responses replayed for a repeated request
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength bounds the keys clients may send, a UUID is 36
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyStore claims idempotency keys and stores the responses of the
+// requests that claimed them
+type IdempotencyStore interface {
+	ClaimIdempotencyKey(ctx context.Context, arg repository.ClaimIdempotencyKeyParams) (repository.IdempotencyKey, error)
+	GetIdempotencyKey(ctx context.Context, arg repository.GetIdempotencyKeyParams) (repository.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, arg repository.CompleteIdempotencyKeyParams) error
+	ReleaseIdempotencyKey(ctx context.Context, arg repository.ReleaseIdempotencyKeyParams) error
+}
+
+// Idempotency honors the Idempotency-Key header on POST and PATCH requests
+// from signed in users, so a request retried over a flaky connection runs
+// once. The first request with a key runs and its response is stored for ttl,
+// repeats get the stored response back. Reusing a key for a different request
+// is rejected, as is a repeat while the first is still running. Server errors
+// are not stored, the request can be retried with the same key.
+//
+// Requests without the header, and other methods, are passed through.
+func Idempotency(store IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		userID := c.GetInt64("userID")
+		if key == "" || userID == 0 || (c.Request.Method != http.MethodPost && c.Request.Method != http.MethodPatch) {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key must be at most 255 characters."})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body."})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		io.WriteString(hash, c.Request.Method+" "+c.Request.URL.RequestURI()+"\n")
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		// The outcome is recorded even if the client gives up waiting, so its
+		// retry gets the response it missed
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		_, err = store.ClaimIdempotencyKey(ctx, repository.ClaimIdempotencyKeyParams{
+			UserID:         userID,
+			IdempotencyKey: key,
+			Method:         c.Request.Method,
+			Path:           c.Request.URL.RequestURI(),
+			RequestHash:    requestHash,
+			ExpiresAt:      pgtype.Timestamp{Time: time.Now().Add(ttl), Valid: true},
+		})
+		if err == pgx.ErrNoRows {
+			replay(ctx, c, store, userID, key, requestHash)
+			return
+		}
+		if err != nil {
+			slog.Error("Failed to claim idempotency key", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process request."})
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			if err := store.ReleaseIdempotencyKey(ctx, repository.ReleaseIdempotencyKeyParams{
+				UserID:         userID,
+				IdempotencyKey: key,
+			}); err != nil {
+				slog.Error("Failed to release idempotency key", "error", err)
+			}
+		}()
+
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		if err := store.CompleteIdempotencyKey(ctx, repository.CompleteIdempotencyKeyParams{
+			StatusCode:     pgtype.Int4{Int32: int32(status), Valid: true},
+			ContentType:    recorder.Header().Get("Content-Type"),
+			ResponseBody:   recorder.body.Bytes(),
+			UserID:         userID,
+			IdempotencyKey: key,
+		}); err != nil {
+			slog.Error("Failed to store idempotent response", "error", err)
+			return
+		}
+		completed = true
+	}
+}
+
+// replay responds to a request whose key is already held with the stored
+// response, or rejects it if the key was used for another request or its
+// first request is still running
+func replay(ctx context.Context, c *gin.Context, store IdempotencyStore, userID int64, key, requestHash string) {
+	defer c.Abort()
+
+	stored, err := store.GetIdempotencyKey(ctx, repository.GetIdempotencyKeyParams{
+		UserID:         userID,
+		IdempotencyKey: key,
+	})
+	if err == pgx.ErrNoRows {
+		// Released by a failed first request between claiming and looking
+		c.JSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key failed. Please retry."})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to fetch idempotency key", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process request."})
+		return
+	}
+
+	if stored.RequestHash != requestHash {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key has already been used for a different request."})
+		return
+	}
+	if !stored.StatusCode.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is still being processed."})
+		return
+	}
+
+	slog.Info("Replaying idempotent response", "userId", userID, "path", stored.Path)
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(int(stored.StatusCode.Int32), stored.ContentType, stored.ResponseBody)
+}
+
+// responseRecorder keeps a copy of the response body as it is written
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
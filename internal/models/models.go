@@ -2,7 +2,14 @@
 package models
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gbart/fcabl-api/internal/auth"
 	"github.com/gbart/fcabl-api/internal/repository"
+	"github.com/gbart/fcabl-api/internal/schedule"
+	"github.com/gbart/fcabl-api/internal/stats"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -12,7 +19,7 @@ type CreateUserRequest struct {
 	PasswordHash string `json:"passwordHash" binding:"required"`
 	FirstName    string `json:"firstName" binding:"required"`
 	LastName     string `json:"lastName" binding:"required"`
-	Role         string `json:"role" binding:"required"`
+	Role         string `json:"role" binding:"required,oneof=normal admin captain scorekeeper treasurer referee"`
 }
 
 func (rq *CreateUserRequest) IntoDBModel() repository.CreateUserParams {
@@ -31,7 +38,7 @@ type UpdateUserRequest struct {
 	PhoneNumber string           `json:"phoneNumber" binding:"required"`
 	FirstName   string           `json:"firstName" binding:"required"`
 	LastName    string           `json:"lastName" binding:"required"`
-	Role        string           `json:"role" binding:"required"`
+	Role        string           `json:"role" binding:"required,oneof=normal admin captain scorekeeper treasurer referee"`
 	UpdatedAt   pgtype.Timestamp `json:"updatedAt" binding:"required"`
 	ID          int64            `json:"id" binding:"required"`
 }
@@ -49,45 +56,103 @@ func (rq *UpdateUserRequest) IntoDBModel() repository.UpdateUserParams {
 }
 
 // Team request models
+// Win/loss records and points are derived from completed games, so they are
+// not accepted here. See RecomputeTeamStandings.
+// SeasonID defaults to the current season when omitted.
 type CreateTeamRequest struct {
-	Name          string `json:"name" binding:"required"`
-	Wins          int32  `json:"wins"`
-	Losses        int32  `json:"losses"`
-	Draws         int32  `json:"draws"`
-	PointsFor     int32  `json:"pointsFor"`
-	PointsAgainst int32  `json:"pointsAgainst"`
+	Name       string      `json:"name" binding:"required"`
+	SeasonID   pgtype.Int8 `json:"seasonId"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
 }
 
 func (rq *CreateTeamRequest) IntoDBModel() repository.CreateTeamParams {
 	return repository.CreateTeamParams{
-		Name:          rq.Name,
-		Wins:          rq.Wins,
-		Losses:        rq.Losses,
-		Draws:         rq.Draws,
-		PointsFor:     rq.PointsFor,
-		PointsAgainst: rq.PointsAgainst,
+		Name:       rq.Name,
+		SeasonID:   rq.SeasonID,
+		DivisionID: rq.DivisionID,
 	}
 }
 
 type UpdateTeamRequest struct {
-	ID            int64  `json:"id" binding:"required"`
-	Name          string `json:"name" binding:"required"`
-	Wins          int32  `json:"wins" binding:"required"`
-	Losses        int32  `json:"losses" binding:"required"`
-	Draws         int32  `json:"draws" binding:"required"`
-	PointsFor     int32  `json:"pointsFor" binding:"required"`
-	PointsAgainst int32  `json:"pointsAgainst" binding:"required"`
+	ID         int64       `json:"id" binding:"required"`
+	Name       string      `json:"name" binding:"required"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
 }
 
 func (rq *UpdateTeamRequest) IntoDBModel() repository.UpdateTeamParams {
 	return repository.UpdateTeamParams{
-		ID:            rq.ID,
-		Name:          rq.Name,
-		Wins:          rq.Wins,
-		Losses:        rq.Losses,
-		Draws:         rq.Draws,
-		PointsFor:     rq.PointsFor,
-		PointsAgainst: rq.PointsAgainst,
+		ID:         rq.ID,
+		Name:       rq.Name,
+		DivisionID: rq.DivisionID,
+	}
+}
+
+// Season request models
+type CreateSeasonRequest struct {
+	Name            string         `json:"name" binding:"required"`
+	StartDate       pgtype.Date    `json:"startDate" binding:"required"`
+	EndDate         pgtype.Date    `json:"endDate" binding:"required"`
+	RegistrationFee pgtype.Numeric `json:"registrationFee" binding:"required"`
+}
+
+func (rq *CreateSeasonRequest) IntoDBModel() repository.CreateSeasonParams {
+	return repository.CreateSeasonParams{
+		Name:            rq.Name,
+		StartDate:       rq.StartDate,
+		EndDate:         rq.EndDate,
+		RegistrationFee: rq.RegistrationFee,
+	}
+}
+
+type UpdateSeasonRequest struct {
+	ID              int64          `json:"id" binding:"required"`
+	Name            string         `json:"name" binding:"required"`
+	StartDate       pgtype.Date    `json:"startDate" binding:"required"`
+	EndDate         pgtype.Date    `json:"endDate" binding:"required"`
+	RegistrationFee pgtype.Numeric `json:"registrationFee" binding:"required"`
+}
+
+func (rq *UpdateSeasonRequest) IntoDBModel() repository.UpdateSeasonParams {
+	return repository.UpdateSeasonParams{
+		ID:              rq.ID,
+		Name:            rq.Name,
+		StartDate:       rq.StartDate,
+		EndDate:         rq.EndDate,
+		RegistrationFee: rq.RegistrationFee,
+	}
+}
+
+type SetCurrentSeasonRequest struct {
+	ID int64 `json:"id" binding:"required"`
+}
+
+// Division request models
+// A division's registration fee overrides the season's when set.
+type CreateDivisionRequest struct {
+	SeasonID        int64          `json:"seasonId" binding:"required"`
+	Name            string         `json:"name" binding:"required"`
+	RegistrationFee pgtype.Numeric `json:"registrationFee"`
+}
+
+func (rq *CreateDivisionRequest) IntoDBModel() repository.CreateDivisionParams {
+	return repository.CreateDivisionParams{
+		SeasonID:        rq.SeasonID,
+		Name:            rq.Name,
+		RegistrationFee: rq.RegistrationFee,
+	}
+}
+
+type UpdateDivisionRequest struct {
+	ID              int64          `json:"id" binding:"required"`
+	Name            string         `json:"name" binding:"required"`
+	RegistrationFee pgtype.Numeric `json:"registrationFee"`
+}
+
+func (rq *UpdateDivisionRequest) IntoDBModel() repository.UpdateDivisionParams {
+	return repository.UpdateDivisionParams{
+		ID:              rq.ID,
+		Name:            rq.Name,
+		RegistrationFee: rq.RegistrationFee,
 	}
 }
 
@@ -96,43 +161,44 @@ func (rq *UpdateTeamRequest) IntoDBModel() repository.UpdateTeamParams {
 // value is passed, because the validation cannot distinguish between a false and no value because
 // of the zero value of bool.
 
+// Players are registered into their team's season, or into the current season
+// when they have no team.
+// The registration fee due and fully registered flag are not part of the
+// request, they are derived from the fee schedule and the player's payments.
+// The fee is assessed from the season's fee schedule, SiblingPlayerID and
+// PromoCode are optional and make the player eligible for their discounts.
 type CreatePlayerRequest struct {
-	UserID             int64          `json:"userId" binding:"required"`
-	TeamID             pgtype.Int8    `json:"teamId" binding:"required"`
-	RegistrationFeeDue pgtype.Numeric `json:"registrationFeeDue" binding:"required"`
-	IsFullyRegistered  bool           `json:"isFullyRegistered"`
-	IsActive           bool           `json:"isActive"`
-	JerseyNumber       pgtype.Int4    `json:"jerseyNumber" binding:"required"`
+	UserID          int64       `json:"userId" binding:"required"`
+	TeamID          pgtype.Int8 `json:"teamId" binding:"required"`
+	IsActive        bool        `json:"isActive"`
+	JerseyNumber    pgtype.Int4 `json:"jerseyNumber" binding:"required"`
+	SiblingPlayerID pgtype.Int8 `json:"siblingPlayerId"`
+	PromoCode       string      `json:"promoCode"`
 }
 
 func (rq *CreatePlayerRequest) IntoDBModel() repository.CreatePlayerParams {
 	return repository.CreatePlayerParams{
-		UserID:             rq.UserID,
-		TeamID:             rq.TeamID,
-		RegistrationFeeDue: rq.RegistrationFeeDue,
-		IsFullyRegistered:  rq.IsFullyRegistered,
-		IsActive:           rq.IsActive,
-		JerseyNumber:       rq.JerseyNumber,
+		UserID:          rq.UserID,
+		TeamID:          rq.TeamID,
+		IsActive:        rq.IsActive,
+		JerseyNumber:    rq.JerseyNumber,
+		SiblingPlayerID: rq.SiblingPlayerID,
 	}
 }
 
 type UpdatePlayerRequest struct {
-	ID                 int64          `json:"id" binding:"required"`
-	TeamID             pgtype.Int8    `json:"teamId" binding:"required"`
-	RegistrationFeeDue pgtype.Numeric `json:"registrationFeeDue" binding:"required"`
-	IsFullyRegistered  bool           `json:"isFullyRegistered"`
-	IsActive           bool           `json:"isActive"`
-	JerseyNumber       pgtype.Int4    `json:"jerseyNumber" binding:"required"`
+	ID           int64       `json:"id" binding:"required"`
+	TeamID       pgtype.Int8 `json:"teamId" binding:"required"`
+	IsActive     bool        `json:"isActive"`
+	JerseyNumber pgtype.Int4 `json:"jerseyNumber" binding:"required"`
 }
 
 func (rq *UpdatePlayerRequest) IntoDBModel() repository.UpdatePlayerParams {
 	return repository.UpdatePlayerParams{
-		ID:                 rq.ID,
-		TeamID:             rq.TeamID,
-		RegistrationFeeDue: rq.RegistrationFeeDue,
-		IsFullyRegistered:  rq.IsFullyRegistered,
-		IsActive:           rq.IsActive,
-		JerseyNumber:       rq.JerseyNumber,
+		ID:           rq.ID,
+		TeamID:       rq.TeamID,
+		IsActive:     rq.IsActive,
+		JerseyNumber: rq.JerseyNumber,
 	}
 }
 
@@ -148,20 +214,6 @@ func (rq *UpdatePlayerTeamRequest) IntoDBModel() repository.UpdatePlayerTeamPara
 	}
 }
 
-type UpdatePlayerRegistrationStatusRequest struct {
-	ID                 int64          `json:"id" binding:"required"`
-	RegistrationFeeDue pgtype.Numeric `json:"registrationFeeDue" binding:"required"`
-	IsFullyRegistered  bool           `json:"isFullyRegistered"`
-}
-
-func (rq *UpdatePlayerRegistrationStatusRequest) IntoDBModel() repository.UpdatePlayerRegistrationStatusParams {
-	return repository.UpdatePlayerRegistrationStatusParams{
-		ID:                 rq.ID,
-		RegistrationFeeDue: rq.RegistrationFeeDue,
-		IsFullyRegistered:  rq.IsFullyRegistered,
-	}
-}
-
 // Game request models
 type CreateGameRequest struct {
 	HomeTeamID int64            `json:"homeTeamId" binding:"required"`
@@ -227,13 +279,205 @@ func (rq *UpdateGameScoreAndStatusRequest) IntoDBModel() repository.UpdateGameSc
 	}
 }
 
+type ScheduleTimeSlot struct {
+	Weekday string `json:"weekday" binding:"required"`
+	Time    string `json:"time" binding:"required,datetime=15:04"`
+}
+
+// GenerateScheduleRequest describes a round-robin season to generate.
+// Rounds is the number of times each team plays every other team, so 1 is a
+// single round robin and 2 is a double round robin. With DryRun set the
+// schedule is only previewed and no games are created.
+type GenerateScheduleRequest struct {
+	TeamIDs   []int64            `json:"teamIds" binding:"required,min=2,dive,required"`
+	StartDate string             `json:"startDate" binding:"required,datetime=2006-01-02"`
+	TimeSlots []ScheduleTimeSlot `json:"timeSlots" binding:"required,min=1,dive"`
+	Rounds    int                `json:"rounds" binding:"omitempty,min=1,max=4"`
+	DryRun    bool               `json:"dryRun"`
+}
+
+func (rq *GenerateScheduleRequest) IntoScheduleOptions() (schedule.Options, error) {
+	startDate, err := time.Parse(time.DateOnly, rq.StartDate)
+	if err != nil {
+		return schedule.Options{}, fmt.Errorf("invalid start date %q", rq.StartDate)
+	}
+
+	slots := make([]schedule.Slot, len(rq.TimeSlots))
+	for i, ts := range rq.TimeSlots {
+		weekday, ok := weekdays[strings.ToLower(ts.Weekday)]
+		if !ok {
+			return schedule.Options{}, fmt.Errorf("invalid weekday %q", ts.Weekday)
+		}
+		clock, err := time.Parse("15:04", ts.Time)
+		if err != nil {
+			return schedule.Options{}, fmt.Errorf("invalid time %q", ts.Time)
+		}
+		slots[i] = schedule.Slot{
+			Weekday: weekday,
+			Hour:    clock.Hour(),
+			Minute:  clock.Minute(),
+		}
+	}
+
+	cycles := rq.Rounds
+	if cycles == 0 {
+		cycles = 1
+	}
+
+	return schedule.Options{
+		TeamIDs:   rq.TeamIDs,
+		StartDate: startDate,
+		Slots:     slots,
+		Cycles:    cycles,
+	}, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// Game details (box score) request models
+
+// GameStats are the stats recorded for a player in a game besides points.
+// Field goals include three pointers.
+type GameStats struct {
+	Rebounds               int32 `json:"rebounds" binding:"min=0"`
+	Assists                int32 `json:"assists" binding:"min=0"`
+	Steals                 int32 `json:"steals" binding:"min=0"`
+	Blocks                 int32 `json:"blocks" binding:"min=0"`
+	Turnovers              int32 `json:"turnovers" binding:"min=0"`
+	Fouls                  int32 `json:"fouls" binding:"min=0"`
+	FieldGoalsMade         int32 `json:"fieldGoalsMade" binding:"min=0"`
+	FieldGoalsAttempted    int32 `json:"fieldGoalsAttempted" binding:"min=0"`
+	ThreePointersMade      int32 `json:"threePointersMade" binding:"min=0"`
+	ThreePointersAttempted int32 `json:"threePointersAttempted" binding:"min=0"`
+	FreeThrowsMade         int32 `json:"freeThrowsMade" binding:"min=0"`
+	FreeThrowsAttempted    int32 `json:"freeThrowsAttempted" binding:"min=0"`
+}
+
+type CreateGameDetailsRequest struct {
+	GameID   int64 `json:"gameId" binding:"required"`
+	PlayerID int64 `json:"playerId" binding:"required"`
+	Score    int32 `json:"score" binding:"min=0"`
+	GameStats
+}
+
+func (rq *CreateGameDetailsRequest) IntoDBModel() repository.CreateGameDetailsParams {
+	return repository.CreateGameDetailsParams{
+		GameID:                 rq.GameID,
+		PlayerID:               rq.PlayerID,
+		Score:                  rq.Score,
+		Rebounds:               rq.Rebounds,
+		Assists:                rq.Assists,
+		Steals:                 rq.Steals,
+		Blocks:                 rq.Blocks,
+		Turnovers:              rq.Turnovers,
+		Fouls:                  rq.Fouls,
+		FieldGoalsMade:         rq.FieldGoalsMade,
+		FieldGoalsAttempted:    rq.FieldGoalsAttempted,
+		ThreePointersMade:      rq.ThreePointersMade,
+		ThreePointersAttempted: rq.ThreePointersAttempted,
+		FreeThrowsMade:         rq.FreeThrowsMade,
+		FreeThrowsAttempted:    rq.FreeThrowsAttempted,
+	}
+}
+
+type UpdateGameDetailsScoreRequest struct {
+	GameID   int64 `json:"gameId" binding:"required"`
+	PlayerID int64 `json:"playerId" binding:"required"`
+	Score    int32 `json:"score" binding:"min=0"`
+}
+
+func (rq *UpdateGameDetailsScoreRequest) IntoDBModel() repository.UpdateGameDetailsScoreParams {
+	return repository.UpdateGameDetailsScoreParams{
+		GameID:   rq.GameID,
+		PlayerID: rq.PlayerID,
+		Score:    rq.Score,
+	}
+}
+
+type BoxScoreEntry struct {
+	PlayerID int64 `json:"playerId" binding:"required"`
+	Score    int32 `json:"score" binding:"min=0"`
+	GameStats
+}
+
+// UpsertBoxScoreRequest submits the whole box score for a game at once.
+// Players already in the box score are updated, new players are inserted.
+type UpsertBoxScoreRequest struct {
+	GameID  int64           `json:"gameId" binding:"required"`
+	Players []BoxScoreEntry `json:"players" binding:"required,min=1,dive"`
+}
+
+func (rq *UpsertBoxScoreRequest) IntoDBModels() []repository.UpsertGameDetailsParams {
+	params := make([]repository.UpsertGameDetailsParams, len(rq.Players))
+	for i, entry := range rq.Players {
+		params[i] = repository.UpsertGameDetailsParams{
+			GameID:                 rq.GameID,
+			PlayerID:               entry.PlayerID,
+			Score:                  entry.Score,
+			Rebounds:               entry.Rebounds,
+			Assists:                entry.Assists,
+			Steals:                 entry.Steals,
+			Blocks:                 entry.Blocks,
+			Turnovers:              entry.Turnovers,
+			Fouls:                  entry.Fouls,
+			FieldGoalsMade:         entry.FieldGoalsMade,
+			FieldGoalsAttempted:    entry.FieldGoalsAttempted,
+			ThreePointersMade:      entry.ThreePointersMade,
+			ThreePointersAttempted: entry.ThreePointersAttempted,
+			FreeThrowsMade:         entry.FreeThrowsMade,
+			FreeThrowsAttempted:    entry.FreeThrowsAttempted,
+		}
+	}
+	return params
+}
+
+// RecordGameEventRequest records a play in a game's play-by-play. ClockSeconds
+// is the time left in the period. Scores, fouls and substitutions are credited
+// to the team of PlayerID, timeouts to TeamID.
+type RecordGameEventRequest struct {
+	GameID       int64       `json:"gameId" binding:"required"`
+	Kind         string      `json:"kind" binding:"required,oneof=score foul timeout substitution period_start period_end"`
+	Period       int32       `json:"period" binding:"required,min=1"`
+	ClockSeconds int32       `json:"clockSeconds" binding:"min=0"`
+	TeamID       pgtype.Int8 `json:"teamId"`
+	PlayerID     pgtype.Int8 `json:"playerId"`
+	PlayerOutID  pgtype.Int8 `json:"playerOutId"`
+	Points       pgtype.Int4 `json:"points"`
+}
+
+func (rq *RecordGameEventRequest) IntoDBModel(createdBy int64) repository.CreateGameEventParams {
+	return repository.CreateGameEventParams{
+		GameID:       rq.GameID,
+		Kind:         rq.Kind,
+		Period:       rq.Period,
+		ClockSeconds: rq.ClockSeconds,
+		TeamID:       rq.TeamID,
+		PlayerID:     rq.PlayerID,
+		PlayerOutID:  rq.PlayerOutID,
+		Points:       rq.Points,
+		CreatedBy:    pgtype.Int8{Int64: createdBy, Valid: true},
+	}
+}
+
+type UndoGameEventRequest struct {
+	EventID int64 `json:"eventId" binding:"required"`
+}
+
 // Payment request models
 
 type CreatePaymentRequest struct {
 	PlayerID int64          `json:"playerId" binding:"required"`
 	StripeID string         `json:"stripeId" binding:"required"`
 	Amount   pgtype.Numeric `json:"amount" binding:"required"`
-	Status   string         `json:"status" binding:"required"`
+	Status   string         `json:"status" binding:"required,oneof=completed pending failed"`
 }
 
 func (rq *CreatePaymentRequest) IntoDBModel() repository.CreatePaymentParams {
@@ -245,9 +489,18 @@ func (rq *CreatePaymentRequest) IntoDBModel() repository.CreatePaymentParams {
 	}
 }
 
+// CreateRefundRequest refunds part of a payment, or what is left of it when no
+// amount is given
+type CreateRefundRequest struct {
+	PaymentID int64          `json:"paymentId" binding:"required"`
+	Amount    pgtype.Numeric `json:"amount"`
+	Reason    string         `json:"reason" binding:"required,max=500"`
+}
+
+// Payments are marked refunded by their refunds, see CreateRefundRequest
 type UpdatePaymentStatusRequest struct {
 	ID     int64  `json:"id" binding:"required"`
-	Status string `json:"status" binding:"required"`
+	Status string `json:"status" binding:"required,oneof=completed pending failed"`
 }
 
 func (rq *UpdatePaymentStatusRequest) IntoDBModel() repository.UpdatePaymentStatusParams {
@@ -310,4 +563,356 @@ type PlayerGameStats struct {
 	PlayerLastName  string      `json:"playerLastName"`
 	Number          pgtype.Int4 `json:"number"`
 	Score           int32       `json:"score"`
+	GameStats
+}
+
+func NewPlayerGameStats(detail repository.ListGameDetailsVerboseRow) PlayerGameStats {
+	return PlayerGameStats{
+		PlayerID:        detail.PlayerID,
+		PlayerFirstName: detail.FirstName,
+		PlayerLastName:  detail.LastName,
+		Number:          detail.JerseyNumber,
+		Score:           detail.Score,
+		GameStats: GameStats{
+			Rebounds:               detail.Rebounds,
+			Assists:                detail.Assists,
+			Steals:                 detail.Steals,
+			Blocks:                 detail.Blocks,
+			Turnovers:              detail.Turnovers,
+			Fouls:                  detail.Fouls,
+			FieldGoalsMade:         detail.FieldGoalsMade,
+			FieldGoalsAttempted:    detail.FieldGoalsAttempted,
+			ThreePointersMade:      detail.ThreePointersMade,
+			ThreePointersAttempted: detail.ThreePointersAttempted,
+			FreeThrowsMade:         detail.FreeThrowsMade,
+			FreeThrowsAttempted:    detail.FreeThrowsAttempted,
+		},
+	}
+}
+
+// PlayerSeasonStats is a player's summary for a season of completed games,
+// and the games it is made from. It is public, so only has the player's name.
+type PlayerSeasonStats struct {
+	PlayerID     int64       `json:"playerId"`
+	FirstName    string      `json:"firstName"`
+	LastName     string      `json:"lastName"`
+	SeasonID     int64       `json:"seasonId"`
+	SeasonName   string      `json:"seasonName"`
+	TeamID       pgtype.Int8 `json:"teamId"`
+	TeamName     pgtype.Text `json:"teamName"`
+	JerseyNumber pgtype.Int4 `json:"jerseyNumber"`
+	stats.Summary
+	Games []repository.ListPlayerCareerGameStatsRow `json:"games"`
+}
+
+// PlayerCareerStats is a player's stats for each season they played, oldest
+// first, and for their whole career
+type PlayerCareerStats struct {
+	FirstName string              `json:"firstName"`
+	LastName  string              `json:"lastName"`
+	Seasons   []PlayerSeasonStats `json:"seasons"`
+	Career    stats.Summary       `json:"career"`
+}
+
+// ScheduledGame is a game produced by the schedule generator. ID is only set
+// once the game has been created.
+type ScheduledGame struct {
+	ID           int64            `json:"id,omitempty"`
+	Round        int              `json:"round"`
+	HomeTeamID   int64            `json:"homeTeamId"`
+	HomeTeamName string           `json:"homeTeamName"`
+	AwayTeamID   int64            `json:"awayTeamId"`
+	AwayTeamName string           `json:"awayTeamName"`
+	GameTime     pgtype.Timestamp `json:"gameTime"`
+}
+
+type GeneratedSchedule struct {
+	DryRun bool            `json:"dryRun"`
+	Games  []ScheduledGame `json:"games"`
+	Byes   []schedule.Bye  `json:"byes"`
+}
+
+// Fee schedule and promo code request models
+type UpsertFeeScheduleRequest struct {
+	SeasonID                int64          `json:"seasonId" binding:"required"`
+	EarlyBirdDeadline       pgtype.Date    `json:"earlyBirdDeadline"`
+	EarlyBirdDiscount       pgtype.Numeric `json:"earlyBirdDiscount"`
+	LateDeadline            pgtype.Date    `json:"lateDeadline"`
+	LateFee                 pgtype.Numeric `json:"lateFee"`
+	ReturningPlayerDiscount pgtype.Numeric `json:"returningPlayerDiscount"`
+	SiblingDiscount         pgtype.Numeric `json:"siblingDiscount"`
+}
+
+func (rq *UpsertFeeScheduleRequest) IntoDBModel() repository.UpsertFeeScheduleParams {
+	return repository.UpsertFeeScheduleParams{
+		SeasonID:                rq.SeasonID,
+		EarlyBirdDeadline:       rq.EarlyBirdDeadline,
+		EarlyBirdDiscount:       rq.EarlyBirdDiscount,
+		LateDeadline:            rq.LateDeadline,
+		LateFee:                 rq.LateFee,
+		ReturningPlayerDiscount: rq.ReturningPlayerDiscount,
+		SiblingDiscount:         rq.SiblingDiscount,
+	}
+}
+
+// Promo codes are stored upper case and matched case-insensitively. A code
+// without a season is valid in every season. Percent discounts are given in
+// percent, e.g. 15 for 15% off.
+type CreatePromoCodeRequest struct {
+	SeasonID       pgtype.Int8    `json:"seasonId"`
+	Code           string         `json:"code" binding:"required,alphanum,max=32"`
+	Description    string         `json:"description"`
+	DiscountType   string         `json:"discountType" binding:"required,oneof=amount percent"`
+	DiscountValue  pgtype.Numeric `json:"discountValue" binding:"required"`
+	MaxRedemptions pgtype.Int4    `json:"maxRedemptions"`
+	ValidFrom      pgtype.Date    `json:"validFrom"`
+	ValidUntil     pgtype.Date    `json:"validUntil"`
+}
+
+func (rq *CreatePromoCodeRequest) IntoDBModel() repository.CreatePromoCodeParams {
+	return repository.CreatePromoCodeParams{
+		SeasonID:       rq.SeasonID,
+		Code:           strings.ToUpper(rq.Code),
+		Description:    rq.Description,
+		DiscountType:   rq.DiscountType,
+		DiscountValue:  rq.DiscountValue,
+		MaxRedemptions: rq.MaxRedemptions,
+		ValidFrom:      rq.ValidFrom,
+		ValidUntil:     rq.ValidUntil,
+	}
+}
+
+type UpdatePromoCodeRequest struct {
+	ID             int64          `json:"id" binding:"required"`
+	Description    string         `json:"description"`
+	DiscountType   string         `json:"discountType" binding:"required,oneof=amount percent"`
+	DiscountValue  pgtype.Numeric `json:"discountValue" binding:"required"`
+	MaxRedemptions pgtype.Int4    `json:"maxRedemptions"`
+	ValidFrom      pgtype.Date    `json:"validFrom"`
+	ValidUntil     pgtype.Date    `json:"validUntil"`
+	IsActive       bool           `json:"isActive"`
+}
+
+func (rq *UpdatePromoCodeRequest) IntoDBModel() repository.UpdatePromoCodeParams {
+	return repository.UpdatePromoCodeParams{
+		ID:             rq.ID,
+		Description:    rq.Description,
+		DiscountType:   rq.DiscountType,
+		DiscountValue:  rq.DiscountValue,
+		MaxRedemptions: rq.MaxRedemptions,
+		ValidFrom:      rq.ValidFrom,
+		ValidUntil:     rq.ValidUntil,
+		IsActive:       rq.IsActive,
+	}
+}
+
+type ApplyPromoCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// AssessRegistrationFeeRequest re-assesses a player's fee, optionally
+// redeeming a promo code for them first
+type AssessRegistrationFeeRequest struct {
+	PlayerID  int64  `json:"playerId" binding:"required"`
+	PromoCode string `json:"promoCode"`
+}
+
+// RegistrationFees is the breakdown of a player's assessed registration fee
+// and what is still due after their payments
+type RegistrationFees struct {
+	PlayerID           int64                            `json:"playerId"`
+	RegistrationFee    pgtype.Numeric                   `json:"registrationFee"`
+	RegistrationFeeDue pgtype.Numeric                   `json:"registrationFeeDue"`
+	IsFullyRegistered  bool                             `json:"isFullyRegistered"`
+	Items              []repository.RegistrationFeeItem `json:"items"`
+}
+
+// Payment plan request models
+
+// CreatePaymentPlanRequest puts a player on a payment plan. The installments
+// must add up to the player's outstanding registration fee.
+type CreatePaymentPlanRequest struct {
+	PlayerID     int64                `json:"playerId" binding:"required"`
+	Notes        string               `json:"notes" binding:"max=500"`
+	Installments []InstallmentRequest `json:"installments" binding:"required,min=1,max=24,dive"`
+}
+
+type InstallmentRequest struct {
+	Amount  pgtype.Numeric `json:"amount" binding:"required"`
+	DueDate pgtype.Date    `json:"dueDate" binding:"required"`
+}
+
+type CancelPaymentPlanRequest struct {
+	ID int64 `json:"id" binding:"required"`
+}
+
+// PaymentPlan is a payment plan with its installments in order
+type PaymentPlan struct {
+	repository.PaymentPlan
+	Installments []repository.PaymentPlanInstallment `json:"installments"`
+}
+
+// ProviderReconciliation is the result of matching the payment provider's
+// payments, from its API or an export, against the payments table
+type ProviderReconciliation struct {
+	Provider string    `json:"provider"`
+	Source   string    `json:"source"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	// Checked is the number of provider payments, Matched how many of them
+	// have a payment
+	Checked int                           `json:"checked"`
+	Matched int                           `json:"matched"`
+	Issues  []ProviderReconciliationIssue `json:"issues"`
+	Applied bool                          `json:"applied"`
+}
+
+// ProviderReconciliationIssue is a payment that is missing on one side or
+// disagrees with the provider. Fixable issues are fixed when the
+// reconciliation is applied.
+type ProviderReconciliationIssue struct {
+	Kind           string         `json:"kind"`
+	StripeID       string         `json:"stripeId"`
+	PaymentID      pgtype.Int8    `json:"paymentId"`
+	PlayerID       pgtype.Int8    `json:"playerId"`
+	Amount         pgtype.Numeric `json:"amount"`
+	ProviderAmount pgtype.Numeric `json:"providerAmount"`
+	Status         string         `json:"status,omitempty"`
+	ProviderStatus string         `json:"providerStatus,omitempty"`
+	Fixable        bool           `json:"fixable"`
+	Fixed          bool           `json:"fixed"`
+}
+
+// PaymentRemindersRequest turns the signed in user's registration fee
+// reminder emails off or back on
+type PaymentRemindersRequest struct {
+	OptOut bool `json:"optOut"`
+}
+
+// Ledger request models
+
+// WriteOffRequest writes off some or all of what a player owes
+type WriteOffRequest struct {
+	PlayerID int64          `json:"playerId" binding:"required"`
+	Amount   pgtype.Numeric `json:"amount" binding:"required"`
+	Reason   string         `json:"reason" binding:"required,max=500"`
+}
+
+// PlayerLedger is a player's receivable account, their transactions with the
+// balance after each
+type PlayerLedger struct {
+	PlayerID     int64                            `json:"playerId"`
+	Balance      pgtype.Numeric                   `json:"balance"`
+	Transactions []repository.ListPlayerLedgerRow `json:"transactions"`
+}
+
+// TrialBalance totals the debits and credits of every ledger account for a
+// season's transactions. The books balance when the totals are equal.
+type TrialBalance struct {
+	SeasonID     int64                                    `json:"seasonId"`
+	AsOf         pgtype.Date                              `json:"asOf"`
+	Accounts     []TrialBalanceAccount                    `json:"accounts"`
+	TotalDebits  pgtype.Numeric                           `json:"totalDebits"`
+	TotalCredits pgtype.Numeric                           `json:"totalCredits"`
+	Balanced     bool                                     `json:"balanced"`
+	Receivables  []repository.ListLedgerPlayerBalancesRow `json:"receivables"`
+}
+
+// TrialBalanceAccount is one line of a trial balance. Balance is debits less
+// credits.
+type TrialBalanceAccount struct {
+	Account string         `json:"account"`
+	Type    string         `json:"type"`
+	Debits  pgtype.Numeric `json:"debits"`
+	Credits pgtype.Numeric `json:"credits"`
+	Balance pgtype.Numeric `json:"balance"`
+}
+
+// Bracket request models
+
+// CreateBracketRequest seeds a playoff bracket from the standings of a season,
+// or of one division in it. TeamCount limits the bracket to the top teams and
+// defaults to every team. Top seeds get byes when the count is not a power of two.
+type CreateBracketRequest struct {
+	Name       string      `json:"name" binding:"required"`
+	SeasonID   int64       `json:"seasonId"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
+	TeamCount  int         `json:"teamCount" binding:"omitempty,min=2"`
+}
+
+type ScheduleBracketMatchRequest struct {
+	MatchID  int64            `json:"matchId" binding:"required"`
+	GameTime pgtype.Timestamp `json:"gameTime" binding:"required"`
+}
+
+type BracketRound struct {
+	Round   int32                              `json:"round"`
+	Matches []repository.ListBracketMatchesRow `json:"matches"`
+}
+
+type BracketTree struct {
+	repository.Bracket
+	Rounds []BracketRound `json:"rounds"`
+}
+
+// ActiveSession is a login session as listed to its user. Current marks the
+// session the request was made with.
+type ActiveSession struct {
+	repository.ListActiveSessionsByUserRow
+	Current bool `json:"current"`
+}
+
+// Role assignment request models
+
+type TeamCaptainRequest struct {
+	TeamID int64 `json:"teamId" binding:"required"`
+	UserID int64 `json:"userId" binding:"required"`
+}
+
+func (rq *TeamCaptainRequest) IntoDBModel() repository.AddTeamCaptainParams {
+	return repository.AddTeamCaptainParams{
+		TeamID: rq.TeamID,
+		UserID: rq.UserID,
+	}
+}
+
+type GameAssignmentRequest struct {
+	GameID int64 `json:"gameId" binding:"required"`
+	UserID int64 `json:"userId" binding:"required"`
+}
+
+func (rq *GameAssignmentRequest) IntoDBModel() repository.AssignUserToGameParams {
+	return repository.AssignUserToGameParams{
+		GameID: rq.GameID,
+		UserID: rq.UserID,
+	}
+}
+
+// UserAccess describes what the signed in user may do. Teams and Games are
+// the teams they captain and the games they are assigned to.
+type UserAccess struct {
+	Role        string            `json:"role"`
+	Permissions []auth.Permission `json:"permissions"`
+	Teams       []repository.Team `json:"teams"`
+	Games       []repository.Game `json:"games"`
+}
+
+// CheckoutSessionResponse is a checkout session the player is sent to in
+// order to pay. The payment stays pending until the provider reports it paid.
+type CheckoutSessionResponse struct {
+	PaymentID int64            `json:"paymentId"`
+	SessionID string           `json:"sessionId"`
+	URL       string           `json:"url"`
+	Amount    pgtype.Numeric   `json:"amount"`
+	ExpiresAt pgtype.Timestamp `json:"expiresAt"`
+}
+
+func NewCheckoutSessionResponse(payment repository.Payment) CheckoutSessionResponse {
+	return CheckoutSessionResponse{
+		PaymentID: payment.ID,
+		SessionID: payment.StripeID,
+		URL:       payment.CheckoutUrl.String,
+		Amount:    payment.Amount,
+		ExpiresAt: payment.CheckoutExpiresAt,
+	}
 }
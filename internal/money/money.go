@@ -0,0 +1,61 @@
+// Package money converts between amounts in cents, which payment providers
+// use, and the DECIMAL(10, 2) columns amounts are stored in.
+package money
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ToNumeric converts an amount in cents to a DECIMAL(10, 2) value
+func ToNumeric(cents int64) pgtype.Numeric {
+	return pgtype.Numeric{Int: big.NewInt(cents), Exp: -2, Valid: true}
+}
+
+// FromNumeric converts a DECIMAL value to cents, dropping fractions of a cent.
+// NULL is treated as zero.
+func FromNumeric(n pgtype.Numeric) int64 {
+	if !n.Valid {
+		return 0
+	}
+	v := new(big.Int).Set(n.Int)
+	ten := big.NewInt(10)
+	for exp := n.Exp + 2; exp > 0; exp-- {
+		v.Mul(v, ten)
+	}
+	for exp := n.Exp + 2; exp < 0; exp++ {
+		v.Quo(v, ten)
+	}
+	return v.Int64()
+}
+
+// Format formats cents as a dollar amount, e.g. $12.50
+func Format(cents int64) string {
+	if cents < 0 {
+		return "-$" + Decimal(-cents)
+	}
+	return "$" + Decimal(cents)
+}
+
+// Decimal formats cents as a plain decimal amount for spreadsheets and
+// accounting imports, e.g. 12.50 or -3.00
+func Decimal(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// Parse parses a dollar amount such as 1,025.50 into cents
+func Parse(s string) (int64, error) {
+	var n pgtype.Numeric
+	if err := n.Scan(strings.ReplaceAll(strings.TrimPrefix(strings.TrimSpace(s), "$"), ",", "")); err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return FromNumeric(n), nil
+}
@@ -0,0 +1,71 @@
+package money
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestFromNumeric(t *testing.T) {
+	tests := []struct {
+		name string
+		n    pgtype.Numeric
+		want int64
+	}{
+		{"null", pgtype.Numeric{}, 0},
+		{"cents", pgtype.Numeric{Int: big.NewInt(1250), Exp: -2, Valid: true}, 1250},
+		{"whole dollars", pgtype.Numeric{Int: big.NewInt(12), Exp: 0, Valid: true}, 1200},
+		{"positive exponent", pgtype.Numeric{Int: big.NewInt(3), Exp: 2, Valid: true}, 30000},
+		{"fraction of a cent", pgtype.Numeric{Int: big.NewInt(12509), Exp: -3, Valid: true}, 1250},
+		{"negative", pgtype.Numeric{Int: big.NewInt(-1250), Exp: -2, Valid: true}, -1250},
+		{"negative fraction of a cent", pgtype.Numeric{Int: big.NewInt(-12509), Exp: -3, Valid: true}, -1250},
+		{"round trip", ToNumeric(-99), -99},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromNumeric(tt.n); got != tt.want {
+				t.Errorf("FromNumeric() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{in: "12.50", want: 1250},
+		{in: "$12.50", want: 1250},
+		{in: "  $7 ", want: 700},
+		{in: "1,025.50", want: 102550},
+		{in: "$1,234,567.89", want: 123456789},
+		{in: "-3.00", want: -300},
+		{in: "-1,025.5", want: -102550},
+		{in: "0.999", want: 99},
+		{in: "", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "12.50 USD", wantErr: true},
+		{in: "1.5e2", wantErr: true},
+		{in: "25E-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Parse(%q) = %d, want an error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
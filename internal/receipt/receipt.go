@@ -0,0 +1,290 @@
+// Package receipt renders payment receipts and season invoices as PDFs, with
+// the league's name and contact details as a letterhead
+package receipt
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gbart/fcabl-api/internal/money"
+	"github.com/gbart/fcabl-api/internal/pdf"
+)
+
+// League is the letterhead printed at the top of every document
+type League struct {
+	Name    string
+	Address string
+	Email   string
+}
+
+// Receipt is a payment received from a player
+type Receipt struct {
+	League        League
+	PaymentID     int64
+	Date          time.Time
+	PlayerName    string
+	PlayerEmail   string
+	SeasonName    string
+	AmountCents   int64
+	RefundedCents int64
+	Status        string
+	Provider      string
+	Reference     string
+	PaymentIntent string
+	IssuedAt      time.Time
+}
+
+// Invoice is a player's charges, payments and balance for a season
+type Invoice struct {
+	League      League
+	PlayerID    int64
+	PlayerName  string
+	PlayerEmail string
+	SeasonName  string
+	Lines       []InvoiceLine
+	// BalanceCents is what the player owes, negative when they are in credit
+	BalanceCents int64
+	IssuedAt     time.Time
+}
+
+// InvoiceLine is one transaction on an invoice. Charges are positive, payments
+// and other credits negative.
+type InvoiceLine struct {
+	Date        time.Time
+	Description string
+	AmountCents int64
+}
+
+// Page layout, in points
+const (
+	margin     = 54.0
+	right      = pdf.PageWidth - margin
+	bandHeight = 84.0
+	lineHeight = 18.0
+	footerY    = margin
+)
+
+var (
+	brand     = pdf.Color{R: 0.09, G: 0.2, B: 0.4}
+	rule      = pdf.Color{R: 0.8, G: 0.8, B: 0.8}
+	shade     = pdf.Color{R: 0.94, G: 0.95, B: 0.97}
+	dateStyle = "Jan 2, 2006"
+)
+
+// ReceiptNumber is the number printed on a payment's receipt
+func ReceiptNumber(paymentID int64) string {
+	return fmt.Sprintf("R-%06d", paymentID)
+}
+
+// InvoiceNumber is the number printed on a player's season invoice
+func InvoiceNumber(playerID int64) string {
+	return fmt.Sprintf("INV-%06d", playerID)
+}
+
+// RenderReceipt renders a receipt as a one page PDF
+func RenderReceipt(r Receipt) ([]byte, error) {
+	doc := pdf.New(r.League.Name + " receipt " + ReceiptNumber(r.PaymentID))
+	page := doc.AddPage()
+	y := letterhead(page, r.League, "RECEIPT")
+
+	y = details(page, y, [][2]string{
+		{"Receipt number", ReceiptNumber(r.PaymentID)},
+		{"Issued", r.IssuedAt.Format(dateStyle)},
+	})
+	y -= lineHeight
+	y = billTo(page, y, r.PlayerName, r.PlayerEmail)
+	y -= lineHeight
+
+	page.Text(margin, y, 13, true, brand, "Payment details")
+	y -= 8
+	page.Line(margin, y, right, y, 1, brand)
+	y -= lineHeight + 4
+
+	rows := [][2]string{
+		{"Payment date", r.Date.Format(dateStyle)},
+		{"Description", "Registration fee payment"},
+	}
+	if r.SeasonName != "" {
+		rows[1][1] += ", " + r.SeasonName
+	}
+	rows = append(rows, [2]string{"Status", statusLabel(r.Status)})
+	if r.Provider != "" {
+		rows = append(rows, [2]string{"Payment method", providerLabel(r.Provider)})
+	}
+	rows = append(rows, [2]string{"Reference", r.Reference})
+	if r.PaymentIntent != "" && r.PaymentIntent != r.Reference {
+		rows = append(rows, [2]string{"Transaction", r.PaymentIntent})
+	}
+	y = details(page, y, rows)
+	y -= lineHeight
+
+	page.Rect(margin, y-14, right-margin, 40, shade)
+	page.Text(margin+12, y, 13, true, pdf.Black, "Amount paid")
+	page.TextRight(right-12, y, 16, true, brand, money.Format(r.AmountCents))
+	y -= 40
+
+	if r.RefundedCents > 0 {
+		page.Text(margin+12, y, 10, false, pdf.Black, "Refunded")
+		page.TextRight(right-12, y, 10, false, pdf.Black, "-"+money.Format(r.RefundedCents))
+		y -= lineHeight
+		page.Text(margin+12, y, 10, true, pdf.Black, "Net amount paid")
+		page.TextRight(right-12, y, 10, true, pdf.Black, money.Format(r.AmountCents-r.RefundedCents))
+	}
+
+	footer(page, r.League, "Thank you for your payment. Please keep this receipt for your records.")
+	return doc.Bytes()
+}
+
+// RenderInvoice renders an invoice as a PDF, adding pages as the lines need
+func RenderInvoice(inv Invoice) ([]byte, error) {
+	doc := pdf.New(inv.League.Name + " invoice " + InvoiceNumber(inv.PlayerID))
+	page := doc.AddPage()
+	y := letterhead(page, inv.League, "INVOICE")
+
+	rows := [][2]string{
+		{"Invoice number", InvoiceNumber(inv.PlayerID)},
+		{"Issued", inv.IssuedAt.Format(dateStyle)},
+	}
+	if inv.SeasonName != "" {
+		rows = append(rows, [2]string{"Season", inv.SeasonName})
+	}
+	y = details(page, y, rows)
+	y -= lineHeight
+	y = billTo(page, y, inv.PlayerName, inv.PlayerEmail)
+	y -= lineHeight
+
+	// Columns: date, description, charges, credits, balance
+	dateX, descX := margin, margin+80
+	chargeX, creditX, balanceX := right-150, right-75, right
+	header := func(y float64) float64 {
+		page.Rect(margin, y-6, right-margin, lineHeight, brand)
+		page.Text(dateX+4, y, 9, true, pdf.White, "Date")
+		page.Text(descX, y, 9, true, pdf.White, "Description")
+		page.TextRight(chargeX, y, 9, true, pdf.White, "Charges")
+		page.TextRight(creditX, y, 9, true, pdf.White, "Credits")
+		page.TextRight(balanceX-4, y, 9, true, pdf.White, "Balance")
+		return y - lineHeight - 4
+	}
+	y = header(y)
+
+	var balance int64
+	if len(inv.Lines) == 0 {
+		page.Text(descX, y, 10, false, pdf.Gray, "No charges or payments.")
+		y -= lineHeight
+	}
+	for _, line := range inv.Lines {
+		if y < footerY+3*lineHeight {
+			footer(page, inv.League, "Continued on the next page.")
+			page = doc.AddPage()
+			y = header(pdf.PageHeight - margin)
+		}
+		balance += line.AmountCents
+
+		page.Text(dateX+4, y, 10, false, pdf.Black, line.Date.Format(dateStyle))
+		page.Text(descX, y, 10, false, pdf.Black, pdf.Truncate(line.Description, chargeX-descX-60, 10, false))
+		if line.AmountCents >= 0 {
+			page.TextRight(chargeX, y, 10, false, pdf.Black, money.Format(line.AmountCents))
+		} else {
+			page.TextRight(creditX, y, 10, false, pdf.Black, money.Format(-line.AmountCents))
+		}
+		page.TextRight(balanceX-4, y, 10, false, pdf.Black, money.Format(balance))
+		page.Line(margin, y-6, right, y-6, 0.5, rule)
+		y -= lineHeight
+	}
+
+	if y < footerY+4*lineHeight {
+		footer(page, inv.League, "Continued on the next page.")
+		page = doc.AddPage()
+		y = pdf.PageHeight - margin
+	}
+	y -= 8
+	label, amount := "Balance due", inv.BalanceCents
+	if amount < 0 {
+		label, amount = "Credit balance", -amount
+	}
+	page.Rect(right-230, y-14, 230, 40, shade)
+	page.Text(right-218, y, 13, true, pdf.Black, label)
+	page.TextRight(right-12, y, 16, true, brand, money.Format(amount))
+
+	note := "Paid in full. Thank you!"
+	if inv.BalanceCents > 0 {
+		note = "Please pay the balance due from your account on the league website."
+	}
+	footer(page, inv.League, note)
+	return doc.Bytes()
+}
+
+// letterhead draws the league's name and contact details in a band across the
+// top of the page with the document's title, and returns where content starts
+func letterhead(page *pdf.Page, league League, title string) float64 {
+	top := pdf.PageHeight - bandHeight
+	page.Rect(0, top, pdf.PageWidth, bandHeight, brand)
+	page.Text(margin, top+44, 22, true, pdf.White, league.Name)
+
+	contact := league.Address
+	if league.Email != "" {
+		if contact != "" {
+			contact += "  |  "
+		}
+		contact += league.Email
+	}
+	if contact != "" {
+		page.Text(margin, top+24, 9, false, pdf.White, contact)
+	}
+	page.TextRight(right, top+44, 18, true, pdf.White, title)
+	return top - 40
+}
+
+// details draws label and value pairs, one per line
+func details(page *pdf.Page, y float64, rows [][2]string) float64 {
+	for _, row := range rows {
+		page.Text(margin, y, 10, false, pdf.Gray, row[0])
+		page.Text(margin+120, y, 10, false, pdf.Black, pdf.Truncate(row[1], right-margin-120, 10, false))
+		y -= lineHeight
+	}
+	return y
+}
+
+// billTo draws who the document is for
+func billTo(page *pdf.Page, y float64, name, email string) float64 {
+	page.Text(margin, y, 10, true, brand, "BILLED TO")
+	y -= lineHeight
+	page.Text(margin, y, 11, true, pdf.Black, name)
+	y -= lineHeight - 4
+	if email != "" {
+		page.Text(margin, y, 10, false, pdf.Black, email)
+		y -= lineHeight
+	}
+	return y
+}
+
+// footer draws a note and the league's name at the bottom of the page
+func footer(page *pdf.Page, league League, note string) {
+	page.Line(margin, footerY+18, right, footerY+18, 0.5, rule)
+	page.Text(margin, footerY, 9, false, pdf.Gray, note)
+	page.TextRight(right, footerY, 9, false, pdf.Gray, league.Name)
+}
+
+// statusLabel describes a payment status for the reader of a receipt
+func statusLabel(status string) string {
+	switch status {
+	case "completed":
+		return "Paid"
+	case "refunded":
+		return "Refunded"
+	default:
+		return status
+	}
+}
+
+// providerLabel describes how a payment was made
+func providerLabel(provider string) string {
+	switch provider {
+	case "stripe":
+		return "Card (Stripe)"
+	case "fake":
+		return "Test checkout"
+	default:
+		return provider
+	}
+}
@@ -12,32 +12,76 @@ import (
 )
 
 const createGameDetails = `-- name: CreateGameDetails :one
-INSERT INTO game_details 
-(game_id, player_id, score)
-VALUES ($1, $2, $3)
-RETURNING id, game_id, player_id, score
+INSERT INTO game_details
+(game_id, player_id, score, rebounds, assists, steals, blocks, turnovers, fouls,
+ field_goals_made, field_goals_attempted, three_pointers_made, three_pointers_attempted,
+ free_throws_made, free_throws_attempted)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+RETURNING id, game_id, player_id, score, rebounds, assists, steals, blocks, turnovers, fouls, field_goals_made, field_goals_attempted, three_pointers_made, three_pointers_attempted, free_throws_made, free_throws_attempted
 `
 
 type CreateGameDetailsParams struct {
-	GameID   int64 `json:"gameId"`
-	PlayerID int64 `json:"playerId"`
-	Score    int32 `json:"score"`
+	GameID                 int64 `json:"gameId"`
+	PlayerID               int64 `json:"playerId"`
+	Score                  int32 `json:"score"`
+	Rebounds               int32 `json:"rebounds"`
+	Assists                int32 `json:"assists"`
+	Steals                 int32 `json:"steals"`
+	Blocks                 int32 `json:"blocks"`
+	Turnovers              int32 `json:"turnovers"`
+	Fouls                  int32 `json:"fouls"`
+	FieldGoalsMade         int32 `json:"fieldGoalsMade"`
+	FieldGoalsAttempted    int32 `json:"fieldGoalsAttempted"`
+	ThreePointersMade      int32 `json:"threePointersMade"`
+	ThreePointersAttempted int32 `json:"threePointersAttempted"`
+	FreeThrowsMade         int32 `json:"freeThrowsMade"`
+	FreeThrowsAttempted    int32 `json:"freeThrowsAttempted"`
 }
 
 // CreateGameDetails
 //
 //	INSERT INTO game_details
-//	(game_id, player_id, score)
-//	VALUES ($1, $2, $3)
-//	RETURNING id, game_id, player_id, score
+//	(game_id, player_id, score, rebounds, assists, steals, blocks, turnovers, fouls,
+//	 field_goals_made, field_goals_attempted, three_pointers_made, three_pointers_attempted,
+//	 free_throws_made, free_throws_attempted)
+//	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+//	RETURNING id, game_id, player_id, score, rebounds, assists, steals, blocks, turnovers, fouls, field_goals_made, field_goals_attempted, three_pointers_made, three_pointers_attempted, free_throws_made, free_throws_attempted
 func (q *Queries) CreateGameDetails(ctx context.Context, arg CreateGameDetailsParams) (GameDetail, error) {
-	row := q.db.QueryRow(ctx, createGameDetails, arg.GameID, arg.PlayerID, arg.Score)
+	row := q.db.QueryRow(ctx, createGameDetails,
+		arg.GameID,
+		arg.PlayerID,
+		arg.Score,
+		arg.Rebounds,
+		arg.Assists,
+		arg.Steals,
+		arg.Blocks,
+		arg.Turnovers,
+		arg.Fouls,
+		arg.FieldGoalsMade,
+		arg.FieldGoalsAttempted,
+		arg.ThreePointersMade,
+		arg.ThreePointersAttempted,
+		arg.FreeThrowsMade,
+		arg.FreeThrowsAttempted,
+	)
 	var i GameDetail
 	err := row.Scan(
 		&i.ID,
 		&i.GameID,
 		&i.PlayerID,
 		&i.Score,
+		&i.Rebounds,
+		&i.Assists,
+		&i.Steals,
+		&i.Blocks,
+		&i.Turnovers,
+		&i.Fouls,
+		&i.FieldGoalsMade,
+		&i.FieldGoalsAttempted,
+		&i.ThreePointersMade,
+		&i.ThreePointersAttempted,
+		&i.FreeThrowsMade,
+		&i.FreeThrowsAttempted,
 	)
 	return i, err
 }
@@ -120,12 +164,12 @@ func (q *Queries) DeleteGameDetailsByTeam(ctx context.Context, teamID pgtype.Int
 }
 
 const listGameDetails = `-- name: ListGameDetails :many
-SELECT id, game_id, player_id, score FROM game_details
+SELECT id, game_id, player_id, score, rebounds, assists, steals, blocks, turnovers, fouls, field_goals_made, field_goals_attempted, three_pointers_made, three_pointers_attempted, free_throws_made, free_throws_attempted FROM game_details
 `
 
 // ListGameDetails
 //
-//	SELECT id, game_id, player_id, score FROM game_details
+//	SELECT id, game_id, player_id, score, rebounds, assists, steals, blocks, turnovers, fouls, field_goals_made, field_goals_attempted, three_pointers_made, three_pointers_attempted, free_throws_made, free_throws_attempted FROM game_details
 func (q *Queries) ListGameDetails(ctx context.Context) ([]GameDetail, error) {
 	rows, err := q.db.Query(ctx, listGameDetails)
 	if err != nil {
@@ -140,6 +184,18 @@ func (q *Queries) ListGameDetails(ctx context.Context) ([]GameDetail, error) {
 			&i.GameID,
 			&i.PlayerID,
 			&i.Score,
+			&i.Rebounds,
+			&i.Assists,
+			&i.Steals,
+			&i.Blocks,
+			&i.Turnovers,
+			&i.Fouls,
+			&i.FieldGoalsMade,
+			&i.FieldGoalsAttempted,
+			&i.ThreePointersMade,
+			&i.ThreePointersAttempted,
+			&i.FreeThrowsMade,
+			&i.FreeThrowsAttempted,
 		); err != nil {
 			return nil, err
 		}
@@ -152,13 +208,13 @@ func (q *Queries) ListGameDetails(ctx context.Context) ([]GameDetail, error) {
 }
 
 const listGameDetailsByGameId = `-- name: ListGameDetailsByGameId :many
-SELECT id, game_id, player_id, score from game_details
+SELECT id, game_id, player_id, score, rebounds, assists, steals, blocks, turnovers, fouls, field_goals_made, field_goals_attempted, three_pointers_made, three_pointers_attempted, free_throws_made, free_throws_attempted from game_details
 WHERE game_id = $1
 `
 
 // ListGameDetailsByGameId
 //
-//	SELECT id, game_id, player_id, score from game_details
+//	SELECT id, game_id, player_id, score, rebounds, assists, steals, blocks, turnovers, fouls, field_goals_made, field_goals_attempted, three_pointers_made, three_pointers_attempted, free_throws_made, free_throws_attempted from game_details
 //	WHERE game_id = $1
 func (q *Queries) ListGameDetailsByGameId(ctx context.Context, gameID int64) ([]GameDetail, error) {
 	rows, err := q.db.Query(ctx, listGameDetailsByGameId, gameID)
@@ -174,6 +230,18 @@ func (q *Queries) ListGameDetailsByGameId(ctx context.Context, gameID int64) ([]
 			&i.GameID,
 			&i.PlayerID,
 			&i.Score,
+			&i.Rebounds,
+			&i.Assists,
+			&i.Steals,
+			&i.Blocks,
+			&i.Turnovers,
+			&i.Fouls,
+			&i.FieldGoalsMade,
+			&i.FieldGoalsAttempted,
+			&i.ThreePointersMade,
+			&i.ThreePointersAttempted,
+			&i.FreeThrowsMade,
+			&i.FreeThrowsAttempted,
 		); err != nil {
 			return nil, err
 		}
@@ -186,13 +254,13 @@ func (q *Queries) ListGameDetailsByGameId(ctx context.Context, gameID int64) ([]
 }
 
 const listGameDetailsById = `-- name: ListGameDetailsById :many
-SELECT id, game_id, player_id, score from game_details
+SELECT id, game_id, player_id, score, rebounds, assists, steals, blocks, turnovers, fouls, field_goals_made, field_goals_attempted, three_pointers_made, three_pointers_attempted, free_throws_made, free_throws_attempted from game_details
 where id = $1
 `
 
 // ListGameDetailsById
 //
-//	SELECT id, game_id, player_id, score from game_details
+//	SELECT id, game_id, player_id, score, rebounds, assists, steals, blocks, turnovers, fouls, field_goals_made, field_goals_attempted, three_pointers_made, three_pointers_attempted, free_throws_made, free_throws_attempted from game_details
 //	where id = $1
 func (q *Queries) ListGameDetailsById(ctx context.Context, id int64) ([]GameDetail, error) {
 	rows, err := q.db.Query(ctx, listGameDetailsById, id)
@@ -208,6 +276,18 @@ func (q *Queries) ListGameDetailsById(ctx context.Context, id int64) ([]GameDeta
 			&i.GameID,
 			&i.PlayerID,
 			&i.Score,
+			&i.Rebounds,
+			&i.Assists,
+			&i.Steals,
+			&i.Blocks,
+			&i.Turnovers,
+			&i.Fouls,
+			&i.FieldGoalsMade,
+			&i.FieldGoalsAttempted,
+			&i.ThreePointersMade,
+			&i.ThreePointersAttempted,
+			&i.FreeThrowsMade,
+			&i.FreeThrowsAttempted,
 		); err != nil {
 			return nil, err
 		}
@@ -220,7 +300,7 @@ func (q *Queries) ListGameDetailsById(ctx context.Context, id int64) ([]GameDeta
 }
 
 const listGameDetailsByTeamId = `-- name: ListGameDetailsByTeamId :many
-SELECT id, game_id, player_id, score
+SELECT id, game_id, player_id, score, rebounds, assists, steals, blocks, turnovers, fouls, field_goals_made, field_goals_attempted, three_pointers_made, three_pointers_attempted, free_throws_made, free_throws_attempted
 FROM game_details
 WHERE PLAYER_ID IN 
   (SELECT id FROM players WHERE team_id = $1)
@@ -228,7 +308,7 @@ WHERE PLAYER_ID IN
 
 // ListGameDetailsByTeamId
 //
-//	SELECT id, game_id, player_id, score
+//	SELECT id, game_id, player_id, score, rebounds, assists, steals, blocks, turnovers, fouls, field_goals_made, field_goals_attempted, three_pointers_made, three_pointers_attempted, free_throws_made, free_throws_attempted
 //	FROM game_details
 //	WHERE PLAYER_ID IN
 //	  (SELECT id FROM players WHERE team_id = $1)
@@ -246,6 +326,18 @@ func (q *Queries) ListGameDetailsByTeamId(ctx context.Context, teamID pgtype.Int
 			&i.GameID,
 			&i.PlayerID,
 			&i.Score,
+			&i.Rebounds,
+			&i.Assists,
+			&i.Steals,
+			&i.Blocks,
+			&i.Turnovers,
+			&i.Fouls,
+			&i.FieldGoalsMade,
+			&i.FieldGoalsAttempted,
+			&i.ThreePointersMade,
+			&i.ThreePointersAttempted,
+			&i.FreeThrowsMade,
+			&i.FreeThrowsAttempted,
 		); err != nil {
 			return nil, err
 		}
@@ -258,32 +350,52 @@ func (q *Queries) ListGameDetailsByTeamId(ctx context.Context, teamID pgtype.Int
 }
 
 const listGameDetailsVerbose = `-- name: ListGameDetailsVerbose :many
-SELECT gd.player_id, gd.game_id, p.team_id, u.first_name, u.last_name, p.jersey_number, gd.score
+SELECT gd.player_id, gd.game_id, p.team_id, u.first_name, u.last_name, p.jersey_number, gd.score,
+       gd.rebounds, gd.assists, gd.steals, gd.blocks, gd.turnovers, gd.fouls,
+       gd.field_goals_made, gd.field_goals_attempted, gd.three_pointers_made, gd.three_pointers_attempted,
+       gd.free_throws_made, gd.free_throws_attempted
 FROM game_details as gd
 INNER JOIN players as p ON gd.player_id = p.id
 INNER JOIN users as u on u.id = p.user_id
+WHERE p.season_id = $1
 order by game_id, team_id
 `
 
 type ListGameDetailsVerboseRow struct {
-	PlayerID     int64       `json:"playerId"`
-	GameID       int64       `json:"gameId"`
-	TeamID       int64       `json:"teamId"`
-	FirstName    string      `json:"firstName"`
-	LastName     string      `json:"lastName"`
-	JerseyNumber pgtype.Int4 `json:"jerseyNumber"`
-	Score        int32       `json:"score"`
+	PlayerID               int64       `json:"playerId"`
+	GameID                 int64       `json:"gameId"`
+	TeamID                 int64       `json:"teamId"`
+	FirstName              string      `json:"firstName"`
+	LastName               string      `json:"lastName"`
+	JerseyNumber           pgtype.Int4 `json:"jerseyNumber"`
+	Score                  int32       `json:"score"`
+	Rebounds               int32       `json:"rebounds"`
+	Assists                int32       `json:"assists"`
+	Steals                 int32       `json:"steals"`
+	Blocks                 int32       `json:"blocks"`
+	Turnovers              int32       `json:"turnovers"`
+	Fouls                  int32       `json:"fouls"`
+	FieldGoalsMade         int32       `json:"fieldGoalsMade"`
+	FieldGoalsAttempted    int32       `json:"fieldGoalsAttempted"`
+	ThreePointersMade      int32       `json:"threePointersMade"`
+	ThreePointersAttempted int32       `json:"threePointersAttempted"`
+	FreeThrowsMade         int32       `json:"freeThrowsMade"`
+	FreeThrowsAttempted    int32       `json:"freeThrowsAttempted"`
 }
 
 // ListGameDetailsVerbose
 //
-//	SELECT gd.player_id, gd.game_id, p.team_id, u.first_name, u.last_name, p.jersey_number, gd.score
+//	SELECT gd.player_id, gd.game_id, p.team_id, u.first_name, u.last_name, p.jersey_number, gd.score,
+//	       gd.rebounds, gd.assists, gd.steals, gd.blocks, gd.turnovers, gd.fouls,
+//	       gd.field_goals_made, gd.field_goals_attempted, gd.three_pointers_made, gd.three_pointers_attempted,
+//	       gd.free_throws_made, gd.free_throws_attempted
 //	FROM game_details as gd
 //	INNER JOIN players as p ON gd.player_id = p.id
 //	INNER JOIN users as u on u.id = p.user_id
+//	WHERE p.season_id = $1
 //	order by game_id, team_id
-func (q *Queries) ListGameDetailsVerbose(ctx context.Context) ([]ListGameDetailsVerboseRow, error) {
-	rows, err := q.db.Query(ctx, listGameDetailsVerbose)
+func (q *Queries) ListGameDetailsVerbose(ctx context.Context, seasonID int64) ([]ListGameDetailsVerboseRow, error) {
+	rows, err := q.db.Query(ctx, listGameDetailsVerbose, seasonID)
 	if err != nil {
 		return nil, err
 	}
@@ -299,6 +411,103 @@ func (q *Queries) ListGameDetailsVerbose(ctx context.Context) ([]ListGameDetails
 			&i.LastName,
 			&i.JerseyNumber,
 			&i.Score,
+			&i.Rebounds,
+			&i.Assists,
+			&i.Steals,
+			&i.Blocks,
+			&i.Turnovers,
+			&i.Fouls,
+			&i.FieldGoalsMade,
+			&i.FieldGoalsAttempted,
+			&i.ThreePointersMade,
+			&i.ThreePointersAttempted,
+			&i.FreeThrowsMade,
+			&i.FreeThrowsAttempted,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGameDetailsVerboseByGame = `-- name: ListGameDetailsVerboseByGame :many
+SELECT gd.player_id, gd.game_id, p.team_id, u.first_name, u.last_name, p.jersey_number, gd.score,
+       gd.rebounds, gd.assists, gd.steals, gd.blocks, gd.turnovers, gd.fouls,
+       gd.field_goals_made, gd.field_goals_attempted, gd.three_pointers_made, gd.three_pointers_attempted,
+       gd.free_throws_made, gd.free_throws_attempted
+FROM game_details as gd
+INNER JOIN players as p ON gd.player_id = p.id
+INNER JOIN users as u on u.id = p.user_id
+WHERE gd.game_id = $1
+order by team_id, jersey_number
+`
+
+type ListGameDetailsVerboseByGameRow struct {
+	PlayerID               int64       `json:"playerId"`
+	GameID                 int64       `json:"gameId"`
+	TeamID                 pgtype.Int8 `json:"teamId"`
+	FirstName              string      `json:"firstName"`
+	LastName               string      `json:"lastName"`
+	JerseyNumber           pgtype.Int4 `json:"jerseyNumber"`
+	Score                  int32       `json:"score"`
+	Rebounds               int32       `json:"rebounds"`
+	Assists                int32       `json:"assists"`
+	Steals                 int32       `json:"steals"`
+	Blocks                 int32       `json:"blocks"`
+	Turnovers              int32       `json:"turnovers"`
+	Fouls                  int32       `json:"fouls"`
+	FieldGoalsMade         int32       `json:"fieldGoalsMade"`
+	FieldGoalsAttempted    int32       `json:"fieldGoalsAttempted"`
+	ThreePointersMade      int32       `json:"threePointersMade"`
+	ThreePointersAttempted int32       `json:"threePointersAttempted"`
+	FreeThrowsMade         int32       `json:"freeThrowsMade"`
+	FreeThrowsAttempted    int32       `json:"freeThrowsAttempted"`
+}
+
+// ListGameDetailsVerboseByGame
+//
+//	SELECT gd.player_id, gd.game_id, p.team_id, u.first_name, u.last_name, p.jersey_number, gd.score,
+//	       gd.rebounds, gd.assists, gd.steals, gd.blocks, gd.turnovers, gd.fouls,
+//	       gd.field_goals_made, gd.field_goals_attempted, gd.three_pointers_made, gd.three_pointers_attempted,
+//	       gd.free_throws_made, gd.free_throws_attempted
+//	FROM game_details as gd
+//	INNER JOIN players as p ON gd.player_id = p.id
+//	INNER JOIN users as u on u.id = p.user_id
+//	WHERE gd.game_id = $1
+//	order by team_id, jersey_number
+func (q *Queries) ListGameDetailsVerboseByGame(ctx context.Context, gameID int64) ([]ListGameDetailsVerboseByGameRow, error) {
+	rows, err := q.db.Query(ctx, listGameDetailsVerboseByGame, gameID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListGameDetailsVerboseByGameRow{}
+	for rows.Next() {
+		var i ListGameDetailsVerboseByGameRow
+		if err := rows.Scan(
+			&i.PlayerID,
+			&i.GameID,
+			&i.TeamID,
+			&i.FirstName,
+			&i.LastName,
+			&i.JerseyNumber,
+			&i.Score,
+			&i.Rebounds,
+			&i.Assists,
+			&i.Steals,
+			&i.Blocks,
+			&i.Turnovers,
+			&i.Fouls,
+			&i.FieldGoalsMade,
+			&i.FieldGoalsAttempted,
+			&i.ThreePointersMade,
+			&i.ThreePointersAttempted,
+			&i.FreeThrowsMade,
+			&i.FreeThrowsAttempted,
 		); err != nil {
 			return nil, err
 		}
@@ -310,7 +519,195 @@ func (q *Queries) ListGameDetailsVerbose(ctx context.Context) ([]ListGameDetails
 	return items, nil
 }
 
-const updateGameDetailsScore = `-- name: UpdateGameDetailsScore :exec
+const listPlayerCareerGameStats = `-- name: ListPlayerCareerGameStats :many
+SELECT gd.id, gd.game_id, gd.player_id, gd.score, gd.rebounds, gd.assists, gd.steals, gd.blocks, gd.turnovers, gd.fouls, gd.field_goals_made, gd.field_goals_attempted, gd.three_pointers_made, gd.three_pointers_attempted, gd.free_throws_made, gd.free_throws_attempted, g.game_time, g.home_team_id, ht.name AS home_team_name, g.home_score,
+       g.away_team_id, at.name AS away_team_name, g.away_score
+FROM game_details gd
+INNER JOIN players p ON p.id = gd.player_id
+INNER JOIN games g ON g.id = gd.game_id
+INNER JOIN teams ht ON ht.id = g.home_team_id
+INNER JOIN teams at ON at.id = g.away_team_id
+WHERE p.user_id = (SELECT user_id FROM players WHERE id = $1)
+  AND g.status = 'completed'
+ORDER BY g.game_time, g.id
+`
+
+type ListPlayerCareerGameStatsRow struct {
+	ID                     int64            `json:"id"`
+	GameID                 int64            `json:"gameId"`
+	PlayerID               int64            `json:"playerId"`
+	Score                  int32            `json:"score"`
+	Rebounds               int32            `json:"rebounds"`
+	Assists                int32            `json:"assists"`
+	Steals                 int32            `json:"steals"`
+	Blocks                 int32            `json:"blocks"`
+	Turnovers              int32            `json:"turnovers"`
+	Fouls                  int32            `json:"fouls"`
+	FieldGoalsMade         int32            `json:"fieldGoalsMade"`
+	FieldGoalsAttempted    int32            `json:"fieldGoalsAttempted"`
+	ThreePointersMade      int32            `json:"threePointersMade"`
+	ThreePointersAttempted int32            `json:"threePointersAttempted"`
+	FreeThrowsMade         int32            `json:"freeThrowsMade"`
+	FreeThrowsAttempted    int32            `json:"freeThrowsAttempted"`
+	GameTime               pgtype.Timestamp `json:"gameTime"`
+	HomeTeamID             int64            `json:"homeTeamId"`
+	HomeTeamName           string           `json:"homeTeamName"`
+	HomeScore              int32            `json:"homeScore"`
+	AwayTeamID             int64            `json:"awayTeamId"`
+	AwayTeamName           string           `json:"awayTeamName"`
+	AwayScore              int32            `json:"awayScore"`
+}
+
+// ListPlayerCareerGameStats
+//
+//	SELECT gd.id, gd.game_id, gd.player_id, gd.score, gd.rebounds, gd.assists, gd.steals, gd.blocks, gd.turnovers, gd.fouls, gd.field_goals_made, gd.field_goals_attempted, gd.three_pointers_made, gd.three_pointers_attempted, gd.free_throws_made, gd.free_throws_attempted, g.game_time, g.home_team_id, ht.name AS home_team_name, g.home_score,
+//	       g.away_team_id, at.name AS away_team_name, g.away_score
+//	FROM game_details gd
+//	INNER JOIN players p ON p.id = gd.player_id
+//	INNER JOIN games g ON g.id = gd.game_id
+//	INNER JOIN teams ht ON ht.id = g.home_team_id
+//	INNER JOIN teams at ON at.id = g.away_team_id
+//	WHERE p.user_id = (SELECT user_id FROM players WHERE id = $1)
+//	  AND g.status = 'completed'
+//	ORDER BY g.game_time, g.id
+func (q *Queries) ListPlayerCareerGameStats(ctx context.Context, playerID int64) ([]ListPlayerCareerGameStatsRow, error) {
+	rows, err := q.db.Query(ctx, listPlayerCareerGameStats, playerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPlayerCareerGameStatsRow{}
+	for rows.Next() {
+		var i ListPlayerCareerGameStatsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.GameID,
+			&i.PlayerID,
+			&i.Score,
+			&i.Rebounds,
+			&i.Assists,
+			&i.Steals,
+			&i.Blocks,
+			&i.Turnovers,
+			&i.Fouls,
+			&i.FieldGoalsMade,
+			&i.FieldGoalsAttempted,
+			&i.ThreePointersMade,
+			&i.ThreePointersAttempted,
+			&i.FreeThrowsMade,
+			&i.FreeThrowsAttempted,
+			&i.GameTime,
+			&i.HomeTeamID,
+			&i.HomeTeamName,
+			&i.HomeScore,
+			&i.AwayTeamID,
+			&i.AwayTeamName,
+			&i.AwayScore,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPlayerGameStats = `-- name: ListPlayerGameStats :many
+SELECT gd.id, gd.game_id, gd.player_id, gd.score, gd.rebounds, gd.assists, gd.steals, gd.blocks, gd.turnovers, gd.fouls, gd.field_goals_made, gd.field_goals_attempted, gd.three_pointers_made, gd.three_pointers_attempted, gd.free_throws_made, gd.free_throws_attempted, g.game_time, g.status, g.home_team_id, ht.name AS home_team_name, g.home_score,
+       g.away_team_id, at.name AS away_team_name, g.away_score
+FROM game_details gd
+INNER JOIN games g ON g.id = gd.game_id
+INNER JOIN teams ht ON ht.id = g.home_team_id
+INNER JOIN teams at ON at.id = g.away_team_id
+WHERE gd.player_id = $1
+ORDER BY g.game_time
+`
+
+type ListPlayerGameStatsRow struct {
+	ID                     int64            `json:"id"`
+	GameID                 int64            `json:"gameId"`
+	PlayerID               int64            `json:"playerId"`
+	Score                  int32            `json:"score"`
+	Rebounds               int32            `json:"rebounds"`
+	Assists                int32            `json:"assists"`
+	Steals                 int32            `json:"steals"`
+	Blocks                 int32            `json:"blocks"`
+	Turnovers              int32            `json:"turnovers"`
+	Fouls                  int32            `json:"fouls"`
+	FieldGoalsMade         int32            `json:"fieldGoalsMade"`
+	FieldGoalsAttempted    int32            `json:"fieldGoalsAttempted"`
+	ThreePointersMade      int32            `json:"threePointersMade"`
+	ThreePointersAttempted int32            `json:"threePointersAttempted"`
+	FreeThrowsMade         int32            `json:"freeThrowsMade"`
+	FreeThrowsAttempted    int32            `json:"freeThrowsAttempted"`
+	GameTime               pgtype.Timestamp `json:"gameTime"`
+	Status                 string           `json:"status"`
+	HomeTeamID             int64            `json:"homeTeamId"`
+	HomeTeamName           string           `json:"homeTeamName"`
+	HomeScore              int32            `json:"homeScore"`
+	AwayTeamID             int64            `json:"awayTeamId"`
+	AwayTeamName           string           `json:"awayTeamName"`
+	AwayScore              int32            `json:"awayScore"`
+}
+
+// ListPlayerGameStats
+//
+//	SELECT gd.id, gd.game_id, gd.player_id, gd.score, gd.rebounds, gd.assists, gd.steals, gd.blocks, gd.turnovers, gd.fouls, gd.field_goals_made, gd.field_goals_attempted, gd.three_pointers_made, gd.three_pointers_attempted, gd.free_throws_made, gd.free_throws_attempted, g.game_time, g.status, g.home_team_id, ht.name AS home_team_name, g.home_score,
+//	       g.away_team_id, at.name AS away_team_name, g.away_score
+//	FROM game_details gd
+//	INNER JOIN games g ON g.id = gd.game_id
+//	INNER JOIN teams ht ON ht.id = g.home_team_id
+//	INNER JOIN teams at ON at.id = g.away_team_id
+//	WHERE gd.player_id = $1
+//	ORDER BY g.game_time
+func (q *Queries) ListPlayerGameStats(ctx context.Context, playerID int64) ([]ListPlayerGameStatsRow, error) {
+	rows, err := q.db.Query(ctx, listPlayerGameStats, playerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPlayerGameStatsRow{}
+	for rows.Next() {
+		var i ListPlayerGameStatsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.GameID,
+			&i.PlayerID,
+			&i.Score,
+			&i.Rebounds,
+			&i.Assists,
+			&i.Steals,
+			&i.Blocks,
+			&i.Turnovers,
+			&i.Fouls,
+			&i.FieldGoalsMade,
+			&i.FieldGoalsAttempted,
+			&i.ThreePointersMade,
+			&i.ThreePointersAttempted,
+			&i.FreeThrowsMade,
+			&i.FreeThrowsAttempted,
+			&i.GameTime,
+			&i.Status,
+			&i.HomeTeamID,
+			&i.HomeTeamName,
+			&i.HomeScore,
+			&i.AwayTeamID,
+			&i.AwayTeamName,
+			&i.AwayScore,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateGameDetailsScore = `-- name: UpdateGameDetailsScore :execrows
 UPDATE game_details
 SET score = $3
 WHERE game_id = $1 AND player_id = $2
@@ -327,7 +724,103 @@ type UpdateGameDetailsScoreParams struct {
 //	UPDATE game_details
 //	SET score = $3
 //	WHERE game_id = $1 AND player_id = $2
-func (q *Queries) UpdateGameDetailsScore(ctx context.Context, arg UpdateGameDetailsScoreParams) error {
-	_, err := q.db.Exec(ctx, updateGameDetailsScore, arg.GameID, arg.PlayerID, arg.Score)
-	return err
+func (q *Queries) UpdateGameDetailsScore(ctx context.Context, arg UpdateGameDetailsScoreParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateGameDetailsScore, arg.GameID, arg.PlayerID, arg.Score)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const upsertGameDetails = `-- name: UpsertGameDetails :one
+INSERT INTO game_details
+(game_id, player_id, score, rebounds, assists, steals, blocks, turnovers, fouls,
+ field_goals_made, field_goals_attempted, three_pointers_made, three_pointers_attempted,
+ free_throws_made, free_throws_attempted)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+ON CONFLICT (game_id, player_id) DO UPDATE
+SET score = EXCLUDED.score, rebounds = EXCLUDED.rebounds, assists = EXCLUDED.assists,
+    steals = EXCLUDED.steals, blocks = EXCLUDED.blocks, turnovers = EXCLUDED.turnovers,
+    fouls = EXCLUDED.fouls, field_goals_made = EXCLUDED.field_goals_made,
+    field_goals_attempted = EXCLUDED.field_goals_attempted,
+    three_pointers_made = EXCLUDED.three_pointers_made,
+    three_pointers_attempted = EXCLUDED.three_pointers_attempted,
+    free_throws_made = EXCLUDED.free_throws_made,
+    free_throws_attempted = EXCLUDED.free_throws_attempted
+RETURNING id, game_id, player_id, score, rebounds, assists, steals, blocks, turnovers, fouls, field_goals_made, field_goals_attempted, three_pointers_made, three_pointers_attempted, free_throws_made, free_throws_attempted
+`
+
+type UpsertGameDetailsParams struct {
+	GameID                 int64 `json:"gameId"`
+	PlayerID               int64 `json:"playerId"`
+	Score                  int32 `json:"score"`
+	Rebounds               int32 `json:"rebounds"`
+	Assists                int32 `json:"assists"`
+	Steals                 int32 `json:"steals"`
+	Blocks                 int32 `json:"blocks"`
+	Turnovers              int32 `json:"turnovers"`
+	Fouls                  int32 `json:"fouls"`
+	FieldGoalsMade         int32 `json:"fieldGoalsMade"`
+	FieldGoalsAttempted    int32 `json:"fieldGoalsAttempted"`
+	ThreePointersMade      int32 `json:"threePointersMade"`
+	ThreePointersAttempted int32 `json:"threePointersAttempted"`
+	FreeThrowsMade         int32 `json:"freeThrowsMade"`
+	FreeThrowsAttempted    int32 `json:"freeThrowsAttempted"`
+}
+
+// UpsertGameDetails
+//
+//	INSERT INTO game_details
+//	(game_id, player_id, score, rebounds, assists, steals, blocks, turnovers, fouls,
+//	 field_goals_made, field_goals_attempted, three_pointers_made, three_pointers_attempted,
+//	 free_throws_made, free_throws_attempted)
+//	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+//	ON CONFLICT (game_id, player_id) DO UPDATE
+//	SET score = EXCLUDED.score, rebounds = EXCLUDED.rebounds, assists = EXCLUDED.assists,
+//	    steals = EXCLUDED.steals, blocks = EXCLUDED.blocks, turnovers = EXCLUDED.turnovers,
+//	    fouls = EXCLUDED.fouls, field_goals_made = EXCLUDED.field_goals_made,
+//	    field_goals_attempted = EXCLUDED.field_goals_attempted,
+//	    three_pointers_made = EXCLUDED.three_pointers_made,
+//	    three_pointers_attempted = EXCLUDED.three_pointers_attempted,
+//	    free_throws_made = EXCLUDED.free_throws_made,
+//	    free_throws_attempted = EXCLUDED.free_throws_attempted
+//	RETURNING id, game_id, player_id, score, rebounds, assists, steals, blocks, turnovers, fouls, field_goals_made, field_goals_attempted, three_pointers_made, three_pointers_attempted, free_throws_made, free_throws_attempted
+func (q *Queries) UpsertGameDetails(ctx context.Context, arg UpsertGameDetailsParams) (GameDetail, error) {
+	row := q.db.QueryRow(ctx, upsertGameDetails,
+		arg.GameID,
+		arg.PlayerID,
+		arg.Score,
+		arg.Rebounds,
+		arg.Assists,
+		arg.Steals,
+		arg.Blocks,
+		arg.Turnovers,
+		arg.Fouls,
+		arg.FieldGoalsMade,
+		arg.FieldGoalsAttempted,
+		arg.ThreePointersMade,
+		arg.ThreePointersAttempted,
+		arg.FreeThrowsMade,
+		arg.FreeThrowsAttempted,
+	)
+	var i GameDetail
+	err := row.Scan(
+		&i.ID,
+		&i.GameID,
+		&i.PlayerID,
+		&i.Score,
+		&i.Rebounds,
+		&i.Assists,
+		&i.Steals,
+		&i.Blocks,
+		&i.Turnovers,
+		&i.Fouls,
+		&i.FieldGoalsMade,
+		&i.FieldGoalsAttempted,
+		&i.ThreePointersMade,
+		&i.ThreePointersAttempted,
+		&i.FreeThrowsMade,
+		&i.FreeThrowsAttempted,
+	)
+	return i, err
 }
@@ -83,6 +83,32 @@ func (q *Queries) GetGameById(ctx context.Context, id int64) (Game, error) {
 	return i, err
 }
 
+const getGameByIdForUpdate = `-- name: GetGameByIdForUpdate :one
+SELECT id, home_team_id, away_team_id, home_score, away_score, game_time, created_at, updated_at, status FROM games WHERE id = $1
+FOR UPDATE
+`
+
+// GetGameByIdForUpdate
+//
+//	SELECT id, home_team_id, away_team_id, home_score, away_score, game_time, created_at, updated_at, status FROM games WHERE id = $1
+//	FOR UPDATE
+func (q *Queries) GetGameByIdForUpdate(ctx context.Context, id int64) (Game, error) {
+	row := q.db.QueryRow(ctx, getGameByIdForUpdate, id)
+	var i Game
+	err := row.Scan(
+		&i.ID,
+		&i.HomeTeamID,
+		&i.AwayTeamID,
+		&i.HomeScore,
+		&i.AwayScore,
+		&i.GameTime,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+	)
+	return i, err
+}
+
 const getGameWithTeams = `-- name: GetGameWithTeams :one
 SELECT g.id, g.home_team_id, g.away_team_id, g.home_score, g.away_score, g.game_time, g.created_at, g.updated_at, g.status,
        ht.name as home_team_name, ht.wins as home_team_wins, ht.losses as home_team_losses,
@@ -144,16 +170,29 @@ func (q *Queries) GetGameWithTeams(ctx context.Context, id int64) (GetGameWithTe
 }
 
 const listGames = `-- name: ListGames :many
-SELECT id, home_team_id, away_team_id, home_score, away_score, game_time, created_at, updated_at, status FROM games
-ORDER BY game_time
+SELECT g.id, g.home_team_id, g.away_team_id, g.home_score, g.away_score, g.game_time, g.created_at, g.updated_at, g.status FROM games g
+INNER JOIN teams ht ON g.home_team_id = ht.id
+INNER JOIN teams at ON g.away_team_id = at.id
+WHERE ht.season_id = $1
+  AND ($2::bigint IS NULL OR ht.division_id = $2 OR at.division_id = $2)
+ORDER BY g.game_time
 `
 
+type ListGamesParams struct {
+	SeasonID   int64       `json:"seasonId"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
+}
+
 // ListGames
 //
-//	SELECT id, home_team_id, away_team_id, home_score, away_score, game_time, created_at, updated_at, status FROM games
-//	ORDER BY game_time
-func (q *Queries) ListGames(ctx context.Context) ([]Game, error) {
-	rows, err := q.db.Query(ctx, listGames)
+//	SELECT g.id, g.home_team_id, g.away_team_id, g.home_score, g.away_score, g.game_time, g.created_at, g.updated_at, g.status FROM games g
+//	INNER JOIN teams ht ON g.home_team_id = ht.id
+//	INNER JOIN teams at ON g.away_team_id = at.id
+//	WHERE ht.season_id = $1
+//	  AND ($2::bigint IS NULL OR ht.division_id = $2 OR at.division_id = $2)
+//	ORDER BY g.game_time
+func (q *Queries) ListGames(ctx context.Context, arg ListGamesParams) ([]Game, error) {
+	rows, err := q.db.Query(ctx, listGames, arg.SeasonID, arg.DivisionID)
 	if err != nil {
 		return nil, err
 	}
@@ -255,9 +294,16 @@ SELECT g.id, g.home_team_id, g.away_team_id, g.home_score, g.away_score,
 FROM games g
 INNER JOIN teams ht ON g.home_team_id = ht.id
 INNER JOIN teams at ON g.away_team_id = at.id
+WHERE ht.season_id = $1
+  AND ($2::bigint IS NULL OR ht.division_id = $2 OR at.division_id = $2)
 ORDER BY g.game_time
 `
 
+type ListGamesWithTeamsParams struct {
+	SeasonID   int64       `json:"seasonId"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
+}
+
 type ListGamesWithTeamsRow struct {
 	ID           int64            `json:"id"`
 	HomeTeamID   int64            `json:"homeTeamId"`
@@ -281,9 +327,11 @@ type ListGamesWithTeamsRow struct {
 //	FROM games g
 //	INNER JOIN teams ht ON g.home_team_id = ht.id
 //	INNER JOIN teams at ON g.away_team_id = at.id
+//	WHERE ht.season_id = $1
+//	  AND ($2::bigint IS NULL OR ht.division_id = $2 OR at.division_id = $2)
 //	ORDER BY g.game_time
-func (q *Queries) ListGamesWithTeams(ctx context.Context) ([]ListGamesWithTeamsRow, error) {
-	rows, err := q.db.Query(ctx, listGamesWithTeams)
+func (q *Queries) ListGamesWithTeams(ctx context.Context, arg ListGamesWithTeamsParams) ([]ListGamesWithTeamsRow, error) {
+	rows, err := q.db.Query(ctx, listGamesWithTeams, arg.SeasonID, arg.DivisionID)
 	if err != nil {
 		return nil, err
 	}
@@ -315,18 +363,31 @@ func (q *Queries) ListGamesWithTeams(ctx context.Context) ([]ListGamesWithTeamsR
 }
 
 const listPastGames = `-- name: ListPastGames :many
-SELECT id, home_team_id, away_team_id, home_score, away_score, game_time, created_at, updated_at, status FROM games
-WHERE game_time <= NOW()
-ORDER BY game_time DESC
+SELECT g.id, g.home_team_id, g.away_team_id, g.home_score, g.away_score, g.game_time, g.created_at, g.updated_at, g.status FROM games g
+INNER JOIN teams ht ON g.home_team_id = ht.id
+INNER JOIN teams at ON g.away_team_id = at.id
+WHERE ht.season_id = $1
+  AND ($2::bigint IS NULL OR ht.division_id = $2 OR at.division_id = $2)
+  AND g.game_time <= NOW()
+ORDER BY g.game_time DESC
 `
 
+type ListPastGamesParams struct {
+	SeasonID   int64       `json:"seasonId"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
+}
+
 // ListPastGames
 //
-//	SELECT id, home_team_id, away_team_id, home_score, away_score, game_time, created_at, updated_at, status FROM games
-//	WHERE game_time <= NOW()
-//	ORDER BY game_time DESC
-func (q *Queries) ListPastGames(ctx context.Context) ([]Game, error) {
-	rows, err := q.db.Query(ctx, listPastGames)
+//	SELECT g.id, g.home_team_id, g.away_team_id, g.home_score, g.away_score, g.game_time, g.created_at, g.updated_at, g.status FROM games g
+//	INNER JOIN teams ht ON g.home_team_id = ht.id
+//	INNER JOIN teams at ON g.away_team_id = at.id
+//	WHERE ht.season_id = $1
+//	  AND ($2::bigint IS NULL OR ht.division_id = $2 OR at.division_id = $2)
+//	  AND g.game_time <= NOW()
+//	ORDER BY g.game_time DESC
+func (q *Queries) ListPastGames(ctx context.Context, arg ListPastGamesParams) ([]Game, error) {
+	rows, err := q.db.Query(ctx, listPastGames, arg.SeasonID, arg.DivisionID)
 	if err != nil {
 		return nil, err
 	}
@@ -425,18 +486,31 @@ func (q *Queries) ListTeamSchedule(ctx context.Context, homeTeamID int64) ([]Lis
 }
 
 const listUpcomingGames = `-- name: ListUpcomingGames :many
-SELECT id, home_team_id, away_team_id, home_score, away_score, game_time, created_at, updated_at, status FROM games
-WHERE game_time > NOW()
-ORDER BY game_time
+SELECT g.id, g.home_team_id, g.away_team_id, g.home_score, g.away_score, g.game_time, g.created_at, g.updated_at, g.status FROM games g
+INNER JOIN teams ht ON g.home_team_id = ht.id
+INNER JOIN teams at ON g.away_team_id = at.id
+WHERE ht.season_id = $1
+  AND ($2::bigint IS NULL OR ht.division_id = $2 OR at.division_id = $2)
+  AND g.game_time > NOW()
+ORDER BY g.game_time
 `
 
+type ListUpcomingGamesParams struct {
+	SeasonID   int64       `json:"seasonId"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
+}
+
 // ListUpcomingGames
 //
-//	SELECT id, home_team_id, away_team_id, home_score, away_score, game_time, created_at, updated_at, status FROM games
-//	WHERE game_time > NOW()
-//	ORDER BY game_time
-func (q *Queries) ListUpcomingGames(ctx context.Context) ([]Game, error) {
-	rows, err := q.db.Query(ctx, listUpcomingGames)
+//	SELECT g.id, g.home_team_id, g.away_team_id, g.home_score, g.away_score, g.game_time, g.created_at, g.updated_at, g.status FROM games g
+//	INNER JOIN teams ht ON g.home_team_id = ht.id
+//	INNER JOIN teams at ON g.away_team_id = at.id
+//	WHERE ht.season_id = $1
+//	  AND ($2::bigint IS NULL OR ht.division_id = $2 OR at.division_id = $2)
+//	  AND g.game_time > NOW()
+//	ORDER BY g.game_time
+func (q *Queries) ListUpcomingGames(ctx context.Context, arg ListUpcomingGamesParams) ([]Game, error) {
+	rows, err := q.db.Query(ctx, listUpcomingGames, arg.SeasonID, arg.DivisionID)
 	if err != nil {
 		return nil, err
 	}
@@ -8,6 +8,54 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type Bracket struct {
+	ID         int64            `json:"id"`
+	SeasonID   int64            `json:"seasonId"`
+	DivisionID pgtype.Int8      `json:"divisionId"`
+	Name       string           `json:"name"`
+	CreatedAt  pgtype.Timestamp `json:"createdAt"`
+	UpdatedAt  pgtype.Timestamp `json:"updatedAt"`
+}
+
+type BracketMatch struct {
+	ID            int64            `json:"id"`
+	BracketID     int64            `json:"bracketId"`
+	Round         int32            `json:"round"`
+	Position      int32            `json:"position"`
+	HomeSeed      pgtype.Int4      `json:"homeSeed"`
+	AwaySeed      pgtype.Int4      `json:"awaySeed"`
+	HomeTeamID    pgtype.Int8      `json:"homeTeamId"`
+	AwayTeamID    pgtype.Int8      `json:"awayTeamId"`
+	GameID        pgtype.Int8      `json:"gameId"`
+	WinnerTeamID  pgtype.Int8      `json:"winnerTeamId"`
+	NextMatchID   pgtype.Int8      `json:"nextMatchId"`
+	NextMatchSlot pgtype.Text      `json:"nextMatchSlot"`
+	CreatedAt     pgtype.Timestamp `json:"createdAt"`
+	UpdatedAt     pgtype.Timestamp `json:"updatedAt"`
+}
+
+type Division struct {
+	ID              int64            `json:"id"`
+	SeasonID        int64            `json:"seasonId"`
+	Name            string           `json:"name"`
+	CreatedAt       pgtype.Timestamp `json:"createdAt"`
+	UpdatedAt       pgtype.Timestamp `json:"updatedAt"`
+	RegistrationFee pgtype.Numeric   `json:"registrationFee"`
+}
+
+type FeeSchedule struct {
+	ID                      int64            `json:"id"`
+	SeasonID                int64            `json:"seasonId"`
+	EarlyBirdDeadline       pgtype.Date      `json:"earlyBirdDeadline"`
+	EarlyBirdDiscount       pgtype.Numeric   `json:"earlyBirdDiscount"`
+	LateDeadline            pgtype.Date      `json:"lateDeadline"`
+	LateFee                 pgtype.Numeric   `json:"lateFee"`
+	ReturningPlayerDiscount pgtype.Numeric   `json:"returningPlayerDiscount"`
+	SiblingDiscount         pgtype.Numeric   `json:"siblingDiscount"`
+	CreatedAt               pgtype.Timestamp `json:"createdAt"`
+	UpdatedAt               pgtype.Timestamp `json:"updatedAt"`
+}
+
 type Game struct {
 	ID         int64            `json:"id"`
 	HomeTeamID int64            `json:"homeTeamId"`
@@ -20,11 +68,87 @@ type Game struct {
 	Status     string           `json:"status"`
 }
 
+type GameAssignment struct {
+	GameID    int64            `json:"gameId"`
+	UserID    int64            `json:"userId"`
+	CreatedAt pgtype.Timestamp `json:"createdAt"`
+}
+
 type GameDetail struct {
-	ID       int64 `json:"id"`
-	GameID   int64 `json:"gameId"`
-	PlayerID int64 `json:"playerId"`
-	Score    int32 `json:"score"`
+	ID                     int64 `json:"id"`
+	GameID                 int64 `json:"gameId"`
+	PlayerID               int64 `json:"playerId"`
+	Score                  int32 `json:"score"`
+	Rebounds               int32 `json:"rebounds"`
+	Assists                int32 `json:"assists"`
+	Steals                 int32 `json:"steals"`
+	Blocks                 int32 `json:"blocks"`
+	Turnovers              int32 `json:"turnovers"`
+	Fouls                  int32 `json:"fouls"`
+	FieldGoalsMade         int32 `json:"fieldGoalsMade"`
+	FieldGoalsAttempted    int32 `json:"fieldGoalsAttempted"`
+	ThreePointersMade      int32 `json:"threePointersMade"`
+	ThreePointersAttempted int32 `json:"threePointersAttempted"`
+	FreeThrowsMade         int32 `json:"freeThrowsMade"`
+	FreeThrowsAttempted    int32 `json:"freeThrowsAttempted"`
+}
+
+type GameEvent struct {
+	ID            int64            `json:"id"`
+	GameID        int64            `json:"gameId"`
+	Kind          string           `json:"kind"`
+	Period        int32            `json:"period"`
+	ClockSeconds  int32            `json:"clockSeconds"`
+	TeamID        pgtype.Int8      `json:"teamId"`
+	PlayerID      pgtype.Int8      `json:"playerId"`
+	PlayerOutID   pgtype.Int8      `json:"playerOutId"`
+	Points        pgtype.Int4      `json:"points"`
+	UndoesEventID pgtype.Int8      `json:"undoesEventId"`
+	CreatedBy     pgtype.Int8      `json:"createdBy"`
+	CreatedAt     pgtype.Timestamp `json:"createdAt"`
+}
+
+type GameStreamEvent struct {
+	ID        int64            `json:"id"`
+	GameID    int64            `json:"gameId"`
+	GameDate  pgtype.Date      `json:"gameDate"`
+	Kind      string           `json:"kind"`
+	Payload   []byte           `json:"payload"`
+	CreatedAt pgtype.Timestamp `json:"createdAt"`
+}
+
+type IdempotencyKey struct {
+	UserID         int64            `json:"userId"`
+	IdempotencyKey string           `json:"idempotencyKey"`
+	Method         string           `json:"method"`
+	Path           string           `json:"path"`
+	RequestHash    string           `json:"requestHash"`
+	StatusCode     pgtype.Int4      `json:"statusCode"`
+	ContentType    string           `json:"contentType"`
+	ResponseBody   []byte           `json:"responseBody"`
+	CreatedAt      pgtype.Timestamp `json:"createdAt"`
+	ExpiresAt      pgtype.Timestamp `json:"expiresAt"`
+}
+
+type LedgerEntry struct {
+	ID            int64          `json:"id"`
+	TransactionID int64          `json:"transactionId"`
+	Account       string         `json:"account"`
+	PlayerID      pgtype.Int8    `json:"playerId"`
+	Amount        pgtype.Numeric `json:"amount"`
+}
+
+type LedgerTransaction struct {
+	ID          int64            `json:"id"`
+	Kind        string           `json:"kind"`
+	PlayerID    int64            `json:"playerId"`
+	SeasonID    int64            `json:"seasonId"`
+	PaymentID   pgtype.Int8      `json:"paymentId"`
+	RefundID    pgtype.Int8      `json:"refundId"`
+	Amount      pgtype.Numeric   `json:"amount"`
+	Description string           `json:"description"`
+	CreatedBy   pgtype.Int8      `json:"createdBy"`
+	CreatedAt   pgtype.Timestamp `json:"createdAt"`
 }
 
 type PasswordResetToken struct {
@@ -37,12 +161,52 @@ type PasswordResetToken struct {
 }
 
 type Payment struct {
-	ID          int64            `json:"id"`
-	PlayerID    int64            `json:"playerId"`
-	StripeID    string           `json:"stripeId"`
-	Amount      pgtype.Numeric   `json:"amount"`
-	Status      string           `json:"status"`
-	PaymentDate pgtype.Timestamp `json:"paymentDate"`
+	ID                int64            `json:"id"`
+	PlayerID          int64            `json:"playerId"`
+	StripeID          string           `json:"stripeId"`
+	Amount            pgtype.Numeric   `json:"amount"`
+	Status            string           `json:"status"`
+	PaymentDate       pgtype.Timestamp `json:"paymentDate"`
+	AmountRefunded    pgtype.Numeric   `json:"amountRefunded"`
+	Provider          string           `json:"provider"`
+	PaymentIntentID   pgtype.Text      `json:"paymentIntentId"`
+	CheckoutUrl       pgtype.Text      `json:"checkoutUrl"`
+	CheckoutExpiresAt pgtype.Timestamp `json:"checkoutExpiresAt"`
+}
+
+type PaymentPlan struct {
+	ID             int64            `json:"id"`
+	PlayerID       int64            `json:"playerId"`
+	Status         string           `json:"status"`
+	TotalAmount    pgtype.Numeric   `json:"totalAmount"`
+	PaidBeforePlan pgtype.Numeric   `json:"paidBeforePlan"`
+	Notes          string           `json:"notes"`
+	CreatedBy      pgtype.Int8      `json:"createdBy"`
+	CreatedAt      pgtype.Timestamp `json:"createdAt"`
+	UpdatedAt      pgtype.Timestamp `json:"updatedAt"`
+}
+
+type PaymentPlanInstallment struct {
+	ID                int64            `json:"id"`
+	PlanID            int64            `json:"planId"`
+	InstallmentNumber int32            `json:"installmentNumber"`
+	Amount            pgtype.Numeric   `json:"amount"`
+	DueDate           pgtype.Date      `json:"dueDate"`
+	Status            string           `json:"status"`
+	PaidAt            pgtype.Timestamp `json:"paidAt"`
+	CreatedAt         pgtype.Timestamp `json:"createdAt"`
+	UpdatedAt         pgtype.Timestamp `json:"updatedAt"`
+}
+
+type PaymentReminder struct {
+	ID        int64            `json:"id"`
+	PlayerID  int64            `json:"playerId"`
+	Stage     int32            `json:"stage"`
+	AmountDue pgtype.Numeric   `json:"amountDue"`
+	SentTo    string           `json:"sentTo"`
+	Status    string           `json:"status"`
+	Error     pgtype.Text      `json:"error"`
+	CreatedAt pgtype.Timestamp `json:"createdAt"`
 }
 
 type Player struct {
@@ -55,6 +219,79 @@ type Player struct {
 	JerseyNumber       pgtype.Int4      `json:"jerseyNumber"`
 	CreatedAt          pgtype.Timestamp `json:"createdAt"`
 	UpdatedAt          pgtype.Timestamp `json:"updatedAt"`
+	SeasonID           int64            `json:"seasonId"`
+	RegistrationFee    pgtype.Numeric   `json:"registrationFee"`
+	SiblingPlayerID    pgtype.Int8      `json:"siblingPlayerId"`
+	FeeAssessedOn      pgtype.Date      `json:"feeAssessedOn"`
+	RosterLockedAt     pgtype.Timestamp `json:"rosterLockedAt"`
+}
+
+type PromoCode struct {
+	ID             int64            `json:"id"`
+	SeasonID       pgtype.Int8      `json:"seasonId"`
+	Code           string           `json:"code"`
+	Description    string           `json:"description"`
+	DiscountType   string           `json:"discountType"`
+	DiscountValue  pgtype.Numeric   `json:"discountValue"`
+	MaxRedemptions pgtype.Int4      `json:"maxRedemptions"`
+	Redemptions    int32            `json:"redemptions"`
+	ValidFrom      pgtype.Date      `json:"validFrom"`
+	ValidUntil     pgtype.Date      `json:"validUntil"`
+	IsActive       bool             `json:"isActive"`
+	CreatedAt      pgtype.Timestamp `json:"createdAt"`
+	UpdatedAt      pgtype.Timestamp `json:"updatedAt"`
+}
+
+type PromoCodeRedemption struct {
+	ID          int64            `json:"id"`
+	PromoCodeID int64            `json:"promoCodeId"`
+	PlayerID    int64            `json:"playerId"`
+	RedeemedAt  pgtype.Timestamp `json:"redeemedAt"`
+}
+
+type Refund struct {
+	ID               int64            `json:"id"`
+	PaymentID        int64            `json:"paymentId"`
+	Amount           pgtype.Numeric   `json:"amount"`
+	Reason           string           `json:"reason"`
+	Status           string           `json:"status"`
+	ProviderRefundID pgtype.Text      `json:"providerRefundId"`
+	CreatedBy        pgtype.Int8      `json:"createdBy"`
+	CreatedAt        pgtype.Timestamp `json:"createdAt"`
+	UpdatedAt        pgtype.Timestamp `json:"updatedAt"`
+}
+
+type RegistrationFeeItem struct {
+	ID          int64            `json:"id"`
+	PlayerID    int64            `json:"playerId"`
+	Kind        string           `json:"kind"`
+	Description string           `json:"description"`
+	Amount      pgtype.Numeric   `json:"amount"`
+	CreatedAt   pgtype.Timestamp `json:"createdAt"`
+}
+
+type Season struct {
+	ID              int64            `json:"id"`
+	Name            string           `json:"name"`
+	StartDate       pgtype.Date      `json:"startDate"`
+	EndDate         pgtype.Date      `json:"endDate"`
+	IsCurrent       bool             `json:"isCurrent"`
+	CreatedAt       pgtype.Timestamp `json:"createdAt"`
+	UpdatedAt       pgtype.Timestamp `json:"updatedAt"`
+	RegistrationFee pgtype.Numeric   `json:"registrationFee"`
+}
+
+type Session struct {
+	ID                int64            `json:"id"`
+	UserID            int64            `json:"userId"`
+	RefreshTokenHash  string           `json:"refreshTokenHash"`
+	PreviousTokenHash pgtype.Text      `json:"previousTokenHash"`
+	UserAgent         string           `json:"userAgent"`
+	IpAddress         string           `json:"ipAddress"`
+	CreatedAt         pgtype.Timestamp `json:"createdAt"`
+	LastUsedAt        pgtype.Timestamp `json:"lastUsedAt"`
+	ExpiresAt         pgtype.Timestamp `json:"expiresAt"`
+	RevokedAt         pgtype.Timestamp `json:"revokedAt"`
 }
 
 type Team struct {
@@ -67,16 +304,36 @@ type Team struct {
 	PointsAgainst int32            `json:"pointsAgainst"`
 	CreatedAt     pgtype.Timestamp `json:"createdAt"`
 	UpdatedAt     pgtype.Timestamp `json:"updatedAt"`
+	SeasonID      int64            `json:"seasonId"`
+	DivisionID    pgtype.Int8      `json:"divisionId"`
+}
+
+type TeamCaptain struct {
+	TeamID    int64            `json:"teamId"`
+	UserID    int64            `json:"userId"`
+	CreatedAt pgtype.Timestamp `json:"createdAt"`
 }
 
 type User struct {
-	ID           int64            `json:"id"`
-	Email        string           `json:"email"`
-	PhoneNumber  string           `json:"phoneNumber"`
-	PasswordHash string           `json:"passwordHash"`
-	FirstName    string           `json:"firstName"`
-	LastName     string           `json:"lastName"`
-	Role         string           `json:"role"`
-	CreatedAt    pgtype.Timestamp `json:"createdAt"`
-	UpdatedAt    pgtype.Timestamp `json:"updatedAt"`
+	ID                     int64            `json:"id"`
+	Email                  string           `json:"email"`
+	PhoneNumber            string           `json:"phoneNumber"`
+	PasswordHash           string           `json:"passwordHash"`
+	FirstName              string           `json:"firstName"`
+	LastName               string           `json:"lastName"`
+	Role                   string           `json:"role"`
+	CreatedAt              pgtype.Timestamp `json:"createdAt"`
+	UpdatedAt              pgtype.Timestamp `json:"updatedAt"`
+	PaymentRemindersOptOut bool             `json:"paymentRemindersOptOut"`
+}
+
+type WebhookEvent struct {
+	ID         int64            `json:"id"`
+	Provider   string           `json:"provider"`
+	EventID    string           `json:"eventId"`
+	EventType  string           `json:"eventType"`
+	Payload    []byte           `json:"payload"`
+	Outcome    string           `json:"outcome"`
+	Note       string           `json:"note"`
+	ReceivedAt pgtype.Timestamp `json:"receivedAt"`
 }
@@ -11,10 +11,77 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const completePayment = `-- name: CompletePayment :exec
+UPDATE payments
+SET status = 'completed', amount = $1
+WHERE id = $2
+`
+
+type CompletePaymentParams struct {
+	Amount pgtype.Numeric `json:"amount"`
+	ID     int64          `json:"id"`
+}
+
+// CompletePayment
+//
+//	UPDATE payments
+//	SET status = 'completed', amount = $1
+//	WHERE id = $2
+func (q *Queries) CompletePayment(ctx context.Context, arg CompletePaymentParams) error {
+	_, err := q.db.Exec(ctx, completePayment, arg.Amount, arg.ID)
+	return err
+}
+
+const createCheckoutPayment = `-- name: CreateCheckoutPayment :one
+INSERT INTO payments (player_id, stripe_id, amount, status, provider, checkout_url, checkout_expires_at, payment_date)
+VALUES ($1, $2, $3, 'pending', $4, $5, $6, NOW())
+RETURNING id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at
+`
+
+type CreateCheckoutPaymentParams struct {
+	PlayerID          int64            `json:"playerId"`
+	StripeID          string           `json:"stripeId"`
+	Amount            pgtype.Numeric   `json:"amount"`
+	Provider          string           `json:"provider"`
+	CheckoutUrl       pgtype.Text      `json:"checkoutUrl"`
+	CheckoutExpiresAt pgtype.Timestamp `json:"checkoutExpiresAt"`
+}
+
+// CreateCheckoutPayment
+//
+//	INSERT INTO payments (player_id, stripe_id, amount, status, provider, checkout_url, checkout_expires_at, payment_date)
+//	VALUES ($1, $2, $3, 'pending', $4, $5, $6, NOW())
+//	RETURNING id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at
+func (q *Queries) CreateCheckoutPayment(ctx context.Context, arg CreateCheckoutPaymentParams) (Payment, error) {
+	row := q.db.QueryRow(ctx, createCheckoutPayment,
+		arg.PlayerID,
+		arg.StripeID,
+		arg.Amount,
+		arg.Provider,
+		arg.CheckoutUrl,
+		arg.CheckoutExpiresAt,
+	)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.PlayerID,
+		&i.StripeID,
+		&i.Amount,
+		&i.Status,
+		&i.PaymentDate,
+		&i.AmountRefunded,
+		&i.Provider,
+		&i.PaymentIntentID,
+		&i.CheckoutUrl,
+		&i.CheckoutExpiresAt,
+	)
+	return i, err
+}
+
 const createPayment = `-- name: CreatePayment :one
 INSERT INTO payments (player_id, stripe_id, amount, status, payment_date)
 VALUES ($1, $2, $3, $4, NOW())
-RETURNING id, player_id, stripe_id, amount, status, payment_date
+RETURNING id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at
 `
 
 type CreatePaymentParams struct {
@@ -28,7 +95,7 @@ type CreatePaymentParams struct {
 //
 //	INSERT INTO payments (player_id, stripe_id, amount, status, payment_date)
 //	VALUES ($1, $2, $3, $4, NOW())
-//	RETURNING id, player_id, stripe_id, amount, status, payment_date
+//	RETURNING id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at
 func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error) {
 	row := q.db.QueryRow(ctx, createPayment,
 		arg.PlayerID,
@@ -44,31 +111,130 @@ func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (P
 		&i.Amount,
 		&i.Status,
 		&i.PaymentDate,
+		&i.AmountRefunded,
+		&i.Provider,
+		&i.PaymentIntentID,
+		&i.CheckoutUrl,
+		&i.CheckoutExpiresAt,
+	)
+	return i, err
+}
+
+const createProviderPayment = `-- name: CreateProviderPayment :one
+INSERT INTO payments (player_id, stripe_id, amount, status, provider, payment_intent_id, payment_date)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at
+`
+
+type CreateProviderPaymentParams struct {
+	PlayerID        int64            `json:"playerId"`
+	StripeID        string           `json:"stripeId"`
+	Amount          pgtype.Numeric   `json:"amount"`
+	Status          string           `json:"status"`
+	Provider        string           `json:"provider"`
+	PaymentIntentID pgtype.Text      `json:"paymentIntentId"`
+	PaymentDate     pgtype.Timestamp `json:"paymentDate"`
+}
+
+// CreateProviderPayment
+//
+//	INSERT INTO payments (player_id, stripe_id, amount, status, provider, payment_intent_id, payment_date)
+//	VALUES ($1, $2, $3, $4, $5, $6, $7)
+//	RETURNING id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at
+func (q *Queries) CreateProviderPayment(ctx context.Context, arg CreateProviderPaymentParams) (Payment, error) {
+	row := q.db.QueryRow(ctx, createProviderPayment,
+		arg.PlayerID,
+		arg.StripeID,
+		arg.Amount,
+		arg.Status,
+		arg.Provider,
+		arg.PaymentIntentID,
+		arg.PaymentDate,
+	)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.PlayerID,
+		&i.StripeID,
+		&i.Amount,
+		&i.Status,
+		&i.PaymentDate,
+		&i.AmountRefunded,
+		&i.Provider,
+		&i.PaymentIntentID,
+		&i.CheckoutUrl,
+		&i.CheckoutExpiresAt,
 	)
 	return i, err
 }
 
-const deletePayment = `-- name: DeletePayment :exec
+const deletePayment = `-- name: DeletePayment :one
 DELETE FROM payments
 WHERE id = $1
+RETURNING player_id
 `
 
 // DeletePayment
 //
 //	DELETE FROM payments
 //	WHERE id = $1
-func (q *Queries) DeletePayment(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, deletePayment, id)
-	return err
+//	RETURNING player_id
+func (q *Queries) DeletePayment(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRow(ctx, deletePayment, id)
+	var playerID int64
+	err := row.Scan(&playerID)
+	return playerID, err
+}
+
+const getOpenCheckoutPayment = `-- name: GetOpenCheckoutPayment :one
+SELECT id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at FROM payments
+WHERE player_id = $1 AND provider = $2 AND amount = $3
+  AND status = 'pending' AND checkout_url IS NOT NULL
+  AND checkout_expires_at > NOW() + INTERVAL '5 minutes'
+ORDER BY payment_date DESC
+LIMIT 1
+`
+
+type GetOpenCheckoutPaymentParams struct {
+	PlayerID int64          `json:"playerId"`
+	Provider string         `json:"provider"`
+	Amount   pgtype.Numeric `json:"amount"`
+}
+
+// GetOpenCheckoutPayment
+//
+//	SELECT id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at FROM payments
+//	WHERE player_id = $1 AND provider = $2 AND amount = $3
+//	  AND status = 'pending' AND checkout_url IS NOT NULL
+//	  AND checkout_expires_at > NOW() + INTERVAL '5 minutes'
+//	ORDER BY payment_date DESC
+//	LIMIT 1
+func (q *Queries) GetOpenCheckoutPayment(ctx context.Context, arg GetOpenCheckoutPaymentParams) (Payment, error) {
+	row := q.db.QueryRow(ctx, getOpenCheckoutPayment, arg.PlayerID, arg.Provider, arg.Amount)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.PlayerID,
+		&i.StripeID,
+		&i.Amount,
+		&i.Status,
+		&i.PaymentDate,
+		&i.AmountRefunded,
+		&i.Provider,
+		&i.PaymentIntentID,
+		&i.CheckoutUrl,
+		&i.CheckoutExpiresAt,
+	)
+	return i, err
 }
 
 const getPaymentById = `-- name: GetPaymentById :one
-SELECT id, player_id, stripe_id, amount, status, payment_date FROM payments WHERE id = $1
+SELECT id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at FROM payments WHERE id = $1
 `
 
 // GetPaymentById
 //
-//	SELECT id, player_id, stripe_id, amount, status, payment_date FROM payments WHERE id = $1
+//	SELECT id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at FROM payments WHERE id = $1
 func (q *Queries) GetPaymentById(ctx context.Context, id int64) (Payment, error) {
 	row := q.db.QueryRow(ctx, getPaymentById, id)
 	var i Payment
@@ -79,17 +245,50 @@ func (q *Queries) GetPaymentById(ctx context.Context, id int64) (Payment, error)
 		&i.Amount,
 		&i.Status,
 		&i.PaymentDate,
+		&i.AmountRefunded,
+		&i.Provider,
+		&i.PaymentIntentID,
+		&i.CheckoutUrl,
+		&i.CheckoutExpiresAt,
+	)
+	return i, err
+}
+
+const getPaymentByIdForUpdate = `-- name: GetPaymentByIdForUpdate :one
+SELECT id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at FROM payments WHERE id = $1
+FOR UPDATE
+`
+
+// GetPaymentByIdForUpdate
+//
+//	SELECT id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at FROM payments WHERE id = $1
+//	FOR UPDATE
+func (q *Queries) GetPaymentByIdForUpdate(ctx context.Context, id int64) (Payment, error) {
+	row := q.db.QueryRow(ctx, getPaymentByIdForUpdate, id)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.PlayerID,
+		&i.StripeID,
+		&i.Amount,
+		&i.Status,
+		&i.PaymentDate,
+		&i.AmountRefunded,
+		&i.Provider,
+		&i.PaymentIntentID,
+		&i.CheckoutUrl,
+		&i.CheckoutExpiresAt,
 	)
 	return i, err
 }
 
 const getPaymentByStripeId = `-- name: GetPaymentByStripeId :one
-SELECT id, player_id, stripe_id, amount, status, payment_date FROM payments WHERE stripe_id = $1
+SELECT id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at FROM payments WHERE stripe_id = $1
 `
 
 // GetPaymentByStripeId
 //
-//	SELECT id, player_id, stripe_id, amount, status, payment_date FROM payments WHERE stripe_id = $1
+//	SELECT id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at FROM payments WHERE stripe_id = $1
 func (q *Queries) GetPaymentByStripeId(ctx context.Context, stripeID string) (Payment, error) {
 	row := q.db.QueryRow(ctx, getPaymentByStripeId, stripeID)
 	var i Payment
@@ -100,12 +299,109 @@ func (q *Queries) GetPaymentByStripeId(ctx context.Context, stripeID string) (Pa
 		&i.Amount,
 		&i.Status,
 		&i.PaymentDate,
+		&i.AmountRefunded,
+		&i.Provider,
+		&i.PaymentIntentID,
+		&i.CheckoutUrl,
+		&i.CheckoutExpiresAt,
+	)
+	return i, err
+}
+
+const getPaymentByStripeIdForUpdate = `-- name: GetPaymentByStripeIdForUpdate :one
+SELECT id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at FROM payments
+WHERE stripe_id = $1 OR payment_intent_id = $1
+FOR UPDATE
+`
+
+// GetPaymentByStripeIdForUpdate
+//
+//	SELECT id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at FROM payments
+//	WHERE stripe_id = $1 OR payment_intent_id = $1
+//	FOR UPDATE
+func (q *Queries) GetPaymentByStripeIdForUpdate(ctx context.Context, stripeID string) (Payment, error) {
+	row := q.db.QueryRow(ctx, getPaymentByStripeIdForUpdate, stripeID)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.PlayerID,
+		&i.StripeID,
+		&i.Amount,
+		&i.Status,
+		&i.PaymentDate,
+		&i.AmountRefunded,
+		&i.Provider,
+		&i.PaymentIntentID,
+		&i.CheckoutUrl,
+		&i.CheckoutExpiresAt,
+	)
+	return i, err
+}
+
+const getPaymentReceipt = `-- name: GetPaymentReceipt :one
+SELECT py.id, py.player_id, py.stripe_id, py.amount, py.status, py.payment_date, py.amount_refunded, py.provider, py.payment_intent_id, py.checkout_url, py.checkout_expires_at, p.user_id, p.season_id, u.email, u.first_name, u.last_name, s.name AS season_name
+FROM payments py
+INNER JOIN players p ON py.player_id = p.id
+INNER JOIN users u ON p.user_id = u.id
+INNER JOIN seasons s ON s.id = p.season_id
+WHERE py.id = $1
+`
+
+type GetPaymentReceiptRow struct {
+	ID                int64            `json:"id"`
+	PlayerID          int64            `json:"playerId"`
+	StripeID          string           `json:"stripeId"`
+	Amount            pgtype.Numeric   `json:"amount"`
+	Status            string           `json:"status"`
+	PaymentDate       pgtype.Timestamp `json:"paymentDate"`
+	AmountRefunded    pgtype.Numeric   `json:"amountRefunded"`
+	Provider          string           `json:"provider"`
+	PaymentIntentID   pgtype.Text      `json:"paymentIntentId"`
+	CheckoutUrl       pgtype.Text      `json:"checkoutUrl"`
+	CheckoutExpiresAt pgtype.Timestamp `json:"checkoutExpiresAt"`
+	UserID            int64            `json:"userId"`
+	SeasonID          int64            `json:"seasonId"`
+	Email             string           `json:"email"`
+	FirstName         string           `json:"firstName"`
+	LastName          string           `json:"lastName"`
+	SeasonName        string           `json:"seasonName"`
+}
+
+// GetPaymentReceipt
+//
+//	SELECT py.id, py.player_id, py.stripe_id, py.amount, py.status, py.payment_date, py.amount_refunded, py.provider, py.payment_intent_id, py.checkout_url, py.checkout_expires_at, p.user_id, p.season_id, u.email, u.first_name, u.last_name, s.name AS season_name
+//	FROM payments py
+//	INNER JOIN players p ON py.player_id = p.id
+//	INNER JOIN users u ON p.user_id = u.id
+//	INNER JOIN seasons s ON s.id = p.season_id
+//	WHERE py.id = $1
+func (q *Queries) GetPaymentReceipt(ctx context.Context, id int64) (GetPaymentReceiptRow, error) {
+	row := q.db.QueryRow(ctx, getPaymentReceipt, id)
+	var i GetPaymentReceiptRow
+	err := row.Scan(
+		&i.ID,
+		&i.PlayerID,
+		&i.StripeID,
+		&i.Amount,
+		&i.Status,
+		&i.PaymentDate,
+		&i.AmountRefunded,
+		&i.Provider,
+		&i.PaymentIntentID,
+		&i.CheckoutUrl,
+		&i.CheckoutExpiresAt,
+		&i.UserID,
+		&i.SeasonID,
+		&i.Email,
+		&i.FirstName,
+		&i.LastName,
+		&i.SeasonName,
 	)
 	return i, err
 }
 
 const getPaymentWithPlayer = `-- name: GetPaymentWithPlayer :one
-SELECT py.id, py.player_id, py.stripe_id, py.amount, py.status, py.payment_date, p.user_id, u.email, u.first_name, u.last_name
+SELECT py.id, py.player_id, py.stripe_id, py.amount, py.status, py.payment_date, py.amount_refunded, py.provider, py.payment_intent_id, py.checkout_url, py.checkout_expires_at, p.user_id, u.email, u.first_name, u.last_name
 FROM payments py
 INNER JOIN players p ON py.player_id = p.id
 INNER JOIN users u ON p.user_id = u.id
@@ -113,21 +409,26 @@ WHERE py.id = $1
 `
 
 type GetPaymentWithPlayerRow struct {
-	ID          int64            `json:"id"`
-	PlayerID    int64            `json:"playerId"`
-	StripeID    string           `json:"stripeId"`
-	Amount      pgtype.Numeric   `json:"amount"`
-	Status      string           `json:"status"`
-	PaymentDate pgtype.Timestamp `json:"paymentDate"`
-	UserID      int64            `json:"userId"`
-	Email       string           `json:"email"`
-	FirstName   string           `json:"firstName"`
-	LastName    string           `json:"lastName"`
+	ID                int64            `json:"id"`
+	PlayerID          int64            `json:"playerId"`
+	StripeID          string           `json:"stripeId"`
+	Amount            pgtype.Numeric   `json:"amount"`
+	Status            string           `json:"status"`
+	PaymentDate       pgtype.Timestamp `json:"paymentDate"`
+	AmountRefunded    pgtype.Numeric   `json:"amountRefunded"`
+	Provider          string           `json:"provider"`
+	PaymentIntentID   pgtype.Text      `json:"paymentIntentId"`
+	CheckoutUrl       pgtype.Text      `json:"checkoutUrl"`
+	CheckoutExpiresAt pgtype.Timestamp `json:"checkoutExpiresAt"`
+	UserID            int64            `json:"userId"`
+	Email             string           `json:"email"`
+	FirstName         string           `json:"firstName"`
+	LastName          string           `json:"lastName"`
 }
 
 // GetPaymentWithPlayer
 //
-//	SELECT py.id, py.player_id, py.stripe_id, py.amount, py.status, py.payment_date, p.user_id, u.email, u.first_name, u.last_name
+//	SELECT py.id, py.player_id, py.stripe_id, py.amount, py.status, py.payment_date, py.amount_refunded, py.provider, py.payment_intent_id, py.checkout_url, py.checkout_expires_at, p.user_id, u.email, u.first_name, u.last_name
 //	FROM payments py
 //	INNER JOIN players p ON py.player_id = p.id
 //	INNER JOIN users u ON p.user_id = u.id
@@ -142,6 +443,11 @@ func (q *Queries) GetPaymentWithPlayer(ctx context.Context, id int64) (GetPaymen
 		&i.Amount,
 		&i.Status,
 		&i.PaymentDate,
+		&i.AmountRefunded,
+		&i.Provider,
+		&i.PaymentIntentID,
+		&i.CheckoutUrl,
+		&i.CheckoutExpiresAt,
 		&i.UserID,
 		&i.Email,
 		&i.FirstName,
@@ -154,9 +460,11 @@ const getPlayerPaymentSummary = `-- name: GetPlayerPaymentSummary :one
 SELECT 
     player_id,
     COUNT(*) as total_payments,
-    SUM(CASE WHEN status = 'completed' THEN amount ELSE 0 END) as total_paid,
+    SUM(CASE WHEN status IN ('completed', 'refunded') THEN amount ELSE 0 END) as total_paid,
     SUM(CASE WHEN status = 'pending' THEN amount ELSE 0 END) as total_pending,
-    SUM(CASE WHEN status = 'failed' THEN amount ELSE 0 END) as total_failed
+    SUM(CASE WHEN status = 'failed' THEN amount ELSE 0 END) as total_failed,
+    SUM(amount_refunded) as total_refunded,
+    SUM(CASE WHEN status IN ('completed', 'refunded') THEN amount - amount_refunded ELSE 0 END) as net_paid
 FROM payments
 WHERE player_id = $1
 GROUP BY player_id
@@ -168,6 +476,8 @@ type GetPlayerPaymentSummaryRow struct {
 	TotalPaid     int64 `json:"totalPaid"`
 	TotalPending  int64 `json:"totalPending"`
 	TotalFailed   int64 `json:"totalFailed"`
+	TotalRefunded int64 `json:"totalRefunded"`
+	NetPaid       int64 `json:"netPaid"`
 }
 
 // GetPlayerPaymentSummary
@@ -175,9 +485,11 @@ type GetPlayerPaymentSummaryRow struct {
 //	SELECT
 //	    player_id,
 //	    COUNT(*) as total_payments,
-//	    SUM(CASE WHEN status = 'completed' THEN amount ELSE 0 END) as total_paid,
+//	    SUM(CASE WHEN status IN ('completed', 'refunded') THEN amount ELSE 0 END) as total_paid,
 //	    SUM(CASE WHEN status = 'pending' THEN amount ELSE 0 END) as total_pending,
-//	    SUM(CASE WHEN status = 'failed' THEN amount ELSE 0 END) as total_failed
+//	    SUM(CASE WHEN status = 'failed' THEN amount ELSE 0 END) as total_failed,
+//	    SUM(amount_refunded) as total_refunded,
+//	    SUM(CASE WHEN status IN ('completed', 'refunded') THEN amount - amount_refunded ELSE 0 END) as net_paid
 //	FROM payments
 //	WHERE player_id = $1
 //	GROUP BY player_id
@@ -190,21 +502,88 @@ func (q *Queries) GetPlayerPaymentSummary(ctx context.Context, playerID int64) (
 		&i.TotalPaid,
 		&i.TotalPending,
 		&i.TotalFailed,
+		&i.TotalRefunded,
+		&i.NetPaid,
 	)
 	return i, err
 }
 
+const listOpenCheckoutPayments = `-- name: ListOpenCheckoutPayments :many
+SELECT id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at FROM payments
+WHERE player_id = $1 AND provider = $2
+  AND status = 'pending' AND checkout_url IS NOT NULL
+  AND checkout_expires_at > NOW()
+ORDER BY payment_date
+`
+
+type ListOpenCheckoutPaymentsParams struct {
+	PlayerID int64  `json:"playerId"`
+	Provider string `json:"provider"`
+}
+
+// ListOpenCheckoutPayments
+//
+//	SELECT id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at FROM payments
+//	WHERE player_id = $1 AND provider = $2
+//	  AND status = 'pending' AND checkout_url IS NOT NULL
+//	  AND checkout_expires_at > NOW()
+//	ORDER BY payment_date
+func (q *Queries) ListOpenCheckoutPayments(ctx context.Context, arg ListOpenCheckoutPaymentsParams) ([]Payment, error) {
+	rows, err := q.db.Query(ctx, listOpenCheckoutPayments, arg.PlayerID, arg.Provider)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Payment{}
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.PlayerID,
+			&i.StripeID,
+			&i.Amount,
+			&i.Status,
+			&i.PaymentDate,
+			&i.AmountRefunded,
+			&i.Provider,
+			&i.PaymentIntentID,
+			&i.CheckoutUrl,
+			&i.CheckoutExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPayments = `-- name: ListPayments :many
-SELECT id, player_id, stripe_id, amount, status, payment_date FROM payments
-ORDER BY payment_date DESC
+SELECT py.id, py.player_id, py.stripe_id, py.amount, py.status, py.payment_date, py.amount_refunded, py.provider, py.payment_intent_id, py.checkout_url, py.checkout_expires_at FROM payments py
+INNER JOIN players p ON py.player_id = p.id
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE p.season_id = $1
+  AND ($2::bigint IS NULL OR t.division_id = $2)
+ORDER BY py.payment_date DESC
 `
 
+type ListPaymentsParams struct {
+	SeasonID   int64       `json:"seasonId"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
+}
+
 // ListPayments
 //
-//	SELECT id, player_id, stripe_id, amount, status, payment_date FROM payments
-//	ORDER BY payment_date DESC
-func (q *Queries) ListPayments(ctx context.Context) ([]Payment, error) {
-	rows, err := q.db.Query(ctx, listPayments)
+//	SELECT py.id, py.player_id, py.stripe_id, py.amount, py.status, py.payment_date, py.amount_refunded, py.provider, py.payment_intent_id, py.checkout_url, py.checkout_expires_at FROM payments py
+//	INNER JOIN players p ON py.player_id = p.id
+//	LEFT JOIN teams t ON t.id = p.team_id
+//	WHERE p.season_id = $1
+//	  AND ($2::bigint IS NULL OR t.division_id = $2)
+//	ORDER BY py.payment_date DESC
+func (q *Queries) ListPayments(ctx context.Context, arg ListPaymentsParams) ([]Payment, error) {
+	rows, err := q.db.Query(ctx, listPayments, arg.SeasonID, arg.DivisionID)
 	if err != nil {
 		return nil, err
 	}
@@ -219,6 +598,11 @@ func (q *Queries) ListPayments(ctx context.Context) ([]Payment, error) {
 			&i.Amount,
 			&i.Status,
 			&i.PaymentDate,
+			&i.AmountRefunded,
+			&i.Provider,
+			&i.PaymentIntentID,
+			&i.CheckoutUrl,
+			&i.CheckoutExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -231,14 +615,14 @@ func (q *Queries) ListPayments(ctx context.Context) ([]Payment, error) {
 }
 
 const listPaymentsByPlayer = `-- name: ListPaymentsByPlayer :many
-SELECT id, player_id, stripe_id, amount, status, payment_date FROM payments
+SELECT id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at FROM payments
 WHERE player_id = $1
 ORDER BY payment_date DESC
 `
 
 // ListPaymentsByPlayer
 //
-//	SELECT id, player_id, stripe_id, amount, status, payment_date FROM payments
+//	SELECT id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at FROM payments
 //	WHERE player_id = $1
 //	ORDER BY payment_date DESC
 func (q *Queries) ListPaymentsByPlayer(ctx context.Context, playerID int64) ([]Payment, error) {
@@ -257,6 +641,11 @@ func (q *Queries) ListPaymentsByPlayer(ctx context.Context, playerID int64) ([]P
 			&i.Amount,
 			&i.Status,
 			&i.PaymentDate,
+			&i.AmountRefunded,
+			&i.Provider,
+			&i.PaymentIntentID,
+			&i.CheckoutUrl,
+			&i.CheckoutExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -269,18 +658,32 @@ func (q *Queries) ListPaymentsByPlayer(ctx context.Context, playerID int64) ([]P
 }
 
 const listPaymentsByStatus = `-- name: ListPaymentsByStatus :many
-SELECT id, player_id, stripe_id, amount, status, payment_date FROM payments
-WHERE status = $1
-ORDER BY payment_date DESC
+SELECT py.id, py.player_id, py.stripe_id, py.amount, py.status, py.payment_date, py.amount_refunded, py.provider, py.payment_intent_id, py.checkout_url, py.checkout_expires_at FROM payments py
+INNER JOIN players p ON py.player_id = p.id
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE p.season_id = $1
+  AND ($2::bigint IS NULL OR t.division_id = $2)
+  AND py.status = $3
+ORDER BY py.payment_date DESC
 `
 
+type ListPaymentsByStatusParams struct {
+	SeasonID   int64       `json:"seasonId"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
+	Status     string      `json:"status"`
+}
+
 // ListPaymentsByStatus
 //
-//	SELECT id, player_id, stripe_id, amount, status, payment_date FROM payments
-//	WHERE status = $1
-//	ORDER BY payment_date DESC
-func (q *Queries) ListPaymentsByStatus(ctx context.Context, status string) ([]Payment, error) {
-	rows, err := q.db.Query(ctx, listPaymentsByStatus, status)
+//	SELECT py.id, py.player_id, py.stripe_id, py.amount, py.status, py.payment_date, py.amount_refunded, py.provider, py.payment_intent_id, py.checkout_url, py.checkout_expires_at FROM payments py
+//	INNER JOIN players p ON py.player_id = p.id
+//	LEFT JOIN teams t ON t.id = p.team_id
+//	WHERE p.season_id = $1
+//	  AND ($2::bigint IS NULL OR t.division_id = $2)
+//	  AND py.status = $3
+//	ORDER BY py.payment_date DESC
+func (q *Queries) ListPaymentsByStatus(ctx context.Context, arg ListPaymentsByStatusParams) ([]Payment, error) {
+	rows, err := q.db.Query(ctx, listPaymentsByStatus, arg.SeasonID, arg.DivisionID, arg.Status)
 	if err != nil {
 		return nil, err
 	}
@@ -295,6 +698,78 @@ func (q *Queries) ListPaymentsByStatus(ctx context.Context, status string) ([]Pa
 			&i.Amount,
 			&i.Status,
 			&i.PaymentDate,
+			&i.AmountRefunded,
+			&i.Provider,
+			&i.PaymentIntentID,
+			&i.CheckoutUrl,
+			&i.CheckoutExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPaymentsByUser = `-- name: ListPaymentsByUser :many
+SELECT py.id, py.player_id, py.stripe_id, py.amount, py.status, py.payment_date, py.amount_refunded, py.provider, py.payment_intent_id, py.checkout_url, py.checkout_expires_at, p.season_id, s.name AS season_name
+FROM payments py
+INNER JOIN players p ON py.player_id = p.id
+INNER JOIN seasons s ON s.id = p.season_id
+WHERE p.user_id = $1
+ORDER BY py.payment_date DESC
+`
+
+type ListPaymentsByUserRow struct {
+	ID                int64            `json:"id"`
+	PlayerID          int64            `json:"playerId"`
+	StripeID          string           `json:"stripeId"`
+	Amount            pgtype.Numeric   `json:"amount"`
+	Status            string           `json:"status"`
+	PaymentDate       pgtype.Timestamp `json:"paymentDate"`
+	AmountRefunded    pgtype.Numeric   `json:"amountRefunded"`
+	Provider          string           `json:"provider"`
+	PaymentIntentID   pgtype.Text      `json:"paymentIntentId"`
+	CheckoutUrl       pgtype.Text      `json:"checkoutUrl"`
+	CheckoutExpiresAt pgtype.Timestamp `json:"checkoutExpiresAt"`
+	SeasonID          int64            `json:"seasonId"`
+	SeasonName        string           `json:"seasonName"`
+}
+
+// ListPaymentsByUser
+//
+//	SELECT py.id, py.player_id, py.stripe_id, py.amount, py.status, py.payment_date, py.amount_refunded, py.provider, py.payment_intent_id, py.checkout_url, py.checkout_expires_at, p.season_id, s.name AS season_name
+//	FROM payments py
+//	INNER JOIN players p ON py.player_id = p.id
+//	INNER JOIN seasons s ON s.id = p.season_id
+//	WHERE p.user_id = $1
+//	ORDER BY py.payment_date DESC
+func (q *Queries) ListPaymentsByUser(ctx context.Context, userID int64) ([]ListPaymentsByUserRow, error) {
+	rows, err := q.db.Query(ctx, listPaymentsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPaymentsByUserRow{}
+	for rows.Next() {
+		var i ListPaymentsByUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PlayerID,
+			&i.StripeID,
+			&i.Amount,
+			&i.Status,
+			&i.PaymentDate,
+			&i.AmountRefunded,
+			&i.Provider,
+			&i.PaymentIntentID,
+			&i.CheckoutUrl,
+			&i.CheckoutExpiresAt,
+			&i.SeasonID,
+			&i.SeasonName,
 		); err != nil {
 			return nil, err
 		}
@@ -307,34 +782,50 @@ func (q *Queries) ListPaymentsByStatus(ctx context.Context, status string) ([]Pa
 }
 
 const listPaymentsWithPlayerInfo = `-- name: ListPaymentsWithPlayerInfo :many
-SELECT py.id, py.player_id, py.stripe_id, py.amount, py.status, py.payment_date, u.first_name, u.last_name, u.email
+SELECT py.id, py.player_id, py.stripe_id, py.amount, py.status, py.payment_date, py.amount_refunded, py.provider, py.payment_intent_id, py.checkout_url, py.checkout_expires_at, u.first_name, u.last_name, u.email
 FROM payments py
 INNER JOIN players p ON py.player_id = p.id
 INNER JOIN users u ON p.user_id = u.id
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE p.season_id = $1
+  AND ($2::bigint IS NULL OR t.division_id = $2)
 ORDER BY py.payment_date DESC
 `
 
+type ListPaymentsWithPlayerInfoParams struct {
+	SeasonID   int64       `json:"seasonId"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
+}
+
 type ListPaymentsWithPlayerInfoRow struct {
-	ID          int64            `json:"id"`
-	PlayerID    int64            `json:"playerId"`
-	StripeID    string           `json:"stripeId"`
-	Amount      pgtype.Numeric   `json:"amount"`
-	Status      string           `json:"status"`
-	PaymentDate pgtype.Timestamp `json:"paymentDate"`
-	FirstName   string           `json:"firstName"`
-	LastName    string           `json:"lastName"`
-	Email       string           `json:"email"`
+	ID                int64            `json:"id"`
+	PlayerID          int64            `json:"playerId"`
+	StripeID          string           `json:"stripeId"`
+	Amount            pgtype.Numeric   `json:"amount"`
+	Status            string           `json:"status"`
+	PaymentDate       pgtype.Timestamp `json:"paymentDate"`
+	AmountRefunded    pgtype.Numeric   `json:"amountRefunded"`
+	Provider          string           `json:"provider"`
+	PaymentIntentID   pgtype.Text      `json:"paymentIntentId"`
+	CheckoutUrl       pgtype.Text      `json:"checkoutUrl"`
+	CheckoutExpiresAt pgtype.Timestamp `json:"checkoutExpiresAt"`
+	FirstName         string           `json:"firstName"`
+	LastName          string           `json:"lastName"`
+	Email             string           `json:"email"`
 }
 
 // ListPaymentsWithPlayerInfo
 //
-//	SELECT py.id, py.player_id, py.stripe_id, py.amount, py.status, py.payment_date, u.first_name, u.last_name, u.email
+//	SELECT py.id, py.player_id, py.stripe_id, py.amount, py.status, py.payment_date, py.amount_refunded, py.provider, py.payment_intent_id, py.checkout_url, py.checkout_expires_at, u.first_name, u.last_name, u.email
 //	FROM payments py
 //	INNER JOIN players p ON py.player_id = p.id
 //	INNER JOIN users u ON p.user_id = u.id
+//	LEFT JOIN teams t ON t.id = p.team_id
+//	WHERE p.season_id = $1
+//	  AND ($2::bigint IS NULL OR t.division_id = $2)
 //	ORDER BY py.payment_date DESC
-func (q *Queries) ListPaymentsWithPlayerInfo(ctx context.Context) ([]ListPaymentsWithPlayerInfoRow, error) {
-	rows, err := q.db.Query(ctx, listPaymentsWithPlayerInfo)
+func (q *Queries) ListPaymentsWithPlayerInfo(ctx context.Context, arg ListPaymentsWithPlayerInfoParams) ([]ListPaymentsWithPlayerInfoRow, error) {
+	rows, err := q.db.Query(ctx, listPaymentsWithPlayerInfo, arg.SeasonID, arg.DivisionID)
 	if err != nil {
 		return nil, err
 	}
@@ -349,6 +840,11 @@ func (q *Queries) ListPaymentsWithPlayerInfo(ctx context.Context) ([]ListPayment
 			&i.Amount,
 			&i.Status,
 			&i.PaymentDate,
+			&i.AmountRefunded,
+			&i.Provider,
+			&i.PaymentIntentID,
+			&i.CheckoutUrl,
+			&i.CheckoutExpiresAt,
 			&i.FirstName,
 			&i.LastName,
 			&i.Email,
@@ -363,10 +859,105 @@ func (q *Queries) ListPaymentsWithPlayerInfo(ctx context.Context) ([]ListPayment
 	return items, nil
 }
 
-const updatePaymentStatus = `-- name: UpdatePaymentStatus :exec
+const listProviderPaymentsBetween = `-- name: ListProviderPaymentsBetween :many
+SELECT id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at FROM payments
+WHERE provider = $1 AND payment_date >= $2 AND payment_date < $3
+  AND status IN ('completed', 'refunded')
+ORDER BY payment_date
+`
+
+type ListProviderPaymentsBetweenParams struct {
+	Provider string           `json:"provider"`
+	FromDate pgtype.Timestamp `json:"fromDate"`
+	ToDate   pgtype.Timestamp `json:"toDate"`
+}
+
+// ListProviderPaymentsBetween
+//
+//	SELECT id, player_id, stripe_id, amount, status, payment_date, amount_refunded, provider, payment_intent_id, checkout_url, checkout_expires_at FROM payments
+//	WHERE provider = $1 AND payment_date >= $2 AND payment_date < $3
+//	  AND status IN ('completed', 'refunded')
+//	ORDER BY payment_date
+func (q *Queries) ListProviderPaymentsBetween(ctx context.Context, arg ListProviderPaymentsBetweenParams) ([]Payment, error) {
+	rows, err := q.db.Query(ctx, listProviderPaymentsBetween, arg.Provider, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Payment{}
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.PlayerID,
+			&i.StripeID,
+			&i.Amount,
+			&i.Status,
+			&i.PaymentDate,
+			&i.AmountRefunded,
+			&i.Provider,
+			&i.PaymentIntentID,
+			&i.CheckoutUrl,
+			&i.CheckoutExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setPaymentIntentId = `-- name: SetPaymentIntentId :exec
+UPDATE payments
+SET payment_intent_id = $1
+WHERE id = $2
+`
+
+type SetPaymentIntentIdParams struct {
+	PaymentIntentID pgtype.Text `json:"paymentIntentId"`
+	ID              int64       `json:"id"`
+}
+
+// SetPaymentIntentId
+//
+//	UPDATE payments
+//	SET payment_intent_id = $1
+//	WHERE id = $2
+func (q *Queries) SetPaymentIntentId(ctx context.Context, arg SetPaymentIntentIdParams) error {
+	_, err := q.db.Exec(ctx, setPaymentIntentId, arg.PaymentIntentID, arg.ID)
+	return err
+}
+
+const updatePaymentFromProvider = `-- name: UpdatePaymentFromProvider :exec
+UPDATE payments
+SET amount = $1, status = $2
+WHERE id = $3
+`
+
+type UpdatePaymentFromProviderParams struct {
+	Amount pgtype.Numeric `json:"amount"`
+	Status string         `json:"status"`
+	ID     int64          `json:"id"`
+}
+
+// UpdatePaymentFromProvider
+//
+//	UPDATE payments
+//	SET amount = $1, status = $2
+//	WHERE id = $3
+func (q *Queries) UpdatePaymentFromProvider(ctx context.Context, arg UpdatePaymentFromProviderParams) error {
+	_, err := q.db.Exec(ctx, updatePaymentFromProvider, arg.Amount, arg.Status, arg.ID)
+	return err
+}
+
+const updatePaymentStatus = `-- name: UpdatePaymentStatus :one
 UPDATE payments
 SET status = $1
 WHERE id = $2
+RETURNING player_id
 `
 
 type UpdatePaymentStatusParams struct {
@@ -379,7 +970,10 @@ type UpdatePaymentStatusParams struct {
 //	UPDATE payments
 //	SET status = $1
 //	WHERE id = $2
-func (q *Queries) UpdatePaymentStatus(ctx context.Context, arg UpdatePaymentStatusParams) error {
-	_, err := q.db.Exec(ctx, updatePaymentStatus, arg.Status, arg.ID)
-	return err
+//	RETURNING player_id
+func (q *Queries) UpdatePaymentStatus(ctx context.Context, arg UpdatePaymentStatusParams) (int64, error) {
+	row := q.db.QueryRow(ctx, updatePaymentStatus, arg.Status, arg.ID)
+	var playerID int64
+	err := row.Scan(&playerID)
+	return playerID, err
 }
@@ -12,33 +12,35 @@ import (
 )
 
 const createPlayer = `-- name: CreatePlayer :one
-INSERT INTO players (user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
-RETURNING id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at
+INSERT INTO players (user_id, team_id, is_active, jersey_number, sibling_player_id, season_id, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5,
+        COALESCE((SELECT t.season_id FROM teams t WHERE t.id = $2), current_season_id()),
+        NOW(), NOW())
+RETURNING id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at, season_id, registration_fee, sibling_player_id, fee_assessed_on, roster_locked_at
 `
 
 type CreatePlayerParams struct {
-	UserID             int64          `json:"userId"`
-	TeamID             pgtype.Int8    `json:"teamId"`
-	RegistrationFeeDue pgtype.Numeric `json:"registrationFeeDue"`
-	IsFullyRegistered  bool           `json:"isFullyRegistered"`
-	IsActive           bool           `json:"isActive"`
-	JerseyNumber       pgtype.Int4    `json:"jerseyNumber"`
+	UserID          int64       `json:"userId"`
+	TeamID          pgtype.Int8 `json:"teamId"`
+	IsActive        bool        `json:"isActive"`
+	JerseyNumber    pgtype.Int4 `json:"jerseyNumber"`
+	SiblingPlayerID pgtype.Int8 `json:"siblingPlayerId"`
 }
 
 // CreatePlayer
 //
-//	INSERT INTO players (user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at)
-//	VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
-//	RETURNING id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at
+//	INSERT INTO players (user_id, team_id, is_active, jersey_number, sibling_player_id, season_id, created_at, updated_at)
+//	VALUES ($1, $2, $3, $4, $5,
+//	        COALESCE((SELECT t.season_id FROM teams t WHERE t.id = $2), current_season_id()),
+//	        NOW(), NOW())
+//	RETURNING id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at, season_id, registration_fee, sibling_player_id, fee_assessed_on, roster_locked_at
 func (q *Queries) CreatePlayer(ctx context.Context, arg CreatePlayerParams) (Player, error) {
 	row := q.db.QueryRow(ctx, createPlayer,
 		arg.UserID,
 		arg.TeamID,
-		arg.RegistrationFeeDue,
-		arg.IsFullyRegistered,
 		arg.IsActive,
 		arg.JerseyNumber,
+		arg.SiblingPlayerID,
 	)
 	var i Player
 	err := row.Scan(
@@ -51,6 +53,11 @@ func (q *Queries) CreatePlayer(ctx context.Context, arg CreatePlayerParams) (Pla
 		&i.JerseyNumber,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SeasonID,
+		&i.RegistrationFee,
+		&i.SiblingPlayerID,
+		&i.FeeAssessedOn,
+		&i.RosterLockedAt,
 	)
 	return i, err
 }
@@ -70,12 +77,12 @@ func (q *Queries) DeletePlayer(ctx context.Context, id int64) error {
 }
 
 const getPlayerById = `-- name: GetPlayerById :one
-SELECT id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at FROM players WHERE id = $1
+SELECT id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at, season_id, registration_fee, sibling_player_id, fee_assessed_on, roster_locked_at FROM players WHERE id = $1
 `
 
 // GetPlayerById
 //
-//	SELECT id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at FROM players WHERE id = $1
+//	SELECT id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at, season_id, registration_fee, sibling_player_id, fee_assessed_on, roster_locked_at FROM players WHERE id = $1
 func (q *Queries) GetPlayerById(ctx context.Context, id int64) (Player, error) {
 	row := q.db.QueryRow(ctx, getPlayerById, id)
 	var i Player
@@ -89,17 +96,97 @@ func (q *Queries) GetPlayerById(ctx context.Context, id int64) (Player, error) {
 		&i.JerseyNumber,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SeasonID,
+		&i.RegistrationFee,
+		&i.SiblingPlayerID,
+		&i.FeeAssessedOn,
+		&i.RosterLockedAt,
+	)
+	return i, err
+}
+
+const getPlayerByIdForUpdate = `-- name: GetPlayerByIdForUpdate :one
+SELECT id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at, season_id, registration_fee, sibling_player_id, fee_assessed_on, roster_locked_at FROM players WHERE id = $1
+FOR NO KEY UPDATE
+`
+
+// GetPlayerByIdForUpdate
+//
+//	SELECT id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at, season_id, registration_fee, sibling_player_id, fee_assessed_on, roster_locked_at FROM players WHERE id = $1
+//	FOR NO KEY UPDATE
+func (q *Queries) GetPlayerByIdForUpdate(ctx context.Context, id int64) (Player, error) {
+	row := q.db.QueryRow(ctx, getPlayerByIdForUpdate, id)
+	var i Player
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TeamID,
+		&i.RegistrationFeeDue,
+		&i.IsFullyRegistered,
+		&i.IsActive,
+		&i.JerseyNumber,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SeasonID,
+		&i.RegistrationFee,
+		&i.SiblingPlayerID,
+		&i.FeeAssessedOn,
+		&i.RosterLockedAt,
+	)
+	return i, err
+}
+
+const getPlayerByUserAndSeason = `-- name: GetPlayerByUserAndSeason :one
+SELECT id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at, season_id, registration_fee, sibling_player_id, fee_assessed_on, roster_locked_at FROM players
+WHERE user_id = $1 AND season_id = $2
+`
+
+type GetPlayerByUserAndSeasonParams struct {
+	UserID   int64 `json:"userId"`
+	SeasonID int64 `json:"seasonId"`
+}
+
+// GetPlayerByUserAndSeason
+//
+//	SELECT id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at, season_id, registration_fee, sibling_player_id, fee_assessed_on, roster_locked_at FROM players
+//	WHERE user_id = $1 AND season_id = $2
+func (q *Queries) GetPlayerByUserAndSeason(ctx context.Context, arg GetPlayerByUserAndSeasonParams) (Player, error) {
+	row := q.db.QueryRow(ctx, getPlayerByUserAndSeason, arg.UserID, arg.SeasonID)
+	var i Player
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TeamID,
+		&i.RegistrationFeeDue,
+		&i.IsFullyRegistered,
+		&i.IsActive,
+		&i.JerseyNumber,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SeasonID,
+		&i.RegistrationFee,
+		&i.SiblingPlayerID,
+		&i.FeeAssessedOn,
+		&i.RosterLockedAt,
 	)
 	return i, err
 }
 
 const getPlayerByUserId = `-- name: GetPlayerByUserId :one
-SELECT id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at FROM players WHERE user_id = $1
+SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, p.season_id, p.registration_fee, p.sibling_player_id, p.fee_assessed_on, p.roster_locked_at FROM players p
+INNER JOIN seasons s ON s.id = p.season_id
+WHERE p.user_id = $1
+ORDER BY s.is_current DESC, s.start_date DESC
+LIMIT 1
 `
 
 // GetPlayerByUserId
 //
-//	SELECT id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at FROM players WHERE user_id = $1
+//	SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, p.season_id, p.registration_fee, p.sibling_player_id, p.fee_assessed_on, p.roster_locked_at FROM players p
+//	INNER JOIN seasons s ON s.id = p.season_id
+//	WHERE p.user_id = $1
+//	ORDER BY s.is_current DESC, s.start_date DESC
+//	LIMIT 1
 func (q *Queries) GetPlayerByUserId(ctx context.Context, userID int64) (Player, error) {
 	row := q.db.QueryRow(ctx, getPlayerByUserId, userID)
 	var i Player
@@ -113,12 +200,17 @@ func (q *Queries) GetPlayerByUserId(ctx context.Context, userID int64) (Player,
 		&i.JerseyNumber,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SeasonID,
+		&i.RegistrationFee,
+		&i.SiblingPlayerID,
+		&i.FeeAssessedOn,
+		&i.RosterLockedAt,
 	)
 	return i, err
 }
 
 const getPlayerWithTeam = `-- name: GetPlayerWithTeam :one
-SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, t.name as team_name
+SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, p.season_id, p.registration_fee, p.sibling_player_id, p.fee_assessed_on, p.roster_locked_at, t.name as team_name
 FROM players p
 LEFT JOIN teams t ON p.team_id = t.id
 WHERE p.id = $1
@@ -134,12 +226,17 @@ type GetPlayerWithTeamRow struct {
 	JerseyNumber       pgtype.Int4      `json:"jerseyNumber"`
 	CreatedAt          pgtype.Timestamp `json:"createdAt"`
 	UpdatedAt          pgtype.Timestamp `json:"updatedAt"`
+	SeasonID           int64            `json:"seasonId"`
+	RegistrationFee    pgtype.Numeric   `json:"registrationFee"`
+	SiblingPlayerID    pgtype.Int8      `json:"siblingPlayerId"`
+	FeeAssessedOn      pgtype.Date      `json:"feeAssessedOn"`
+	RosterLockedAt     pgtype.Timestamp `json:"rosterLockedAt"`
 	TeamName           pgtype.Text      `json:"teamName"`
 }
 
 // GetPlayerWithTeam
 //
-//	SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, t.name as team_name
+//	SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, p.season_id, p.registration_fee, p.sibling_player_id, p.fee_assessed_on, p.roster_locked_at, t.name as team_name
 //	FROM players p
 //	LEFT JOIN teams t ON p.team_id = t.id
 //	WHERE p.id = $1
@@ -156,13 +253,18 @@ func (q *Queries) GetPlayerWithTeam(ctx context.Context, id int64) (GetPlayerWit
 		&i.JerseyNumber,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SeasonID,
+		&i.RegistrationFee,
+		&i.SiblingPlayerID,
+		&i.FeeAssessedOn,
+		&i.RosterLockedAt,
 		&i.TeamName,
 	)
 	return i, err
 }
 
 const getPlayerWithUser = `-- name: GetPlayerWithUser :one
-SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, u.email, u.phone_number, u.first_name, u.last_name, u.role
+SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, p.season_id, p.registration_fee, p.sibling_player_id, p.fee_assessed_on, p.roster_locked_at, u.email, u.phone_number, u.first_name, u.last_name, u.role
 FROM players p
 INNER JOIN users u ON p.user_id = u.id
 WHERE p.id = $1
@@ -178,6 +280,11 @@ type GetPlayerWithUserRow struct {
 	JerseyNumber       pgtype.Int4      `json:"jerseyNumber"`
 	CreatedAt          pgtype.Timestamp `json:"createdAt"`
 	UpdatedAt          pgtype.Timestamp `json:"updatedAt"`
+	SeasonID           int64            `json:"seasonId"`
+	RegistrationFee    pgtype.Numeric   `json:"registrationFee"`
+	SiblingPlayerID    pgtype.Int8      `json:"siblingPlayerId"`
+	FeeAssessedOn      pgtype.Date      `json:"feeAssessedOn"`
+	RosterLockedAt     pgtype.Timestamp `json:"rosterLockedAt"`
 	Email              string           `json:"email"`
 	PhoneNumber        string           `json:"phoneNumber"`
 	FirstName          string           `json:"firstName"`
@@ -187,7 +294,7 @@ type GetPlayerWithUserRow struct {
 
 // GetPlayerWithUser
 //
-//	SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, u.email, u.phone_number, u.first_name, u.last_name, u.role
+//	SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, p.season_id, p.registration_fee, p.sibling_player_id, p.fee_assessed_on, p.roster_locked_at, u.email, u.phone_number, u.first_name, u.last_name, u.role
 //	FROM players p
 //	INNER JOIN users u ON p.user_id = u.id
 //	WHERE p.id = $1
@@ -204,6 +311,11 @@ func (q *Queries) GetPlayerWithUser(ctx context.Context, id int64) (GetPlayerWit
 		&i.JerseyNumber,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SeasonID,
+		&i.RegistrationFee,
+		&i.SiblingPlayerID,
+		&i.FeeAssessedOn,
+		&i.RosterLockedAt,
 		&i.Email,
 		&i.PhoneNumber,
 		&i.FirstName,
@@ -214,18 +326,29 @@ func (q *Queries) GetPlayerWithUser(ctx context.Context, id int64) (GetPlayerWit
 }
 
 const listActivePlayers = `-- name: ListActivePlayers :many
-SELECT id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at FROM players
-WHERE is_active = true
-ORDER BY id
+SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, p.season_id, p.registration_fee, p.sibling_player_id, p.fee_assessed_on, p.roster_locked_at FROM players p
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE p.season_id = $1
+  AND ($2::bigint IS NULL OR t.division_id = $2)
+  AND p.is_active = true
+ORDER BY p.id
 `
 
+type ListActivePlayersParams struct {
+	SeasonID   int64       `json:"seasonId"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
+}
+
 // ListActivePlayers
 //
-//	SELECT id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at FROM players
-//	WHERE is_active = true
-//	ORDER BY id
-func (q *Queries) ListActivePlayers(ctx context.Context) ([]Player, error) {
-	rows, err := q.db.Query(ctx, listActivePlayers)
+//	SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, p.season_id, p.registration_fee, p.sibling_player_id, p.fee_assessed_on, p.roster_locked_at FROM players p
+//	LEFT JOIN teams t ON t.id = p.team_id
+//	WHERE p.season_id = $1
+//	  AND ($2::bigint IS NULL OR t.division_id = $2)
+//	  AND p.is_active = true
+//	ORDER BY p.id
+func (q *Queries) ListActivePlayers(ctx context.Context, arg ListActivePlayersParams) ([]Player, error) {
+	rows, err := q.db.Query(ctx, listActivePlayers, arg.SeasonID, arg.DivisionID)
 	if err != nil {
 		return nil, err
 	}
@@ -243,6 +366,11 @@ func (q *Queries) ListActivePlayers(ctx context.Context) ([]Player, error) {
 			&i.JerseyNumber,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SeasonID,
+			&i.RegistrationFee,
+			&i.SiblingPlayerID,
+			&i.FeeAssessedOn,
+			&i.RosterLockedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -255,10 +383,10 @@ func (q *Queries) ListActivePlayers(ctx context.Context) ([]Player, error) {
 }
 
 const listFreeAgents = `-- name: ListFreeAgents :many
-SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, u.email, u.first_name, u.last_name
+SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, p.season_id, p.registration_fee, p.sibling_player_id, p.fee_assessed_on, p.roster_locked_at, u.email, u.first_name, u.last_name
 FROM players p
 INNER JOIN users u ON p.user_id = u.id
-WHERE p.team_id IS NULL AND p.is_active = true
+WHERE p.season_id = $1 AND p.team_id IS NULL AND p.is_active = true
 ORDER BY u.last_name, u.first_name
 `
 
@@ -272,6 +400,11 @@ type ListFreeAgentsRow struct {
 	JerseyNumber       pgtype.Int4      `json:"jerseyNumber"`
 	CreatedAt          pgtype.Timestamp `json:"createdAt"`
 	UpdatedAt          pgtype.Timestamp `json:"updatedAt"`
+	SeasonID           int64            `json:"seasonId"`
+	RegistrationFee    pgtype.Numeric   `json:"registrationFee"`
+	SiblingPlayerID    pgtype.Int8      `json:"siblingPlayerId"`
+	FeeAssessedOn      pgtype.Date      `json:"feeAssessedOn"`
+	RosterLockedAt     pgtype.Timestamp `json:"rosterLockedAt"`
 	Email              string           `json:"email"`
 	FirstName          string           `json:"firstName"`
 	LastName           string           `json:"lastName"`
@@ -279,13 +412,13 @@ type ListFreeAgentsRow struct {
 
 // ListFreeAgents
 //
-//	SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, u.email, u.first_name, u.last_name
+//	SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, p.season_id, p.registration_fee, p.sibling_player_id, p.fee_assessed_on, p.roster_locked_at, u.email, u.first_name, u.last_name
 //	FROM players p
 //	INNER JOIN users u ON p.user_id = u.id
-//	WHERE p.team_id IS NULL AND p.is_active = true
+//	WHERE p.season_id = $1 AND p.team_id IS NULL AND p.is_active = true
 //	ORDER BY u.last_name, u.first_name
-func (q *Queries) ListFreeAgents(ctx context.Context) ([]ListFreeAgentsRow, error) {
-	rows, err := q.db.Query(ctx, listFreeAgents)
+func (q *Queries) ListFreeAgents(ctx context.Context, seasonID int64) ([]ListFreeAgentsRow, error) {
+	rows, err := q.db.Query(ctx, listFreeAgents, seasonID)
 	if err != nil {
 		return nil, err
 	}
@@ -303,6 +436,11 @@ func (q *Queries) ListFreeAgents(ctx context.Context) ([]ListFreeAgentsRow, erro
 			&i.JerseyNumber,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SeasonID,
+			&i.RegistrationFee,
+			&i.SiblingPlayerID,
+			&i.FeeAssessedOn,
+			&i.RosterLockedAt,
 			&i.Email,
 			&i.FirstName,
 			&i.LastName,
@@ -317,17 +455,89 @@ func (q *Queries) ListFreeAgents(ctx context.Context) ([]ListFreeAgentsRow, erro
 	return items, nil
 }
 
+const listPlayerCareerSeasons = `-- name: ListPlayerCareerSeasons :many
+SELECT p.id, p.season_id, s.name AS season_name, p.team_id, t.name AS team_name, p.jersey_number,
+       u.first_name, u.last_name
+FROM players p
+INNER JOIN seasons s ON s.id = p.season_id
+INNER JOIN users u ON u.id = p.user_id
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE p.user_id = (SELECT user_id FROM players WHERE id = $1)
+ORDER BY s.start_date, p.id
+`
+
+type ListPlayerCareerSeasonsRow struct {
+	ID           int64       `json:"id"`
+	SeasonID     int64       `json:"seasonId"`
+	SeasonName   string      `json:"seasonName"`
+	TeamID       pgtype.Int8 `json:"teamId"`
+	TeamName     pgtype.Text `json:"teamName"`
+	JerseyNumber pgtype.Int4 `json:"jerseyNumber"`
+	FirstName    string      `json:"firstName"`
+	LastName     string      `json:"lastName"`
+}
+
+// ListPlayerCareerSeasons
+//
+//	SELECT p.id, p.season_id, s.name AS season_name, p.team_id, t.name AS team_name, p.jersey_number,
+//	       u.first_name, u.last_name
+//	FROM players p
+//	INNER JOIN seasons s ON s.id = p.season_id
+//	INNER JOIN users u ON u.id = p.user_id
+//	LEFT JOIN teams t ON t.id = p.team_id
+//	WHERE p.user_id = (SELECT user_id FROM players WHERE id = $1)
+//	ORDER BY s.start_date, p.id
+func (q *Queries) ListPlayerCareerSeasons(ctx context.Context, playerID int64) ([]ListPlayerCareerSeasonsRow, error) {
+	rows, err := q.db.Query(ctx, listPlayerCareerSeasons, playerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPlayerCareerSeasonsRow{}
+	for rows.Next() {
+		var i ListPlayerCareerSeasonsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SeasonID,
+			&i.SeasonName,
+			&i.TeamID,
+			&i.TeamName,
+			&i.JerseyNumber,
+			&i.FirstName,
+			&i.LastName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPlayers = `-- name: ListPlayers :many
-SELECT id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at FROM players
-ORDER BY id
+SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, p.season_id, p.registration_fee, p.sibling_player_id, p.fee_assessed_on, p.roster_locked_at FROM players p
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE p.season_id = $1
+  AND ($2::bigint IS NULL OR t.division_id = $2)
+ORDER BY p.id
 `
 
+type ListPlayersParams struct {
+	SeasonID   int64       `json:"seasonId"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
+}
+
 // ListPlayers
 //
-//	SELECT id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at FROM players
-//	ORDER BY id
-func (q *Queries) ListPlayers(ctx context.Context) ([]Player, error) {
-	rows, err := q.db.Query(ctx, listPlayers)
+//	SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, p.season_id, p.registration_fee, p.sibling_player_id, p.fee_assessed_on, p.roster_locked_at FROM players p
+//	LEFT JOIN teams t ON t.id = p.team_id
+//	WHERE p.season_id = $1
+//	  AND ($2::bigint IS NULL OR t.division_id = $2)
+//	ORDER BY p.id
+func (q *Queries) ListPlayers(ctx context.Context, arg ListPlayersParams) ([]Player, error) {
+	rows, err := q.db.Query(ctx, listPlayers, arg.SeasonID, arg.DivisionID)
 	if err != nil {
 		return nil, err
 	}
@@ -345,6 +555,11 @@ func (q *Queries) ListPlayers(ctx context.Context) ([]Player, error) {
 			&i.JerseyNumber,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SeasonID,
+			&i.RegistrationFee,
+			&i.SiblingPlayerID,
+			&i.FeeAssessedOn,
+			&i.RosterLockedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -357,14 +572,14 @@ func (q *Queries) ListPlayers(ctx context.Context) ([]Player, error) {
 }
 
 const listPlayersByTeam = `-- name: ListPlayersByTeam :many
-SELECT id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at FROM players
+SELECT id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at, season_id, registration_fee, sibling_player_id, fee_assessed_on, roster_locked_at FROM players
 WHERE team_id = $1
 ORDER BY jersey_number, id
 `
 
 // ListPlayersByTeam
 //
-//	SELECT id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at FROM players
+//	SELECT id, user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at, season_id, registration_fee, sibling_player_id, fee_assessed_on, roster_locked_at FROM players
 //	WHERE team_id = $1
 //	ORDER BY jersey_number, id
 func (q *Queries) ListPlayersByTeam(ctx context.Context, teamID pgtype.Int8) ([]Player, error) {
@@ -386,6 +601,11 @@ func (q *Queries) ListPlayersByTeam(ctx context.Context, teamID pgtype.Int8) ([]
 			&i.JerseyNumber,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SeasonID,
+			&i.RegistrationFee,
+			&i.SiblingPlayerID,
+			&i.FeeAssessedOn,
+			&i.RosterLockedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -398,12 +618,20 @@ func (q *Queries) ListPlayersByTeam(ctx context.Context, teamID pgtype.Int8) ([]
 }
 
 const listPlayersWithUsers = `-- name: ListPlayersWithUsers :many
-SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, u.email, u.first_name, u.last_name
+SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, p.season_id, p.registration_fee, p.sibling_player_id, p.fee_assessed_on, p.roster_locked_at, u.email, u.first_name, u.last_name
 FROM players p
 INNER JOIN users u ON p.user_id = u.id
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE p.season_id = $1
+  AND ($2::bigint IS NULL OR t.division_id = $2)
 ORDER BY u.last_name, u.first_name
 `
 
+type ListPlayersWithUsersParams struct {
+	SeasonID   int64       `json:"seasonId"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
+}
+
 type ListPlayersWithUsersRow struct {
 	ID                 int64            `json:"id"`
 	UserID             int64            `json:"userId"`
@@ -414,6 +642,11 @@ type ListPlayersWithUsersRow struct {
 	JerseyNumber       pgtype.Int4      `json:"jerseyNumber"`
 	CreatedAt          pgtype.Timestamp `json:"createdAt"`
 	UpdatedAt          pgtype.Timestamp `json:"updatedAt"`
+	SeasonID           int64            `json:"seasonId"`
+	RegistrationFee    pgtype.Numeric   `json:"registrationFee"`
+	SiblingPlayerID    pgtype.Int8      `json:"siblingPlayerId"`
+	FeeAssessedOn      pgtype.Date      `json:"feeAssessedOn"`
+	RosterLockedAt     pgtype.Timestamp `json:"rosterLockedAt"`
 	Email              string           `json:"email"`
 	FirstName          string           `json:"firstName"`
 	LastName           string           `json:"lastName"`
@@ -421,12 +654,15 @@ type ListPlayersWithUsersRow struct {
 
 // ListPlayersWithUsers
 //
-//	SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, u.email, u.first_name, u.last_name
+//	SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered, p.is_active, p.jersey_number, p.created_at, p.updated_at, p.season_id, p.registration_fee, p.sibling_player_id, p.fee_assessed_on, p.roster_locked_at, u.email, u.first_name, u.last_name
 //	FROM players p
 //	INNER JOIN users u ON p.user_id = u.id
+//	LEFT JOIN teams t ON t.id = p.team_id
+//	WHERE p.season_id = $1
+//	  AND ($2::bigint IS NULL OR t.division_id = $2)
 //	ORDER BY u.last_name, u.first_name
-func (q *Queries) ListPlayersWithUsers(ctx context.Context) ([]ListPlayersWithUsersRow, error) {
-	rows, err := q.db.Query(ctx, listPlayersWithUsers)
+func (q *Queries) ListPlayersWithUsers(ctx context.Context, arg ListPlayersWithUsersParams) ([]ListPlayersWithUsersRow, error) {
+	rows, err := q.db.Query(ctx, listPlayersWithUsers, arg.SeasonID, arg.DivisionID)
 	if err != nil {
 		return nil, err
 	}
@@ -444,6 +680,11 @@ func (q *Queries) ListPlayersWithUsers(ctx context.Context) ([]ListPlayersWithUs
 			&i.JerseyNumber,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SeasonID,
+			&i.RegistrationFee,
+			&i.SiblingPlayerID,
+			&i.FeeAssessedOn,
+			&i.RosterLockedAt,
 			&i.Email,
 			&i.FirstName,
 			&i.LastName,
@@ -458,59 +699,226 @@ func (q *Queries) ListPlayersWithUsers(ctx context.Context) ([]ListPlayersWithUs
 	return items, nil
 }
 
-const updatePlayer = `-- name: UpdatePlayer :exec
-UPDATE players
-SET team_id = $1, registration_fee_due = $2, is_fully_registered = $3, 
-    is_active = $4, jersey_number = $5, updated_at = NOW()
-WHERE id = $6
+const listRegistrationMismatches = `-- name: ListRegistrationMismatches :many
+SELECT p.id AS player_id, p.user_id, u.first_name, u.last_name, u.email,
+       p.registration_fee_due, p.is_fully_registered,
+       COALESCE(lb.balance, 0)::numeric AS ledger_balance,
+       COALESCE(ub.balance, 0)::numeric AS unposted_balance,
+       GREATEST(COALESCE(lb.balance, 0) + COALESCE(ub.balance, 0), 0)::numeric AS expected_fee_due
+FROM players p
+INNER JOIN users u ON u.id = p.user_id
+LEFT JOIN teams t ON t.id = p.team_id
+LEFT JOIN (
+    SELECT player_id, SUM(amount) AS balance
+    FROM ledger_entries
+    WHERE account = 'receivable'
+    GROUP BY player_id
+) lb ON lb.player_id = p.id
+LEFT JOIN (
+    SELECT player_id, SUM(CASE WHEN kind IN ('charge', 'refund') THEN amount ELSE -amount END) AS balance
+    FROM unposted_ledger_changes(NULL, $1)
+    GROUP BY player_id
+) ub ON ub.player_id = p.id
+WHERE p.season_id = $1
+  AND ($2::bigint IS NULL OR t.division_id = $2)
+  AND (p.registration_fee_due <> GREATEST(COALESCE(lb.balance, 0) + COALESCE(ub.balance, 0), 0)
+    OR p.is_fully_registered <> (GREATEST(COALESCE(lb.balance, 0) + COALESCE(ub.balance, 0), 0) = 0))
+ORDER BY u.last_name, u.first_name
 `
 
-type UpdatePlayerParams struct {
-	TeamID             pgtype.Int8    `json:"teamId"`
+type ListRegistrationMismatchesParams struct {
+	SeasonID   int64       `json:"seasonId"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
+}
+
+type ListRegistrationMismatchesRow struct {
+	PlayerID           int64          `json:"playerId"`
+	UserID             int64          `json:"userId"`
+	FirstName          string         `json:"firstName"`
+	LastName           string         `json:"lastName"`
+	Email              string         `json:"email"`
 	RegistrationFeeDue pgtype.Numeric `json:"registrationFeeDue"`
 	IsFullyRegistered  bool           `json:"isFullyRegistered"`
-	IsActive           bool           `json:"isActive"`
-	JerseyNumber       pgtype.Int4    `json:"jerseyNumber"`
-	ID                 int64          `json:"id"`
+	LedgerBalance      pgtype.Numeric `json:"ledgerBalance"`
+	UnpostedBalance    pgtype.Numeric `json:"unpostedBalance"`
+	ExpectedFeeDue     pgtype.Numeric `json:"expectedFeeDue"`
 }
 
-// UpdatePlayer
+// ListRegistrationMismatches
+//
+//	SELECT p.id AS player_id, p.user_id, u.first_name, u.last_name, u.email,
+//	       p.registration_fee_due, p.is_fully_registered,
+//	       COALESCE(lb.balance, 0)::numeric AS ledger_balance,
+//	       COALESCE(ub.balance, 0)::numeric AS unposted_balance,
+//	       GREATEST(COALESCE(lb.balance, 0) + COALESCE(ub.balance, 0), 0)::numeric AS expected_fee_due
+//	FROM players p
+//	INNER JOIN users u ON u.id = p.user_id
+//	LEFT JOIN teams t ON t.id = p.team_id
+//	LEFT JOIN (
+//	    SELECT player_id, SUM(amount) AS balance
+//	    FROM ledger_entries
+//	    WHERE account = 'receivable'
+//	    GROUP BY player_id
+//	) lb ON lb.player_id = p.id
+//	LEFT JOIN (
+//	    SELECT player_id, SUM(CASE WHEN kind IN ('charge', 'refund') THEN amount ELSE -amount END) AS balance
+//	    FROM unposted_ledger_changes(NULL, $1)
+//	    GROUP BY player_id
+//	) ub ON ub.player_id = p.id
+//	WHERE p.season_id = $1
+//	  AND ($2::bigint IS NULL OR t.division_id = $2)
+//	  AND (p.registration_fee_due <> GREATEST(COALESCE(lb.balance, 0) + COALESCE(ub.balance, 0), 0)
+//	    OR p.is_fully_registered <> (GREATEST(COALESCE(lb.balance, 0) + COALESCE(ub.balance, 0), 0) = 0))
+//	ORDER BY u.last_name, u.first_name
+func (q *Queries) ListRegistrationMismatches(ctx context.Context, arg ListRegistrationMismatchesParams) ([]ListRegistrationMismatchesRow, error) {
+	rows, err := q.db.Query(ctx, listRegistrationMismatches, arg.SeasonID, arg.DivisionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRegistrationMismatchesRow{}
+	for rows.Next() {
+		var i ListRegistrationMismatchesRow
+		if err := rows.Scan(
+			&i.PlayerID,
+			&i.UserID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Email,
+			&i.RegistrationFeeDue,
+			&i.IsFullyRegistered,
+			&i.LedgerBalance,
+			&i.UnpostedBalance,
+			&i.ExpectedFeeDue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockPlayers = `-- name: LockPlayers :exec
+SELECT id FROM players
+WHERE ($1::bigint IS NULL OR id = $1)
+  AND ($2::bigint IS NULL OR season_id = $2)
+ORDER BY id
+FOR NO KEY UPDATE
+`
+
+type LockPlayersParams struct {
+	PlayerID pgtype.Int8 `json:"playerId"`
+	SeasonID pgtype.Int8 `json:"seasonId"`
+}
+
+// LockPlayers
+//
+//	SELECT id FROM players
+//	WHERE ($1::bigint IS NULL OR id = $1)
+//	  AND ($2::bigint IS NULL OR season_id = $2)
+//	ORDER BY id
+//	FOR NO KEY UPDATE
+func (q *Queries) LockPlayers(ctx context.Context, arg LockPlayersParams) error {
+	_, err := q.db.Exec(ctx, lockPlayers, arg.PlayerID, arg.SeasonID)
+	return err
+}
+
+const reconcilePlayerRegistrations = `-- name: ReconcilePlayerRegistrations :execrows
+WITH expected AS (
+    SELECT p.id, GREATEST(COALESCE(SUM(le.amount), 0), 0) AS fee_due
+    FROM players p
+    LEFT JOIN ledger_entries le ON le.account = 'receivable' AND le.player_id = p.id
+    WHERE ($1::bigint IS NULL OR p.id = $1)
+      AND ($2::bigint IS NULL OR p.season_id = $2)
+    GROUP BY p.id
+)
+UPDATE players p
+SET registration_fee_due = e.fee_due,
+    is_fully_registered = e.fee_due = 0,
+    roster_locked_at = CASE WHEN e.fee_due = 0 THEN NULL ELSE p.roster_locked_at END,
+    updated_at = NOW()
+FROM expected e
+WHERE p.id = e.id
+  AND (p.registration_fee_due <> e.fee_due OR p.is_fully_registered <> (e.fee_due = 0)
+       OR (e.fee_due = 0 AND p.roster_locked_at IS NOT NULL))
+`
+
+type ReconcilePlayerRegistrationsParams struct {
+	PlayerID pgtype.Int8 `json:"playerId"`
+	SeasonID pgtype.Int8 `json:"seasonId"`
+}
+
+// ReconcilePlayerRegistrations
+//
+//	WITH expected AS (
+//	    SELECT p.id, GREATEST(COALESCE(SUM(le.amount), 0), 0) AS fee_due
+//	    FROM players p
+//	    LEFT JOIN ledger_entries le ON le.account = 'receivable' AND le.player_id = p.id
+//	    WHERE ($1::bigint IS NULL OR p.id = $1)
+//	      AND ($2::bigint IS NULL OR p.season_id = $2)
+//	    GROUP BY p.id
+//	)
+//	UPDATE players p
+//	SET registration_fee_due = e.fee_due,
+//	    is_fully_registered = e.fee_due = 0,
+//	    roster_locked_at = CASE WHEN e.fee_due = 0 THEN NULL ELSE p.roster_locked_at END,
+//	    updated_at = NOW()
+//	FROM expected e
+//	WHERE p.id = e.id
+//	  AND (p.registration_fee_due <> e.fee_due OR p.is_fully_registered <> (e.fee_due = 0)
+//	       OR (e.fee_due = 0 AND p.roster_locked_at IS NOT NULL))
+func (q *Queries) ReconcilePlayerRegistrations(ctx context.Context, arg ReconcilePlayerRegistrationsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, reconcilePlayerRegistrations, arg.PlayerID, arg.SeasonID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const resetPlayerFeeAssessedOn = `-- name: ResetPlayerFeeAssessedOn :exec
+UPDATE players
+SET fee_assessed_on = CURRENT_DATE, updated_at = NOW()
+WHERE id = $1
+`
+
+// ResetPlayerFeeAssessedOn
 //
 //	UPDATE players
-//	SET team_id = $1, registration_fee_due = $2, is_fully_registered = $3,
-//	    is_active = $4, jersey_number = $5, updated_at = NOW()
-//	WHERE id = $6
-func (q *Queries) UpdatePlayer(ctx context.Context, arg UpdatePlayerParams) error {
-	_, err := q.db.Exec(ctx, updatePlayer,
-		arg.TeamID,
-		arg.RegistrationFeeDue,
-		arg.IsFullyRegistered,
-		arg.IsActive,
-		arg.JerseyNumber,
-		arg.ID,
-	)
+//	SET fee_assessed_on = CURRENT_DATE, updated_at = NOW()
+//	WHERE id = $1
+func (q *Queries) ResetPlayerFeeAssessedOn(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, resetPlayerFeeAssessedOn, id)
 	return err
 }
 
-const updatePlayerRegistrationStatus = `-- name: UpdatePlayerRegistrationStatus :exec
+const updatePlayer = `-- name: UpdatePlayer :exec
 UPDATE players
-SET registration_fee_due = $1, is_fully_registered = $2, updated_at = NOW()
-WHERE id = $3
+SET team_id = $1, is_active = $2, jersey_number = $3, updated_at = NOW()
+WHERE id = $4
 `
 
-type UpdatePlayerRegistrationStatusParams struct {
-	RegistrationFeeDue pgtype.Numeric `json:"registrationFeeDue"`
-	IsFullyRegistered  bool           `json:"isFullyRegistered"`
-	ID                 int64          `json:"id"`
+type UpdatePlayerParams struct {
+	TeamID       pgtype.Int8 `json:"teamId"`
+	IsActive     bool        `json:"isActive"`
+	JerseyNumber pgtype.Int4 `json:"jerseyNumber"`
+	ID           int64       `json:"id"`
 }
 
-// UpdatePlayerRegistrationStatus
+// UpdatePlayer
 //
 //	UPDATE players
-//	SET registration_fee_due = $1, is_fully_registered = $2, updated_at = NOW()
-//	WHERE id = $3
-func (q *Queries) UpdatePlayerRegistrationStatus(ctx context.Context, arg UpdatePlayerRegistrationStatusParams) error {
-	_, err := q.db.Exec(ctx, updatePlayerRegistrationStatus, arg.RegistrationFeeDue, arg.IsFullyRegistered, arg.ID)
+//	SET team_id = $1, is_active = $2, jersey_number = $3, updated_at = NOW()
+//	WHERE id = $4
+func (q *Queries) UpdatePlayer(ctx context.Context, arg UpdatePlayerParams) error {
+	_, err := q.db.Exec(ctx, updatePlayer,
+		arg.TeamID,
+		arg.IsActive,
+		arg.JerseyNumber,
+		arg.ID,
+	)
 	return err
 }
 
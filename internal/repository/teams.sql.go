@@ -12,34 +12,24 @@ import (
 )
 
 const createTeam = `-- name: CreateTeam :one
-INSERT INTO teams (name, wins, losses, draws, points_for, points_against, created_at, updated_at)
-values ($1, $2, $3, $4, $5, $6, NOW(), NOW())
-RETURNING id, name, wins, losses, draws, points_for, points_against, created_at, updated_at
+INSERT INTO teams (name, season_id, division_id, created_at, updated_at)
+values ($1, COALESCE($2::bigint, current_season_id()), $3, NOW(), NOW())
+RETURNING id, name, wins, losses, draws, points_for, points_against, created_at, updated_at, season_id, division_id
 `
 
 type CreateTeamParams struct {
-	Name          string `json:"name"`
-	Wins          int32  `json:"wins"`
-	Losses        int32  `json:"losses"`
-	Draws         int32  `json:"draws"`
-	PointsFor     int32  `json:"pointsFor"`
-	PointsAgainst int32  `json:"pointsAgainst"`
+	Name       string      `json:"name"`
+	SeasonID   pgtype.Int8 `json:"seasonId"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
 }
 
 // CreateTeam
 //
-//	INSERT INTO teams (name, wins, losses, draws, points_for, points_against, created_at, updated_at)
-//	values ($1, $2, $3, $4, $5, $6, NOW(), NOW())
-//	RETURNING id, name, wins, losses, draws, points_for, points_against, created_at, updated_at
+//	INSERT INTO teams (name, season_id, division_id, created_at, updated_at)
+//	values ($1, COALESCE($2::bigint, current_season_id()), $3, NOW(), NOW())
+//	RETURNING id, name, wins, losses, draws, points_for, points_against, created_at, updated_at, season_id, division_id
 func (q *Queries) CreateTeam(ctx context.Context, arg CreateTeamParams) (Team, error) {
-	row := q.db.QueryRow(ctx, createTeam,
-		arg.Name,
-		arg.Wins,
-		arg.Losses,
-		arg.Draws,
-		arg.PointsFor,
-		arg.PointsAgainst,
-	)
+	row := q.db.QueryRow(ctx, createTeam, arg.Name, arg.SeasonID, arg.DivisionID)
 	var i Team
 	err := row.Scan(
 		&i.ID,
@@ -51,6 +41,8 @@ func (q *Queries) CreateTeam(ctx context.Context, arg CreateTeamParams) (Team, e
 		&i.PointsAgainst,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SeasonID,
+		&i.DivisionID,
 	)
 	return i, err
 }
@@ -70,12 +62,12 @@ func (q *Queries) DeleteTeam(ctx context.Context, id int64) error {
 }
 
 const getTeamById = `-- name: GetTeamById :one
-SELECT id, name, wins, losses, draws, points_for, points_against, created_at, updated_at FROM teams where id = $1
+SELECT id, name, wins, losses, draws, points_for, points_against, created_at, updated_at, season_id, division_id FROM teams where id = $1
 `
 
 // GetTeamById
 //
-//	SELECT id, name, wins, losses, draws, points_for, points_against, created_at, updated_at FROM teams where id = $1
+//	SELECT id, name, wins, losses, draws, points_for, points_against, created_at, updated_at, season_id, division_id FROM teams where id = $1
 func (q *Queries) GetTeamById(ctx context.Context, id int64) (Team, error) {
 	row := q.db.QueryRow(ctx, getTeamById, id)
 	var i Team
@@ -89,6 +81,8 @@ func (q *Queries) GetTeamById(ctx context.Context, id int64) (Team, error) {
 		&i.PointsAgainst,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SeasonID,
+		&i.DivisionID,
 	)
 	return i, err
 }
@@ -98,9 +92,16 @@ SELECT id, name, wins, losses, draws, points_for, points_against, created_at, up
        (wins * 3 + draws) as points,
        (points_for - points_against) as point_differential
 FROM teams
+WHERE season_id = $1
+  AND ($2::bigint IS NULL OR division_id = $2)
 ORDER BY points DESC, point_differential DESC, name ASC
 `
 
+type GetTeamStandingsParams struct {
+	SeasonID   int64       `json:"seasonId"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
+}
+
 type GetTeamStandingsRow struct {
 	ID                int64            `json:"id"`
 	Name              string           `json:"name"`
@@ -121,9 +122,11 @@ type GetTeamStandingsRow struct {
 //	       (wins * 3 + draws) as points,
 //	       (points_for - points_against) as point_differential
 //	FROM teams
+//	WHERE season_id = $1
+//	  AND ($2::bigint IS NULL OR division_id = $2)
 //	ORDER BY points DESC, point_differential DESC, name ASC
-func (q *Queries) GetTeamStandings(ctx context.Context) ([]GetTeamStandingsRow, error) {
-	rows, err := q.db.Query(ctx, getTeamStandings)
+func (q *Queries) GetTeamStandings(ctx context.Context, arg GetTeamStandingsParams) ([]GetTeamStandingsRow, error) {
+	rows, err := q.db.Query(ctx, getTeamStandings, arg.SeasonID, arg.DivisionID)
 	if err != nil {
 		return nil, err
 	}
@@ -155,33 +158,75 @@ func (q *Queries) GetTeamStandings(ctx context.Context) ([]GetTeamStandingsRow,
 }
 
 const getTeamStats = `-- name: GetTeamStats :one
-SELECT t.id, t.name, t.wins, t.losses, t.draws, t.points_for, t.points_against, t.created_at, t.updated_at,
-       COUNT(p.id) as player_count
+SELECT t.id, t.name, t.wins, t.losses, t.draws, t.points_for, t.points_against, t.created_at, t.updated_at, t.season_id, t.division_id,
+       (SELECT COUNT(*) FROM players p WHERE p.team_id = t.id AND p.is_active = true)::bigint as player_count,
+       COALESCE(SUM(gd.rebounds), 0)::bigint AS rebounds,
+       COALESCE(SUM(gd.assists), 0)::bigint AS assists,
+       COALESCE(SUM(gd.steals), 0)::bigint AS steals,
+       COALESCE(SUM(gd.blocks), 0)::bigint AS blocks,
+       COALESCE(SUM(gd.turnovers), 0)::bigint AS turnovers,
+       COALESCE(SUM(gd.fouls), 0)::bigint AS fouls,
+       COALESCE(SUM(gd.field_goals_made), 0)::bigint AS field_goals_made,
+       COALESCE(SUM(gd.field_goals_attempted), 0)::bigint AS field_goals_attempted,
+       COALESCE(SUM(gd.three_pointers_made), 0)::bigint AS three_pointers_made,
+       COALESCE(SUM(gd.three_pointers_attempted), 0)::bigint AS three_pointers_attempted,
+       COALESCE(SUM(gd.free_throws_made), 0)::bigint AS free_throws_made,
+       COALESCE(SUM(gd.free_throws_attempted), 0)::bigint AS free_throws_attempted
 FROM teams t
-LEFT JOIN players p ON p.team_id = t.id AND p.is_active = true
+LEFT JOIN players tp ON tp.team_id = t.id
+LEFT JOIN game_details gd ON gd.player_id = tp.id
+  AND gd.game_id IN (SELECT id FROM games WHERE status = 'completed' AND t.id IN (home_team_id, away_team_id))
 WHERE t.id = $1
 GROUP BY t.id
 `
 
 type GetTeamStatsRow struct {
-	ID            int64            `json:"id"`
-	Name          string           `json:"name"`
-	Wins          int32            `json:"wins"`
-	Losses        int32            `json:"losses"`
-	Draws         int32            `json:"draws"`
-	PointsFor     int32            `json:"pointsFor"`
-	PointsAgainst int32            `json:"pointsAgainst"`
-	CreatedAt     pgtype.Timestamp `json:"createdAt"`
-	UpdatedAt     pgtype.Timestamp `json:"updatedAt"`
-	PlayerCount   int64            `json:"playerCount"`
+	ID                     int64            `json:"id"`
+	Name                   string           `json:"name"`
+	Wins                   int32            `json:"wins"`
+	Losses                 int32            `json:"losses"`
+	Draws                  int32            `json:"draws"`
+	PointsFor              int32            `json:"pointsFor"`
+	PointsAgainst          int32            `json:"pointsAgainst"`
+	CreatedAt              pgtype.Timestamp `json:"createdAt"`
+	UpdatedAt              pgtype.Timestamp `json:"updatedAt"`
+	SeasonID               int64            `json:"seasonId"`
+	DivisionID             pgtype.Int8      `json:"divisionId"`
+	PlayerCount            int64            `json:"playerCount"`
+	Rebounds               int64            `json:"rebounds"`
+	Assists                int64            `json:"assists"`
+	Steals                 int64            `json:"steals"`
+	Blocks                 int64            `json:"blocks"`
+	Turnovers              int64            `json:"turnovers"`
+	Fouls                  int64            `json:"fouls"`
+	FieldGoalsMade         int64            `json:"fieldGoalsMade"`
+	FieldGoalsAttempted    int64            `json:"fieldGoalsAttempted"`
+	ThreePointersMade      int64            `json:"threePointersMade"`
+	ThreePointersAttempted int64            `json:"threePointersAttempted"`
+	FreeThrowsMade         int64            `json:"freeThrowsMade"`
+	FreeThrowsAttempted    int64            `json:"freeThrowsAttempted"`
 }
 
 // GetTeamStats
 //
-//	SELECT t.id, t.name, t.wins, t.losses, t.draws, t.points_for, t.points_against, t.created_at, t.updated_at,
-//	       COUNT(p.id) as player_count
+//	SELECT t.id, t.name, t.wins, t.losses, t.draws, t.points_for, t.points_against, t.created_at, t.updated_at, t.season_id, t.division_id,
+//	       (SELECT COUNT(*) FROM players p WHERE p.team_id = t.id AND p.is_active = true)::bigint as player_count,
+//	       COALESCE(SUM(gd.rebounds), 0)::bigint AS rebounds,
+//	       COALESCE(SUM(gd.assists), 0)::bigint AS assists,
+//	       COALESCE(SUM(gd.steals), 0)::bigint AS steals,
+//	       COALESCE(SUM(gd.blocks), 0)::bigint AS blocks,
+//	       COALESCE(SUM(gd.turnovers), 0)::bigint AS turnovers,
+//	       COALESCE(SUM(gd.fouls), 0)::bigint AS fouls,
+//	       COALESCE(SUM(gd.field_goals_made), 0)::bigint AS field_goals_made,
+//	       COALESCE(SUM(gd.field_goals_attempted), 0)::bigint AS field_goals_attempted,
+//	       COALESCE(SUM(gd.three_pointers_made), 0)::bigint AS three_pointers_made,
+//	       COALESCE(SUM(gd.three_pointers_attempted), 0)::bigint AS three_pointers_attempted,
+//	       COALESCE(SUM(gd.free_throws_made), 0)::bigint AS free_throws_made,
+//	       COALESCE(SUM(gd.free_throws_attempted), 0)::bigint AS free_throws_attempted
 //	FROM teams t
-//	LEFT JOIN players p ON p.team_id = t.id AND p.is_active = true
+//	LEFT JOIN players tp ON tp.team_id = t.id
+//	LEFT JOIN game_details gd ON gd.player_id = tp.id
+//	  AND gd.game_id IN (SELECT id FROM games WHERE status = 'completed' AND t.id IN (home_team_id, away_team_id))
 //	WHERE t.id = $1
 //	GROUP BY t.id
 func (q *Queries) GetTeamStats(ctx context.Context, id int64) (GetTeamStatsRow, error) {
@@ -197,7 +242,21 @@ func (q *Queries) GetTeamStats(ctx context.Context, id int64) (GetTeamStatsRow,
 		&i.PointsAgainst,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SeasonID,
+		&i.DivisionID,
 		&i.PlayerCount,
+		&i.Rebounds,
+		&i.Assists,
+		&i.Steals,
+		&i.Blocks,
+		&i.Turnovers,
+		&i.Fouls,
+		&i.FieldGoalsMade,
+		&i.FieldGoalsAttempted,
+		&i.ThreePointersMade,
+		&i.ThreePointersAttempted,
+		&i.FreeThrowsMade,
+		&i.FreeThrowsAttempted,
 	)
 	return i, err
 }
@@ -210,6 +269,18 @@ WHERE p.team_id = $1
 ORDER BY p.id
 `
 
+type GetTeamWithPlayersRow struct {
+	ID                 int64            `json:"id"`
+	UserID             int64            `json:"userId"`
+	TeamID             pgtype.Int8      `json:"teamId"`
+	RegistrationFeeDue pgtype.Numeric   `json:"registrationFeeDue"`
+	IsFullyRegistered  bool             `json:"isFullyRegistered"`
+	IsActive           bool             `json:"isActive"`
+	JerseyNumber       pgtype.Int4      `json:"jerseyNumber"`
+	CreatedAt          pgtype.Timestamp `json:"createdAt"`
+	UpdatedAt          pgtype.Timestamp `json:"updatedAt"`
+}
+
 // GetTeamWithPlayers
 //
 //	SELECT p.id, p.user_id, p.team_id, p.registration_fee_due, p.is_fully_registered,
@@ -217,15 +288,15 @@ ORDER BY p.id
 //	FROM players p
 //	WHERE p.team_id = $1
 //	ORDER BY p.id
-func (q *Queries) GetTeamWithPlayers(ctx context.Context, teamID pgtype.Int8) ([]Player, error) {
+func (q *Queries) GetTeamWithPlayers(ctx context.Context, teamID pgtype.Int8) ([]GetTeamWithPlayersRow, error) {
 	rows, err := q.db.Query(ctx, getTeamWithPlayers, teamID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Player{}
+	items := []GetTeamWithPlayersRow{}
 	for rows.Next() {
-		var i Player
+		var i GetTeamWithPlayersRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
@@ -248,16 +319,25 @@ func (q *Queries) GetTeamWithPlayers(ctx context.Context, teamID pgtype.Int8) ([
 }
 
 const listTeams = `-- name: ListTeams :many
-SELECT id, name, wins, losses, draws, points_for, points_against, created_at, updated_at FROM teams
+SELECT id, name, wins, losses, draws, points_for, points_against, created_at, updated_at, season_id, division_id FROM teams
+WHERE season_id = $1
+  AND ($2::bigint IS NULL OR division_id = $2)
 ORDER BY name
 `
 
+type ListTeamsParams struct {
+	SeasonID   int64       `json:"seasonId"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
+}
+
 // ListTeams
 //
-//	SELECT id, name, wins, losses, draws, points_for, points_against, created_at, updated_at FROM teams
+//	SELECT id, name, wins, losses, draws, points_for, points_against, created_at, updated_at, season_id, division_id FROM teams
+//	WHERE season_id = $1
+//	  AND ($2::bigint IS NULL OR division_id = $2)
 //	ORDER BY name
-func (q *Queries) ListTeams(ctx context.Context) ([]Team, error) {
-	rows, err := q.db.Query(ctx, listTeams)
+func (q *Queries) ListTeams(ctx context.Context, arg ListTeamsParams) ([]Team, error) {
+	rows, err := q.db.Query(ctx, listTeams, arg.SeasonID, arg.DivisionID)
 	if err != nil {
 		return nil, err
 	}
@@ -275,6 +355,8 @@ func (q *Queries) ListTeams(ctx context.Context) ([]Team, error) {
 			&i.PointsAgainst,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SeasonID,
+			&i.DivisionID,
 		); err != nil {
 			return nil, err
 		}
@@ -292,9 +374,16 @@ u.first_name, u.last_name
 FROM teams t
 LEFT JOIN players p on t.id = p.team_id
 LEFT JOIN users u on u.id = p.user_id
+WHERE t.season_id = $1
+  AND ($2::bigint IS NULL OR t.division_id = $2)
 ORDER BY t.id
 `
 
+type ListTeamsWithPlayersParams struct {
+	SeasonID   int64       `json:"seasonId"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
+}
+
 type ListTeamsWithPlayersRow struct {
 	ID            int64            `json:"id"`
 	Name          string           `json:"name"`
@@ -317,9 +406,11 @@ type ListTeamsWithPlayersRow struct {
 //	FROM teams t
 //	LEFT JOIN players p on t.id = p.team_id
 //	LEFT JOIN users u on u.id = p.user_id
+//	WHERE t.season_id = $1
+//	  AND ($2::bigint IS NULL OR t.division_id = $2)
 //	ORDER BY t.id
-func (q *Queries) ListTeamsWithPlayers(ctx context.Context) ([]ListTeamsWithPlayersRow, error) {
-	rows, err := q.db.Query(ctx, listTeamsWithPlayers)
+func (q *Queries) ListTeamsWithPlayers(ctx context.Context, arg ListTeamsWithPlayersParams) ([]ListTeamsWithPlayersRow, error) {
+	rows, err := q.db.Query(ctx, listTeamsWithPlayers, arg.SeasonID, arg.DivisionID)
 	if err != nil {
 		return nil, err
 	}
@@ -351,36 +442,105 @@ func (q *Queries) ListTeamsWithPlayers(ctx context.Context) ([]ListTeamsWithPlay
 	return items, nil
 }
 
-const updateTeam = `-- name: UpdateTeam :exec
+const lockTeamStandings = `-- name: LockTeamStandings :exec
+SELECT pg_advisory_xact_lock(hashtext('team_standings'))
+`
+
+// LockTeamStandings
+//
+//	SELECT pg_advisory_xact_lock(hashtext('team_standings'))
+func (q *Queries) LockTeamStandings(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockTeamStandings)
+	return err
+}
+
+const recomputeTeamStandings = `-- name: RecomputeTeamStandings :execrows
+UPDATE teams t
+SET wins = s.wins, losses = s.losses, draws = s.draws,
+    points_for = s.points_for, points_against = s.points_against,
+    updated_at = NOW()
+FROM (
+    SELECT tm.id,
+           COUNT(r.team_id) FILTER (WHERE r.scored > r.allowed)::int AS wins,
+           COUNT(r.team_id) FILTER (WHERE r.scored < r.allowed)::int AS losses,
+           COUNT(r.team_id) FILTER (WHERE r.scored = r.allowed)::int AS draws,
+           COALESCE(SUM(r.scored), 0)::int AS points_for,
+           COALESCE(SUM(r.allowed), 0)::int AS points_against
+    FROM teams tm
+    LEFT JOIN (
+        SELECT home_team_id AS team_id, home_score AS scored, away_score AS allowed
+        FROM games g WHERE status = 'completed'
+          AND NOT EXISTS (SELECT 1 FROM bracket_matches bm WHERE bm.game_id = g.id)
+        UNION ALL
+        SELECT away_team_id, away_score, home_score
+        FROM games g WHERE status = 'completed'
+          AND NOT EXISTS (SELECT 1 FROM bracket_matches bm WHERE bm.game_id = g.id)
+    ) r ON r.team_id = tm.id
+    GROUP BY tm.id
+) s
+WHERE t.id = s.id
+  AND (t.wins, t.losses, t.draws, t.points_for, t.points_against)
+      IS DISTINCT FROM (s.wins, s.losses, s.draws, s.points_for, s.points_against)
+`
+
+// RecomputeTeamStandings
+//
+//	UPDATE teams t
+//	SET wins = s.wins, losses = s.losses, draws = s.draws,
+//	    points_for = s.points_for, points_against = s.points_against,
+//	    updated_at = NOW()
+//	FROM (
+//	    SELECT tm.id,
+//	           COUNT(r.team_id) FILTER (WHERE r.scored > r.allowed)::int AS wins,
+//	           COUNT(r.team_id) FILTER (WHERE r.scored < r.allowed)::int AS losses,
+//	           COUNT(r.team_id) FILTER (WHERE r.scored = r.allowed)::int AS draws,
+//	           COALESCE(SUM(r.scored), 0)::int AS points_for,
+//	           COALESCE(SUM(r.allowed), 0)::int AS points_against
+//	    FROM teams tm
+//	    LEFT JOIN (
+//	        SELECT home_team_id AS team_id, home_score AS scored, away_score AS allowed
+//	        FROM games g WHERE status = 'completed'
+//	          AND NOT EXISTS (SELECT 1 FROM bracket_matches bm WHERE bm.game_id = g.id)
+//	        UNION ALL
+//	        SELECT away_team_id, away_score, home_score
+//	        FROM games g WHERE status = 'completed'
+//	          AND NOT EXISTS (SELECT 1 FROM bracket_matches bm WHERE bm.game_id = g.id)
+//	    ) r ON r.team_id = tm.id
+//	    GROUP BY tm.id
+//	) s
+//	WHERE t.id = s.id
+//	  AND (t.wins, t.losses, t.draws, t.points_for, t.points_against)
+//	      IS DISTINCT FROM (s.wins, s.losses, s.draws, s.points_for, s.points_against)
+func (q *Queries) RecomputeTeamStandings(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, recomputeTeamStandings)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateTeam = `-- name: UpdateTeam :one
 UPDATE teams
-SET name = $1, wins = $2, losses = $3, draws = $4, points_for = $5, points_against = $6, updated_at = NOW()
-WHERE id = $7
+SET name = $1, division_id = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING season_id
 `
 
 type UpdateTeamParams struct {
-	Name          string `json:"name"`
-	Wins          int32  `json:"wins"`
-	Losses        int32  `json:"losses"`
-	Draws         int32  `json:"draws"`
-	PointsFor     int32  `json:"pointsFor"`
-	PointsAgainst int32  `json:"pointsAgainst"`
-	ID            int64  `json:"id"`
+	Name       string      `json:"name"`
+	DivisionID pgtype.Int8 `json:"divisionId"`
+	ID         int64       `json:"id"`
 }
 
 // UpdateTeam
 //
 //	UPDATE teams
-//	SET name = $1, wins = $2, losses = $3, draws = $4, points_for = $5, points_against = $6, updated_at = NOW()
-//	WHERE id = $7
-func (q *Queries) UpdateTeam(ctx context.Context, arg UpdateTeamParams) error {
-	_, err := q.db.Exec(ctx, updateTeam,
-		arg.Name,
-		arg.Wins,
-		arg.Losses,
-		arg.Draws,
-		arg.PointsFor,
-		arg.PointsAgainst,
-		arg.ID,
-	)
-	return err
+//	SET name = $1, division_id = $2, updated_at = NOW()
+//	WHERE id = $3
+//	RETURNING season_id
+func (q *Queries) UpdateTeam(ctx context.Context, arg UpdateTeamParams) (int64, error) {
+	row := q.db.QueryRow(ctx, updateTeam, arg.Name, arg.DivisionID, arg.ID)
+	var seasonID int64
+	err := row.Scan(&seasonID)
+	return seasonID, err
 }
@@ -98,13 +98,25 @@ SELECT id, email, phone_number, password_hash, first_name, last_name, role, crea
 FROM users WHERE email = $1
 `
 
+type GetUserByEmailWithPasswordRow struct {
+	ID           int64            `json:"id"`
+	Email        string           `json:"email"`
+	PhoneNumber  string           `json:"phoneNumber"`
+	PasswordHash string           `json:"passwordHash"`
+	FirstName    string           `json:"firstName"`
+	LastName     string           `json:"lastName"`
+	Role         string           `json:"role"`
+	CreatedAt    pgtype.Timestamp `json:"createdAt"`
+	UpdatedAt    pgtype.Timestamp `json:"updatedAt"`
+}
+
 // GetUserByEmailWithPassword
 //
 //	SELECT id, email, phone_number, password_hash, first_name, last_name, role, created_at, updated_at
 //	FROM users WHERE email = $1
-func (q *Queries) GetUserByEmailWithPassword(ctx context.Context, email string) (User, error) {
+func (q *Queries) GetUserByEmailWithPassword(ctx context.Context, email string) (GetUserByEmailWithPasswordRow, error) {
 	row := q.db.QueryRow(ctx, getUserByEmailWithPassword, email)
-	var i User
+	var i GetUserByEmailWithPasswordRow
 	err := row.Scan(
 		&i.ID,
 		&i.Email,
@@ -120,23 +132,24 @@ func (q *Queries) GetUserByEmailWithPassword(ctx context.Context, email string)
 }
 
 const getUserById = `-- name: GetUserById :one
-SELECT id, email, phone_number, first_name, last_name, role, created_at
+SELECT id, email, phone_number, first_name, last_name, role, payment_reminders_opt_out, created_at
 FROM users WHERE id = $1
 `
 
 type GetUserByIdRow struct {
-	ID          int64            `json:"id"`
-	Email       string           `json:"email"`
-	PhoneNumber string           `json:"phoneNumber"`
-	FirstName   string           `json:"firstName"`
-	LastName    string           `json:"lastName"`
-	Role        string           `json:"role"`
-	CreatedAt   pgtype.Timestamp `json:"createdAt"`
+	ID                     int64            `json:"id"`
+	Email                  string           `json:"email"`
+	PhoneNumber            string           `json:"phoneNumber"`
+	FirstName              string           `json:"firstName"`
+	LastName               string           `json:"lastName"`
+	Role                   string           `json:"role"`
+	PaymentRemindersOptOut bool             `json:"paymentRemindersOptOut"`
+	CreatedAt              pgtype.Timestamp `json:"createdAt"`
 }
 
 // GetUserById
 //
-//	SELECT id, email, phone_number, first_name, last_name, role, created_at
+//	SELECT id, email, phone_number, first_name, last_name, role, payment_reminders_opt_out, created_at
 //	FROM users WHERE id = $1
 func (q *Queries) GetUserById(ctx context.Context, id int64) (GetUserByIdRow, error) {
 	row := q.db.QueryRow(ctx, getUserById, id)
@@ -148,6 +161,7 @@ func (q *Queries) GetUserById(ctx context.Context, id int64) (GetUserByIdRow, er
 		&i.FirstName,
 		&i.LastName,
 		&i.Role,
+		&i.PaymentRemindersOptOut,
 		&i.CreatedAt,
 	)
 	return i, err
@@ -0,0 +1,192 @@
+// Package schedule generates balanced round-robin season schedules that can
+// be previewed before they are written to the games table.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// bye is the placeholder opponent used to even out an odd number of teams.
+// A team drawn against it sits out that round.
+const bye int64 = -1
+
+// Slot is a weekly time slot games can be played in, e.g. Mondays at 18:30.
+type Slot struct {
+	Weekday time.Weekday
+	Hour    int
+	Minute  int
+}
+
+// Options describes the season to generate.
+type Options struct {
+	TeamIDs   []int64
+	StartDate time.Time
+	Slots     []Slot
+	// Cycles is how many times every team plays every other team:
+	// 1 for a single round robin, 2 for a double round robin, and so on.
+	Cycles int
+}
+
+// Matchup is a single generated game.
+type Matchup struct {
+	Round      int       `json:"round"`
+	HomeTeamID int64     `json:"homeTeamId"`
+	AwayTeamID int64     `json:"awayTeamId"`
+	GameTime   time.Time `json:"gameTime"`
+}
+
+// Bye records a team that sits out a round because of an odd team count.
+type Bye struct {
+	Round  int   `json:"round"`
+	TeamID int64 `json:"teamId"`
+}
+
+// Schedule is the generated season.
+type Schedule struct {
+	Games []Matchup `json:"games"`
+	Byes  []Bye     `json:"byes"`
+}
+
+var (
+	ErrTooFewTeams   = errors.New("at least two teams are required")
+	ErrDuplicateTeam = errors.New("team ids must be unique")
+	ErrNoSlots       = errors.New("at least one time slot is required")
+	ErrInvalidCycles = errors.New("cycles must be at least 1")
+)
+
+// RoundRobin builds a round-robin schedule using the circle method. Each round
+// is played in its own week starting from StartDate, and the games of a round
+// are assigned to the weekly slots in chronological order.
+//
+// Home and away games are split as evenly as possible: in a single round robin
+// no team has more than one home game more than any other, and every second
+// cycle mirrors the one before it so double round robins are exactly even.
+func RoundRobin(opts Options) (*Schedule, error) {
+	if len(opts.TeamIDs) < 2 {
+		return nil, ErrTooFewTeams
+	}
+	if len(opts.Slots) == 0 {
+		return nil, ErrNoSlots
+	}
+	if opts.Cycles < 1 {
+		return nil, ErrInvalidCycles
+	}
+
+	seen := make(map[int64]bool, len(opts.TeamIDs))
+	for _, id := range opts.TeamIDs {
+		if seen[id] {
+			return nil, ErrDuplicateTeam
+		}
+		seen[id] = true
+	}
+
+	teams := append([]int64(nil), opts.TeamIDs...)
+	if len(teams)%2 == 1 {
+		// Fixing the bye in place keeps every real team rotating, which is
+		// what balances their home and away games.
+		teams = append([]int64{bye}, teams...)
+	}
+
+	gamesPerRound := len(opts.TeamIDs) / 2
+	if gamesPerRound > len(opts.Slots) {
+		return nil, fmt.Errorf("each round has %d games but only %d weekly time slots were given", gamesPerRound, len(opts.Slots))
+	}
+
+	slots := sortSlots(opts.Slots, opts.StartDate.Weekday())
+	rounds := singleRoundRobin(teams)
+
+	schedule := &Schedule{
+		Games: []Matchup{},
+		Byes:  []Bye{},
+	}
+	roundNumber := 0
+	for cycle := 0; cycle < opts.Cycles; cycle++ {
+		for _, pairings := range rounds {
+			week := opts.StartDate.AddDate(0, 0, 7*roundNumber)
+			roundNumber++
+
+			slot := 0
+			for _, p := range pairings {
+				home, away := p[0], p[1]
+				if cycle%2 == 1 {
+					home, away = away, home
+				}
+
+				if home == bye || away == bye {
+					team := home
+					if team == bye {
+						team = away
+					}
+					schedule.Byes = append(schedule.Byes, Bye{Round: roundNumber, TeamID: team})
+					continue
+				}
+
+				schedule.Games = append(schedule.Games, Matchup{
+					Round:      roundNumber,
+					HomeTeamID: home,
+					AwayTeamID: away,
+					GameTime:   slots[slot].in(week),
+				})
+				slot++
+			}
+		}
+	}
+
+	return schedule, nil
+}
+
+// singleRoundRobin returns the pairings for one full cycle of an even number
+// of teams as [home, away] pairs. The first team stays fixed while the rest
+// rotate around it.
+func singleRoundRobin(teams []int64) [][][2]int64 {
+	n := len(teams)
+	rotation := append([]int64(nil), teams[1:]...)
+	rounds := make([][][2]int64, 0, n-1)
+
+	for r := 0; r < n-1; r++ {
+		current := append([]int64{teams[0]}, rotation...)
+		pairings := make([][2]int64, 0, n/2)
+
+		for i := 0; i < n/2; i++ {
+			a, b := current[i], current[n-1-i]
+			// Alternate which side hosts so every team swaps between home and
+			// away from one round to the next.
+			if (i == 0 && r%2 == 1) || (i > 0 && i%2 == 1) {
+				a, b = b, a
+			}
+			pairings = append(pairings, [2]int64{a, b})
+		}
+		rounds = append(rounds, pairings)
+
+		// Rotate clockwise: the last team moves to the front.
+		rotation = append([]int64{rotation[len(rotation)-1]}, rotation[:len(rotation)-1]...)
+	}
+
+	return rounds
+}
+
+// sortSlots orders slots chronologically within a week that begins on start.
+func sortSlots(slots []Slot, start time.Weekday) []Slot {
+	sorted := append([]Slot(nil), slots...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].offset(start) < sorted[j].offset(start)
+	})
+	return sorted
+}
+
+// offset is the number of minutes between the start of a week beginning on
+// start and this slot.
+func (s Slot) offset(start time.Weekday) int {
+	days := (int(s.Weekday) - int(start) + 7) % 7
+	return days*24*60 + s.Hour*60 + s.Minute
+}
+
+// in returns the time of this slot in the week beginning on weekStart.
+func (s Slot) in(weekStart time.Time) time.Time {
+	days := (int(s.Weekday) - int(weekStart.Weekday()) + 7) % 7
+	date := weekStart.AddDate(0, 0, days)
+	return time.Date(date.Year(), date.Month(), date.Day(), s.Hour, s.Minute, 0, 0, weekStart.Location())
+}
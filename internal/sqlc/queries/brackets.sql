@@ -0,0 +1,69 @@
+-- name: CreateBracket :one
+INSERT INTO brackets (season_id, division_id, name, created_at, updated_at)
+VALUES ($1, $2, $3, NOW(), NOW())
+RETURNING *;
+
+-- name: GetBracketById :one
+SELECT * FROM brackets WHERE id = $1;
+
+-- name: ListBrackets :many
+SELECT * FROM brackets
+WHERE season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR division_id = sqlc.narg('division_id'))
+ORDER BY created_at DESC;
+
+-- name: DeleteBracketGames :exec
+DELETE FROM games
+WHERE id IN (
+    SELECT game_id FROM bracket_matches
+    WHERE bracket_id = $1 AND game_id IS NOT NULL
+);
+
+-- name: DeleteBracket :exec
+DELETE FROM brackets
+WHERE id = $1;
+
+-- name: CreateBracketMatch :one
+INSERT INTO bracket_matches (bracket_id, round, position, home_seed, away_seed, home_team_id, away_team_id,
+                             next_match_id, next_match_slot, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+RETURNING *;
+
+-- name: GetBracketMatchById :one
+SELECT * FROM bracket_matches WHERE id = $1;
+
+-- name: GetBracketMatchByGameId :one
+SELECT * FROM bracket_matches WHERE game_id = $1;
+
+-- name: ListBracketMatches :many
+SELECT bm.id, bm.bracket_id, bm.round, bm.position, bm.home_seed, bm.away_seed,
+       bm.home_team_id, ht.name as home_team_name,
+       bm.away_team_id, at.name as away_team_name,
+       bm.game_id, g.home_score, g.away_score, g.game_time, g.status,
+       bm.winner_team_id, bm.next_match_id, bm.next_match_slot
+FROM bracket_matches bm
+LEFT JOIN teams ht ON ht.id = bm.home_team_id
+LEFT JOIN teams at ON at.id = bm.away_team_id
+LEFT JOIN games g ON g.id = bm.game_id
+WHERE bm.bracket_id = $1
+ORDER BY bm.round, bm.position;
+
+-- name: SetBracketMatchGame :execrows
+UPDATE bracket_matches
+SET game_id = $1, updated_at = NOW()
+WHERE id = $2 AND game_id IS NULL;
+
+-- name: SetBracketMatchWinner :exec
+UPDATE bracket_matches
+SET winner_team_id = $1, updated_at = NOW()
+WHERE id = $2;
+
+-- name: AdvanceToHomeSlot :execrows
+UPDATE bracket_matches
+SET home_team_id = $1, home_seed = $2, updated_at = NOW()
+WHERE id = $3 AND (game_id IS NULL OR home_team_id = $1);
+
+-- name: AdvanceToAwaySlot :execrows
+UPDATE bracket_matches
+SET away_team_id = $1, away_seed = $2, updated_at = NOW()
+WHERE id = $3 AND (game_id IS NULL OR away_team_id = $1);
@@ -0,0 +1,23 @@
+-- name: GetDivisionById :one
+SELECT * FROM divisions WHERE id = $1;
+
+-- name: ListDivisionsBySeason :many
+SELECT * FROM divisions
+WHERE season_id = $1
+ORDER BY name;
+
+-- name: CreateDivision :one
+INSERT INTO divisions (season_id, name, registration_fee, created_at, updated_at)
+VALUES ($1, $2, $3, NOW(), NOW())
+RETURNING *;
+
+-- name: UpdateDivision :one
+UPDATE divisions
+SET name = $1, registration_fee = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING season_id;
+
+-- name: DeleteDivision :one
+DELETE FROM divisions
+WHERE id = $1
+RETURNING season_id;
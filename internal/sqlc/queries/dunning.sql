@@ -0,0 +1,83 @@
+-- name: ListPlayersDueReminders :many
+SELECT p.id AS player_id, p.registration_fee_due, p.roster_locked_at, p.created_at,
+       u.email, u.first_name, s.name AS season_name,
+       (CURRENT_DATE - p.created_at::date)::int AS days_registered,
+       COALESCE((SELECT MAX(r.stage) FROM payment_reminders r WHERE r.player_id = p.id AND r.status = 'sent'), 0)::int AS last_stage
+FROM players p
+INNER JOIN users u ON u.id = p.user_id
+INNER JOIN seasons s ON s.id = p.season_id
+WHERE s.is_current AND p.is_active AND p.registration_fee_due > 0
+  AND NOT u.payment_reminders_opt_out
+  AND NOT EXISTS (
+      SELECT 1 FROM payment_plans pp
+      WHERE pp.player_id = p.id AND pp.status = 'active'
+        AND NOT EXISTS (
+            SELECT 1 FROM payment_plan_installments i
+            WHERE i.plan_id = pp.id AND i.status = 'overdue'
+        )
+  )
+ORDER BY p.id;
+
+-- name: CreatePaymentReminder :one
+INSERT INTO payment_reminders (player_id, stage, amount_due, sent_to, status, error, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, NOW())
+RETURNING *;
+
+-- name: ListPaymentRemindersByPlayer :many
+SELECT * FROM payment_reminders
+WHERE player_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListPaymentReminders :many
+SELECT r.*, u.first_name, u.last_name
+FROM payment_reminders r
+INNER JOIN players p ON p.id = r.player_id
+INNER JOIN users u ON u.id = p.user_id
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE p.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR t.division_id = sqlc.narg('division_id'))
+ORDER BY r.created_at DESC;
+
+-- name: SyncRosterLocks :execrows
+WITH qualifying AS (
+    SELECT p.id
+    FROM players p
+    INNER JOIN seasons s ON s.id = p.season_id
+    WHERE sqlc.narg('lockout_days')::int IS NOT NULL
+      AND s.is_current AND p.is_active AND p.registration_fee_due > 0
+      AND p.created_at::date <= CURRENT_DATE - sqlc.narg('lockout_days')::int
+      AND NOT EXISTS (
+          SELECT 1 FROM payment_plans pp
+          WHERE pp.player_id = p.id AND pp.status = 'active'
+            AND NOT EXISTS (
+                SELECT 1 FROM payment_plan_installments i
+                WHERE i.plan_id = pp.id AND i.status = 'overdue'
+            )
+      )
+)
+UPDATE players p
+SET roster_locked_at = CASE WHEN p.id IN (SELECT id FROM qualifying) THEN NOW() END,
+    updated_at = NOW()
+WHERE (p.roster_locked_at IS NULL AND p.id IN (SELECT id FROM qualifying))
+   OR (p.roster_locked_at IS NOT NULL AND p.id NOT IN (SELECT id FROM qualifying));
+
+-- name: ListRosterLockedPlayersForGame :many
+SELECT p.id
+FROM players p
+INNER JOIN games g ON p.team_id IN (g.home_team_id, g.away_team_id)
+WHERE g.id = sqlc.arg('game_id') AND p.roster_locked_at IS NOT NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM game_details gd
+      WHERE gd.game_id = g.id AND gd.player_id = p.id
+  );
+
+-- name: SetPaymentRemindersOptOut :exec
+UPDATE users
+SET payment_reminders_opt_out = $1, updated_at = NOW()
+WHERE id = $2;
+
+-- name: GetPaymentReminderFailures :one
+SELECT COUNT(*) AS attempts,
+       COALESCE(EXTRACT(EPOCH FROM NOW() - MAX(created_at)), 0)::bigint AS seconds_since_last_attempt
+FROM payment_reminders
+WHERE player_id = $1 AND stage = $2 AND status = 'failed';
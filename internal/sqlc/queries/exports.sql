@@ -0,0 +1,22 @@
+-- name: ListPaymentsForExport :many
+SELECT py.id, py.payment_date, py.player_id, u.first_name, u.last_name, u.email, s.name AS season_name,
+       py.provider, py.stripe_id, py.payment_intent_id, py.status, py.amount, py.amount_refunded
+FROM payments py
+INNER JOIN players p ON py.player_id = p.id
+INNER JOIN users u ON p.user_id = u.id
+INNER JOIN seasons s ON s.id = p.season_id
+WHERE py.payment_date >= sqlc.arg('from_date') AND py.payment_date < sqlc.arg('to_date')
+  AND py.status IN ('completed', 'refunded')
+ORDER BY py.payment_date, py.id;
+
+-- name: ListRefundsForExport :many
+SELECT r.id, r.created_at, r.payment_id, py.player_id, u.first_name, u.last_name, u.email, s.name AS season_name,
+       py.stripe_id, r.provider_refund_id, r.reason, r.amount
+FROM refunds r
+INNER JOIN payments py ON py.id = r.payment_id
+INNER JOIN players p ON py.player_id = p.id
+INNER JOIN users u ON p.user_id = u.id
+INNER JOIN seasons s ON s.id = p.season_id
+WHERE r.status = 'succeeded'
+  AND r.created_at >= sqlc.arg('from_date') AND r.created_at < sqlc.arg('to_date')
+ORDER BY r.created_at, r.id;
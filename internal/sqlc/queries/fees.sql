@@ -0,0 +1,105 @@
+-- name: GetFeeScheduleBySeason :one
+SELECT * FROM fee_schedules WHERE season_id = $1;
+
+-- name: UpsertFeeSchedule :one
+INSERT INTO fee_schedules (
+    season_id, early_bird_deadline, early_bird_discount, late_deadline, late_fee,
+    returning_player_discount, sibling_discount, created_at, updated_at
+)
+VALUES (
+    sqlc.arg('season_id'), sqlc.narg('early_bird_deadline'), COALESCE(sqlc.narg('early_bird_discount')::numeric, 0),
+    sqlc.narg('late_deadline'), COALESCE(sqlc.narg('late_fee')::numeric, 0),
+    COALESCE(sqlc.narg('returning_player_discount')::numeric, 0), COALESCE(sqlc.narg('sibling_discount')::numeric, 0),
+    NOW(), NOW()
+)
+ON CONFLICT (season_id) DO UPDATE
+SET early_bird_deadline = EXCLUDED.early_bird_deadline,
+    early_bird_discount = EXCLUDED.early_bird_discount,
+    late_deadline = EXCLUDED.late_deadline,
+    late_fee = EXCLUDED.late_fee,
+    returning_player_discount = EXCLUDED.returning_player_discount,
+    sibling_discount = EXCLUDED.sibling_discount,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteFeeSchedule :execrows
+DELETE FROM fee_schedules
+WHERE season_id = $1;
+
+-- name: GetPlayerFeeContext :one
+SELECT p.id, p.season_id, p.fee_assessed_on,
+       COALESCE(d.registration_fee, s.registration_fee)::numeric AS base_fee,
+       EXISTS (
+           SELECT 1 FROM players prev
+           INNER JOIN seasons ps ON ps.id = prev.season_id
+           WHERE prev.user_id = p.user_id AND ps.start_date < s.start_date
+       ) AS is_returning,
+       EXISTS (
+           SELECT 1 FROM players sib
+           WHERE sib.id = p.sibling_player_id AND sib.season_id = p.season_id
+       ) AS has_sibling
+FROM players p
+INNER JOIN seasons s ON s.id = p.season_id
+LEFT JOIN teams t ON t.id = p.team_id
+LEFT JOIN divisions d ON d.id = t.division_id
+WHERE p.id = $1;
+
+-- name: ListRegistrationFeeItems :many
+SELECT * FROM registration_fee_items
+WHERE player_id = $1
+ORDER BY id;
+
+-- name: DeleteRegistrationFeeItems :exec
+DELETE FROM registration_fee_items
+WHERE player_id = $1;
+
+-- name: CreateRegistrationFeeItem :exec
+INSERT INTO registration_fee_items (player_id, kind, description, amount, created_at)
+VALUES ($1, $2, $3, $4, NOW());
+
+-- name: SetPlayerRegistrationFee :exec
+UPDATE players
+SET registration_fee = $1, updated_at = NOW()
+WHERE id = $2;
+
+-- name: ListPromoCodes :many
+SELECT * FROM promo_codes
+WHERE season_id = $1 OR season_id IS NULL
+ORDER BY code;
+
+-- name: GetPromoCodeByCode :one
+SELECT * FROM promo_codes WHERE code = $1;
+
+-- name: CreatePromoCode :one
+INSERT INTO promo_codes (
+    season_id, code, description, discount_type, discount_value,
+    max_redemptions, valid_from, valid_until, created_at, updated_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+RETURNING *;
+
+-- name: UpdatePromoCode :one
+UPDATE promo_codes
+SET description = $1, discount_type = $2, discount_value = $3, max_redemptions = $4,
+    valid_from = $5, valid_until = $6, is_active = $7, updated_at = NOW()
+WHERE id = $8
+RETURNING *;
+
+-- name: DeletePromoCode :execrows
+DELETE FROM promo_codes
+WHERE id = $1;
+
+-- name: RedeemPromoCode :one
+UPDATE promo_codes
+SET redemptions = redemptions + 1, updated_at = NOW()
+WHERE id = $1 AND (max_redemptions IS NULL OR redemptions < max_redemptions)
+RETURNING *;
+
+-- name: CreatePromoCodeRedemption :exec
+INSERT INTO promo_code_redemptions (promo_code_id, player_id, redeemed_at)
+VALUES ($1, $2, NOW());
+
+-- name: GetPlayerPromoCode :one
+SELECT pc.* FROM promo_codes pc
+INNER JOIN promo_code_redemptions r ON r.promo_code_id = pc.id
+WHERE r.player_id = $1;
@@ -16,19 +16,75 @@ WHERE PLAYER_ID IN
 SELECT * FROM game_details;
 
 -- name: ListGameDetailsVerbose :many
-SELECT gd.player_id, gd.game_id, p.team_id, u.first_name, u.last_name, p.jersey_number, gd.score
+SELECT gd.player_id, gd.game_id, p.team_id, u.first_name, u.last_name, p.jersey_number, gd.score,
+       gd.rebounds, gd.assists, gd.steals, gd.blocks, gd.turnovers, gd.fouls,
+       gd.field_goals_made, gd.field_goals_attempted, gd.three_pointers_made, gd.three_pointers_attempted,
+       gd.free_throws_made, gd.free_throws_attempted
 FROM game_details as gd
 INNER JOIN players as p ON gd.player_id = p.id
 INNER JOIN users as u on u.id = p.user_id
+WHERE p.season_id = $1
 order by game_id, team_id;
 
+-- name: ListGameDetailsVerboseByGame :many
+SELECT gd.player_id, gd.game_id, p.team_id, u.first_name, u.last_name, p.jersey_number, gd.score,
+       gd.rebounds, gd.assists, gd.steals, gd.blocks, gd.turnovers, gd.fouls,
+       gd.field_goals_made, gd.field_goals_attempted, gd.three_pointers_made, gd.three_pointers_attempted,
+       gd.free_throws_made, gd.free_throws_attempted
+FROM game_details as gd
+INNER JOIN players as p ON gd.player_id = p.id
+INNER JOIN users as u on u.id = p.user_id
+WHERE gd.game_id = $1
+order by team_id, jersey_number;
+
 -- name: CreateGameDetails :one
-INSERT INTO game_details 
-(game_id, player_id, score)
-VALUES ($1, $2, $3)
+INSERT INTO game_details
+(game_id, player_id, score, rebounds, assists, steals, blocks, turnovers, fouls,
+ field_goals_made, field_goals_attempted, three_pointers_made, three_pointers_attempted,
+ free_throws_made, free_throws_attempted)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 RETURNING *;
 
--- name: UpdateGameDetailsScore :exec
+-- name: UpsertGameDetails :one
+INSERT INTO game_details
+(game_id, player_id, score, rebounds, assists, steals, blocks, turnovers, fouls,
+ field_goals_made, field_goals_attempted, three_pointers_made, three_pointers_attempted,
+ free_throws_made, free_throws_attempted)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+ON CONFLICT (game_id, player_id) DO UPDATE
+SET score = EXCLUDED.score, rebounds = EXCLUDED.rebounds, assists = EXCLUDED.assists,
+    steals = EXCLUDED.steals, blocks = EXCLUDED.blocks, turnovers = EXCLUDED.turnovers,
+    fouls = EXCLUDED.fouls, field_goals_made = EXCLUDED.field_goals_made,
+    field_goals_attempted = EXCLUDED.field_goals_attempted,
+    three_pointers_made = EXCLUDED.three_pointers_made,
+    three_pointers_attempted = EXCLUDED.three_pointers_attempted,
+    free_throws_made = EXCLUDED.free_throws_made,
+    free_throws_attempted = EXCLUDED.free_throws_attempted
+RETURNING *;
+
+-- name: ListPlayerGameStats :many
+SELECT gd.*, g.game_time, g.status, g.home_team_id, ht.name AS home_team_name, g.home_score,
+       g.away_team_id, at.name AS away_team_name, g.away_score
+FROM game_details gd
+INNER JOIN games g ON g.id = gd.game_id
+INNER JOIN teams ht ON ht.id = g.home_team_id
+INNER JOIN teams at ON at.id = g.away_team_id
+WHERE gd.player_id = $1
+ORDER BY g.game_time;
+
+-- name: ListPlayerCareerGameStats :many
+SELECT gd.*, g.game_time, g.home_team_id, ht.name AS home_team_name, g.home_score,
+       g.away_team_id, at.name AS away_team_name, g.away_score
+FROM game_details gd
+INNER JOIN players p ON p.id = gd.player_id
+INNER JOIN games g ON g.id = gd.game_id
+INNER JOIN teams ht ON ht.id = g.home_team_id
+INNER JOIN teams at ON at.id = g.away_team_id
+WHERE p.user_id = (SELECT user_id FROM players WHERE id = sqlc.arg('player_id'))
+  AND g.status = 'completed'
+ORDER BY g.game_time, g.id;
+
+-- name: UpdateGameDetailsScore :execrows
 UPDATE game_details
 SET score = $3
 WHERE game_id = $1 AND player_id = $2;
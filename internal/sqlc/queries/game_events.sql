@@ -0,0 +1,74 @@
+-- name: CreateGameEvent :one
+INSERT INTO game_events
+(game_id, kind, period, clock_seconds, team_id, player_id, player_out_id, points, undoes_event_id, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: GetGameEvent :one
+SELECT e.*,
+  EXISTS (SELECT 1 FROM game_events u WHERE u.undoes_event_id = e.id) AS undone
+FROM game_events e
+WHERE e.id = $1;
+
+-- name: ListGameEvents :many
+SELECT e.*, pu.first_name, pu.last_name, ou.first_name AS player_out_first_name, ou.last_name AS player_out_last_name,
+  EXISTS (SELECT 1 FROM game_events u WHERE u.undoes_event_id = e.id) AS undone
+FROM game_events e
+LEFT JOIN players p ON p.id = e.player_id
+LEFT JOIN users pu ON pu.id = p.user_id
+LEFT JOIN players po ON po.id = e.player_out_id
+LEFT JOIN users ou ON ou.id = po.user_id
+WHERE e.game_id = $1
+ORDER BY e.period, e.clock_seconds DESC, e.id;
+
+-- name: GetCurrentGamePeriod :one
+SELECT e.kind, e.period
+FROM game_events e
+WHERE e.game_id = $1
+  AND e.kind IN ('period_start', 'period_end')
+  AND NOT EXISTS (SELECT 1 FROM game_events u WHERE u.undoes_event_id = e.id)
+ORDER BY e.period DESC, e.id DESC
+LIMIT 1;
+
+-- name: GameHasEvents :one
+SELECT EXISTS (SELECT 1 FROM game_events WHERE game_id = $1);
+
+-- name: CreateGameDetailsFromEvents :exec
+INSERT INTO game_details
+(game_id, player_id, score, fouls, field_goals_made, field_goals_attempted,
+ three_pointers_made, three_pointers_attempted, free_throws_made, free_throws_attempted)
+SELECT e.game_id, e.player_id, l.score, l.fouls, l.field_goals_made, l.field_goals_made,
+       l.three_pointers_made, l.three_pointers_made, l.free_throws_made, l.free_throws_made
+FROM (SELECT DISTINCT game_id, player_id FROM game_events WHERE game_id = $1 AND kind IN ('score', 'foul')) e
+CROSS JOIN LATERAL game_event_line(e.game_id, e.player_id) l
+ON CONFLICT (game_id, player_id) DO NOTHING;
+
+-- name: SyncGameDetailsFromEvents :exec
+UPDATE game_details gd
+SET (score, fouls, field_goals_made, field_goals_attempted, three_pointers_made,
+     three_pointers_attempted, free_throws_made, free_throws_attempted) = (
+    SELECT l.score, l.fouls, l.field_goals_made, GREATEST(gd.field_goals_attempted, l.field_goals_made),
+           l.three_pointers_made, GREATEST(gd.three_pointers_attempted, l.three_pointers_made),
+           l.free_throws_made, GREATEST(gd.free_throws_attempted, l.free_throws_made)
+    FROM game_event_line(gd.game_id, gd.player_id) l
+)
+WHERE gd.game_id = $1
+  AND EXISTS (
+    SELECT 1 FROM game_event_line(gd.game_id, gd.player_id) l
+    WHERE (gd.score, gd.fouls, gd.field_goals_made, gd.three_pointers_made, gd.free_throws_made)
+          IS DISTINCT FROM (l.score, l.fouls, l.field_goals_made, l.three_pointers_made, l.free_throws_made)
+  );
+
+-- name: SyncGameScoreFromEvents :exec
+UPDATE games
+SET home_score = game_event_points(id, home_team_id, NULL),
+    away_score = game_event_points(id, away_team_id, NULL),
+    updated_at = NOW()
+WHERE id = $1
+  AND (home_score <> game_event_points(id, home_team_id, NULL)
+       OR away_score <> game_event_points(id, away_team_id, NULL));
+
+-- name: StartGame :exec
+UPDATE games
+SET status = 'in_progress', updated_at = NOW()
+WHERE id = $1 AND status = 'scheduled';
@@ -0,0 +1,15 @@
+-- name: GetGameStreamEvent :one
+SELECT * FROM game_stream_events
+WHERE id = $1;
+
+-- name: ListGameStreamEventsSince :many
+SELECT * FROM game_stream_events
+WHERE id > sqlc.arg('after_id')
+  AND (sqlc.narg('game_id')::bigint IS NULL OR game_id = sqlc.narg('game_id'))
+  AND (sqlc.narg('game_date')::date IS NULL OR game_date = sqlc.narg('game_date'))
+ORDER BY id
+LIMIT 1000;
+
+-- name: DeleteGameStreamEventsBefore :execrows
+DELETE FROM game_stream_events
+WHERE created_at < $1;
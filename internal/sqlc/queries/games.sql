@@ -6,19 +6,35 @@ RETURNING *;
 -- name: GetGameById :one
 SELECT * FROM games WHERE id = $1;
 
+-- name: GetGameByIdForUpdate :one
+SELECT * FROM games WHERE id = $1
+FOR UPDATE;
+
 -- name: ListGames :many
-SELECT * FROM games
-ORDER BY game_time;
+SELECT g.* FROM games g
+INNER JOIN teams ht ON g.home_team_id = ht.id
+INNER JOIN teams at ON g.away_team_id = at.id
+WHERE ht.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR ht.division_id = sqlc.narg('division_id') OR at.division_id = sqlc.narg('division_id'))
+ORDER BY g.game_time;
 
 -- name: ListUpcomingGames :many
-SELECT * FROM games
-WHERE game_time > NOW()
-ORDER BY game_time;
+SELECT g.* FROM games g
+INNER JOIN teams ht ON g.home_team_id = ht.id
+INNER JOIN teams at ON g.away_team_id = at.id
+WHERE ht.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR ht.division_id = sqlc.narg('division_id') OR at.division_id = sqlc.narg('division_id'))
+  AND g.game_time > NOW()
+ORDER BY g.game_time;
 
 -- name: ListPastGames :many
-SELECT * FROM games
-WHERE game_time <= NOW()
-ORDER BY game_time DESC;
+SELECT g.* FROM games g
+INNER JOIN teams ht ON g.home_team_id = ht.id
+INNER JOIN teams at ON g.away_team_id = at.id
+WHERE ht.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR ht.division_id = sqlc.narg('division_id') OR at.division_id = sqlc.narg('division_id'))
+  AND g.game_time <= NOW()
+ORDER BY g.game_time DESC;
 
 -- name: ListGamesByTeam :many
 SELECT g.id, g.home_team_id, g.away_team_id, g.home_score, g.away_score, g.game_time, g.created_at, g.updated_at, g.status, 
@@ -66,6 +82,8 @@ SELECT g.id, g.home_team_id, g.away_team_id, g.home_score, g.away_score,
 FROM games g
 INNER JOIN teams ht ON g.home_team_id = ht.id
 INNER JOIN teams at ON g.away_team_id = at.id
+WHERE ht.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR ht.division_id = sqlc.narg('division_id') OR at.division_id = sqlc.narg('division_id'))
 ORDER BY g.game_time;
 
 -- name: ListTeamSchedule :many
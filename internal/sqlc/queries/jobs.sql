@@ -0,0 +1,5 @@
+-- name: TryJobLock :one
+SELECT pg_try_advisory_lock(hashtext(sqlc.arg('name')::text))::bool AS locked;
+
+-- name: ReleaseJobLock :exec
+SELECT pg_advisory_unlock(hashtext(sqlc.arg('name')::text));
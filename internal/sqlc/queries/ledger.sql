@@ -0,0 +1,74 @@
+-- name: PostLedgerTransactions :execrows
+WITH inserted AS (
+    INSERT INTO ledger_transactions (kind, player_id, season_id, payment_id, refund_id, amount, description)
+    SELECT kind, player_id, season_id, payment_id, refund_id, amount, description
+    FROM unposted_ledger_changes(sqlc.narg('player_id')::bigint, sqlc.narg('season_id')::bigint)
+    RETURNING id, kind, player_id, amount
+)
+INSERT INTO ledger_entries (transaction_id, account, player_id, amount)
+SELECT id, 'receivable', player_id, CASE WHEN kind IN ('charge', 'refund') THEN amount ELSE -amount END
+FROM inserted
+UNION ALL
+SELECT id, CASE kind WHEN 'charge' THEN 'revenue' WHEN 'discount' THEN 'discounts' ELSE 'cash' END, NULL,
+       CASE WHEN kind IN ('charge', 'refund') THEN -amount ELSE amount END
+FROM inserted;
+
+-- name: CreateLedgerTransaction :one
+INSERT INTO ledger_transactions (kind, player_id, season_id, amount, description, created_by, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, NOW())
+RETURNING *;
+
+-- name: CreateLedgerEntry :exec
+INSERT INTO ledger_entries (transaction_id, account, player_id, amount)
+VALUES ($1, $2, $3, $4);
+
+-- name: GetPlayerLedgerBalance :one
+SELECT COALESCE(SUM(amount), 0)::numeric AS balance
+FROM ledger_entries
+WHERE account = 'receivable' AND player_id = sqlc.arg('player_id')::bigint;
+
+-- name: ListPlayerLedger :many
+SELECT lt.*, le.amount AS balance_change,
+       SUM(le.amount) OVER (ORDER BY lt.created_at, lt.id)::numeric AS balance
+FROM ledger_transactions lt
+INNER JOIN ledger_entries le ON le.transaction_id = lt.id AND le.account = 'receivable'
+WHERE le.player_id = sqlc.arg('player_id')::bigint
+ORDER BY lt.created_at, lt.id;
+
+-- name: ListPlayerUnpostedLedger :many
+SELECT description::text AS description,
+       (CASE WHEN kind IN ('charge', 'refund') THEN amount ELSE -amount END)::numeric AS balance_change
+FROM unposted_ledger_changes(sqlc.arg('player_id')::bigint, NULL)
+ORDER BY payment_id NULLS FIRST, refund_id NULLS FIRST, kind;
+
+-- name: ListLedgerTransactions :many
+SELECT lt.*, u.first_name, u.last_name
+FROM ledger_transactions lt
+LEFT JOIN players p ON p.id = lt.player_id
+LEFT JOIN users u ON u.id = p.user_id
+WHERE lt.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('kind')::text IS NULL OR lt.kind = sqlc.narg('kind'))
+ORDER BY lt.created_at DESC, lt.id DESC;
+
+-- name: GetTrialBalance :many
+SELECT le.account,
+       COALESCE(SUM(le.amount) FILTER (WHERE le.amount > 0), 0)::numeric AS debits,
+       COALESCE(-SUM(le.amount) FILTER (WHERE le.amount < 0), 0)::numeric AS credits
+FROM ledger_entries le
+INNER JOIN ledger_transactions lt ON lt.id = le.transaction_id
+WHERE lt.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('before')::timestamp IS NULL OR lt.created_at < sqlc.narg('before'))
+GROUP BY le.account
+ORDER BY le.account;
+
+-- name: ListLedgerPlayerBalances :many
+SELECT le.player_id, u.first_name, u.last_name, SUM(le.amount)::numeric AS balance
+FROM ledger_entries le
+INNER JOIN ledger_transactions lt ON lt.id = le.transaction_id
+LEFT JOIN players p ON p.id = le.player_id
+LEFT JOIN users u ON u.id = p.user_id
+WHERE le.account = 'receivable' AND lt.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('before')::timestamp IS NULL OR lt.created_at < sqlc.narg('before'))
+GROUP BY le.player_id, u.first_name, u.last_name
+HAVING SUM(le.amount) <> 0
+ORDER BY u.last_name, u.first_name, le.player_id;
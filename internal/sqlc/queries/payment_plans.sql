@@ -0,0 +1,99 @@
+-- name: CreatePaymentPlan :one
+INSERT INTO payment_plans (player_id, total_amount, paid_before_plan, notes, created_by, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+RETURNING *;
+
+-- name: CreatePaymentPlanInstallment :one
+INSERT INTO payment_plan_installments (plan_id, installment_number, amount, due_date, created_at, updated_at)
+VALUES ($1, $2, $3, $4, NOW(), NOW())
+RETURNING *;
+
+-- name: GetPaymentPlanById :one
+SELECT * FROM payment_plans WHERE id = $1;
+
+-- name: GetActivePaymentPlanByPlayer :one
+SELECT * FROM payment_plans
+WHERE player_id = $1 AND status = 'active';
+
+-- name: ListPaymentPlansByPlayer :many
+SELECT * FROM payment_plans
+WHERE player_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListPaymentPlans :many
+SELECT pp.*, u.first_name, u.last_name, u.email,
+       (SELECT COUNT(*) FROM payment_plan_installments i WHERE i.plan_id = pp.id AND i.status = 'overdue')::bigint AS overdue_installments,
+       (SELECT MIN(i.due_date) FROM payment_plan_installments i WHERE i.plan_id = pp.id AND i.status <> 'paid')::date AS next_due_date
+FROM payment_plans pp
+INNER JOIN players p ON p.id = pp.player_id
+INNER JOIN users u ON u.id = p.user_id
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE p.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR t.division_id = sqlc.narg('division_id'))
+  AND (sqlc.narg('status')::text IS NULL OR pp.status = sqlc.narg('status'))
+ORDER BY u.last_name, u.first_name, pp.created_at DESC;
+
+-- name: ListPaymentPlanInstallments :many
+SELECT * FROM payment_plan_installments
+WHERE plan_id = $1
+ORDER BY installment_number;
+
+-- name: CancelPaymentPlan :execrows
+UPDATE payment_plans
+SET status = 'cancelled', updated_at = NOW()
+WHERE id = $1 AND status = 'active';
+
+-- name: GetPlayerNetPaid :one
+SELECT COALESCE(SUM(amount - amount_refunded), 0)::numeric AS net_paid
+FROM payments
+WHERE player_id = $1 AND status IN ('completed', 'refunded');
+
+-- name: SyncPaymentPlanInstallments :execrows
+WITH plan AS (
+    SELECT pp.id, pp.paid_before_plan
+    FROM payment_plans pp
+    WHERE pp.player_id = sqlc.arg('player_id') AND pp.status = 'active'
+), paid AS (
+    SELECT COALESCE(SUM(py.amount - py.amount_refunded), 0) AS total
+    FROM payments py
+    WHERE py.player_id = sqlc.arg('player_id') AND py.status IN ('completed', 'refunded')
+), expected AS (
+    SELECT i.id,
+           CASE
+               WHEN SUM(i.amount) OVER (ORDER BY i.installment_number) <= paid.total - plan.paid_before_plan THEN 'paid'
+               WHEN i.due_date < CURRENT_DATE THEN 'overdue'
+               ELSE 'pending'
+           END AS status
+    FROM payment_plan_installments i
+    INNER JOIN plan ON plan.id = i.plan_id
+    CROSS JOIN paid
+)
+UPDATE payment_plan_installments i
+SET status = e.status,
+    paid_at = CASE WHEN e.status = 'paid' THEN COALESCE(i.paid_at, NOW()) END,
+    updated_at = NOW()
+FROM expected e
+WHERE i.id = e.id AND i.status <> e.status;
+
+-- name: CompletePaymentPlans :execrows
+UPDATE payment_plans pp
+SET status = 'completed', updated_at = NOW()
+WHERE pp.player_id = $1 AND pp.status = 'active'
+  AND (
+      NOT EXISTS (
+          SELECT 1 FROM payment_plan_installments i
+          WHERE i.plan_id = pp.id AND i.status <> 'paid'
+      )
+      -- Nothing is left to pay when the rest was written off
+      OR EXISTS (
+          SELECT 1 FROM players p
+          WHERE p.id = pp.player_id AND p.is_fully_registered
+      )
+  );
+
+-- name: MarkOverdueInstallments :execrows
+UPDATE payment_plan_installments i
+SET status = 'overdue', updated_at = NOW()
+FROM payment_plans pp
+WHERE pp.id = i.plan_id AND pp.status = 'active'
+  AND i.status = 'pending' AND i.due_date < CURRENT_DATE;
@@ -6,12 +6,60 @@ RETURNING *;
 -- name: GetPaymentById :one
 SELECT * FROM payments WHERE id = $1;
 
+-- name: GetPaymentByIdForUpdate :one
+SELECT * FROM payments WHERE id = $1
+FOR UPDATE;
+
 -- name: GetPaymentByStripeId :one
 SELECT * FROM payments WHERE stripe_id = $1;
 
--- name: ListPayments :many
+-- name: GetPaymentByStripeIdForUpdate :one
 SELECT * FROM payments
-ORDER BY payment_date DESC;
+WHERE stripe_id = sqlc.arg('stripe_id') OR payment_intent_id = sqlc.arg('stripe_id')
+FOR UPDATE;
+
+-- name: SetPaymentIntentId :exec
+UPDATE payments
+SET payment_intent_id = $1
+WHERE id = $2;
+
+-- name: CreateCheckoutPayment :one
+INSERT INTO payments (player_id, stripe_id, amount, status, provider, checkout_url, checkout_expires_at, payment_date)
+VALUES ($1, $2, $3, 'pending', $4, $5, $6, NOW())
+RETURNING *;
+
+-- name: CreateProviderPayment :one
+INSERT INTO payments (player_id, stripe_id, amount, status, provider, payment_intent_id, payment_date)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetOpenCheckoutPayment :one
+SELECT * FROM payments
+WHERE player_id = $1 AND provider = $2 AND amount = $3
+  AND status = 'pending' AND checkout_url IS NOT NULL
+  AND checkout_expires_at > NOW() + INTERVAL '5 minutes'
+ORDER BY payment_date DESC
+LIMIT 1;
+
+-- name: ListOpenCheckoutPayments :many
+SELECT * FROM payments
+WHERE player_id = $1 AND provider = $2
+  AND status = 'pending' AND checkout_url IS NOT NULL
+  AND checkout_expires_at > NOW()
+ORDER BY payment_date;
+
+-- name: CompletePayment :exec
+UPDATE payments
+SET status = 'completed', amount = $1
+WHERE id = $2;
+
+-- name: ListPayments :many
+SELECT py.* FROM payments py
+INNER JOIN players p ON py.player_id = p.id
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE p.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR t.division_id = sqlc.narg('division_id'))
+ORDER BY py.payment_date DESC;
 
 -- name: ListPaymentsByPlayer :many
 SELECT * FROM payments
@@ -19,18 +67,35 @@ WHERE player_id = $1
 ORDER BY payment_date DESC;
 
 -- name: ListPaymentsByStatus :many
-SELECT * FROM payments
-WHERE status = $1
-ORDER BY payment_date DESC;
+SELECT py.* FROM payments py
+INNER JOIN players p ON py.player_id = p.id
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE p.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR t.division_id = sqlc.narg('division_id'))
+  AND py.status = sqlc.arg('status')
+ORDER BY py.payment_date DESC;
 
--- name: UpdatePaymentStatus :exec
+-- name: UpdatePaymentStatus :one
 UPDATE payments
 SET status = $1
-WHERE id = $2;
+WHERE id = $2
+RETURNING player_id;
+
+-- name: UpdatePaymentFromProvider :exec
+UPDATE payments
+SET amount = $1, status = $2
+WHERE id = $3;
+
+-- name: ListProviderPaymentsBetween :many
+SELECT * FROM payments
+WHERE provider = sqlc.arg('provider') AND payment_date >= sqlc.arg('from_date') AND payment_date < sqlc.arg('to_date')
+  AND status IN ('completed', 'refunded')
+ORDER BY payment_date;
 
--- name: DeletePayment :exec
+-- name: DeletePayment :one
 DELETE FROM payments
-WHERE id = $1;
+WHERE id = $1
+RETURNING player_id;
 
 -- name: GetPaymentWithPlayer :one
 SELECT py.*, p.user_id, u.email, u.first_name, u.last_name
@@ -44,15 +109,36 @@ SELECT py.*, u.first_name, u.last_name, u.email
 FROM payments py
 INNER JOIN players p ON py.player_id = p.id
 INNER JOIN users u ON p.user_id = u.id
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE p.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR t.division_id = sqlc.narg('division_id'))
 ORDER BY py.payment_date DESC;
 
 -- name: GetPlayerPaymentSummary :one
 SELECT 
     player_id,
     COUNT(*) as total_payments,
-    SUM(CASE WHEN status = 'completed' THEN amount ELSE 0 END) as total_paid,
+    SUM(CASE WHEN status IN ('completed', 'refunded') THEN amount ELSE 0 END) as total_paid,
     SUM(CASE WHEN status = 'pending' THEN amount ELSE 0 END) as total_pending,
-    SUM(CASE WHEN status = 'failed' THEN amount ELSE 0 END) as total_failed
+    SUM(CASE WHEN status = 'failed' THEN amount ELSE 0 END) as total_failed,
+    SUM(amount_refunded) as total_refunded,
+    SUM(CASE WHEN status IN ('completed', 'refunded') THEN amount - amount_refunded ELSE 0 END) as net_paid
 FROM payments
 WHERE player_id = $1
 GROUP BY player_id;
+
+-- name: GetPaymentReceipt :one
+SELECT py.*, p.user_id, p.season_id, u.email, u.first_name, u.last_name, s.name AS season_name
+FROM payments py
+INNER JOIN players p ON py.player_id = p.id
+INNER JOIN users u ON p.user_id = u.id
+INNER JOIN seasons s ON s.id = p.season_id
+WHERE py.id = $1;
+
+-- name: ListPaymentsByUser :many
+SELECT py.*, p.season_id, s.name AS season_name
+FROM payments py
+INNER JOIN players p ON py.player_id = p.id
+INNER JOIN seasons s ON s.id = p.season_id
+WHERE p.user_id = $1
+ORDER BY py.payment_date DESC;
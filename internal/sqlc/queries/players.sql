@@ -1,22 +1,47 @@
 -- name: CreatePlayer :one
-INSERT INTO players (user_id, team_id, registration_fee_due, is_fully_registered, is_active, jersey_number, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+INSERT INTO players (user_id, team_id, is_active, jersey_number, sibling_player_id, season_id, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5,
+        COALESCE((SELECT t.season_id FROM teams t WHERE t.id = $2), current_season_id()),
+        NOW(), NOW())
 RETURNING *;
 
 -- name: GetPlayerById :one
 SELECT * FROM players WHERE id = $1;
 
+-- name: ResetPlayerFeeAssessedOn :exec
+UPDATE players
+SET fee_assessed_on = CURRENT_DATE, updated_at = NOW()
+WHERE id = $1;
+
+-- name: GetPlayerByIdForUpdate :one
+SELECT * FROM players WHERE id = $1
+FOR NO KEY UPDATE;
+
 -- name: GetPlayerByUserId :one
-SELECT * FROM players WHERE user_id = $1;
+SELECT p.* FROM players p
+INNER JOIN seasons s ON s.id = p.season_id
+WHERE p.user_id = $1
+ORDER BY s.is_current DESC, s.start_date DESC
+LIMIT 1;
 
--- name: ListPlayers :many
+-- name: GetPlayerByUserAndSeason :one
 SELECT * FROM players
-ORDER BY id;
+WHERE user_id = $1 AND season_id = $2;
+
+-- name: ListPlayers :many
+SELECT p.* FROM players p
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE p.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR t.division_id = sqlc.narg('division_id'))
+ORDER BY p.id;
 
 -- name: ListActivePlayers :many
-SELECT * FROM players
-WHERE is_active = true
-ORDER BY id;
+SELECT p.* FROM players p
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE p.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR t.division_id = sqlc.narg('division_id'))
+  AND p.is_active = true
+ORDER BY p.id;
 
 -- name: ListPlayersByTeam :many
 SELECT * FROM players
@@ -25,19 +50,65 @@ ORDER BY jersey_number, id;
 
 -- name: UpdatePlayer :exec
 UPDATE players
-SET team_id = $1, registration_fee_due = $2, is_fully_registered = $3, 
-    is_active = $4, jersey_number = $5, updated_at = NOW()
-WHERE id = $6;
+SET team_id = $1, is_active = $2, jersey_number = $3, updated_at = NOW()
+WHERE id = $4;
 
 -- name: UpdatePlayerTeam :exec
 UPDATE players
 SET team_id = $1, updated_at = NOW()
 WHERE id = $2;
 
--- name: UpdatePlayerRegistrationStatus :exec
-UPDATE players
-SET registration_fee_due = $1, is_fully_registered = $2, updated_at = NOW()
-WHERE id = $3;
+-- name: LockPlayers :exec
+SELECT id FROM players
+WHERE (sqlc.narg('player_id')::bigint IS NULL OR id = sqlc.narg('player_id'))
+  AND (sqlc.narg('season_id')::bigint IS NULL OR season_id = sqlc.narg('season_id'))
+ORDER BY id
+FOR NO KEY UPDATE;
+
+-- name: ReconcilePlayerRegistrations :execrows
+WITH expected AS (
+    SELECT p.id, GREATEST(COALESCE(SUM(le.amount), 0), 0) AS fee_due
+    FROM players p
+    LEFT JOIN ledger_entries le ON le.account = 'receivable' AND le.player_id = p.id
+    WHERE (sqlc.narg('player_id')::bigint IS NULL OR p.id = sqlc.narg('player_id'))
+      AND (sqlc.narg('season_id')::bigint IS NULL OR p.season_id = sqlc.narg('season_id'))
+    GROUP BY p.id
+)
+UPDATE players p
+SET registration_fee_due = e.fee_due,
+    is_fully_registered = e.fee_due = 0,
+    roster_locked_at = CASE WHEN e.fee_due = 0 THEN NULL ELSE p.roster_locked_at END,
+    updated_at = NOW()
+FROM expected e
+WHERE p.id = e.id
+  AND (p.registration_fee_due <> e.fee_due OR p.is_fully_registered <> (e.fee_due = 0)
+       OR (e.fee_due = 0 AND p.roster_locked_at IS NOT NULL));
+
+-- name: ListRegistrationMismatches :many
+SELECT p.id AS player_id, p.user_id, u.first_name, u.last_name, u.email,
+       p.registration_fee_due, p.is_fully_registered,
+       COALESCE(lb.balance, 0)::numeric AS ledger_balance,
+       COALESCE(ub.balance, 0)::numeric AS unposted_balance,
+       GREATEST(COALESCE(lb.balance, 0) + COALESCE(ub.balance, 0), 0)::numeric AS expected_fee_due
+FROM players p
+INNER JOIN users u ON u.id = p.user_id
+LEFT JOIN teams t ON t.id = p.team_id
+LEFT JOIN (
+    SELECT player_id, SUM(amount) AS balance
+    FROM ledger_entries
+    WHERE account = 'receivable'
+    GROUP BY player_id
+) lb ON lb.player_id = p.id
+LEFT JOIN (
+    SELECT player_id, SUM(CASE WHEN kind IN ('charge', 'refund') THEN amount ELSE -amount END) AS balance
+    FROM unposted_ledger_changes(NULL, sqlc.arg('season_id'))
+    GROUP BY player_id
+) ub ON ub.player_id = p.id
+WHERE p.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR t.division_id = sqlc.narg('division_id'))
+  AND (p.registration_fee_due <> GREATEST(COALESCE(lb.balance, 0) + COALESCE(ub.balance, 0), 0)
+    OR p.is_fully_registered <> (GREATEST(COALESCE(lb.balance, 0) + COALESCE(ub.balance, 0), 0) = 0))
+ORDER BY u.last_name, u.first_name;
 
 -- name: DeletePlayer :exec
 DELETE FROM players
@@ -59,11 +130,24 @@ WHERE p.id = $1;
 SELECT p.*, u.email, u.first_name, u.last_name
 FROM players p
 INNER JOIN users u ON p.user_id = u.id
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE p.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR t.division_id = sqlc.narg('division_id'))
 ORDER BY u.last_name, u.first_name;
 
 -- name: ListFreeAgents :many
 SELECT p.*, u.email, u.first_name, u.last_name
 FROM players p
 INNER JOIN users u ON p.user_id = u.id
-WHERE p.team_id IS NULL AND p.is_active = true
+WHERE p.season_id = $1 AND p.team_id IS NULL AND p.is_active = true
 ORDER BY u.last_name, u.first_name;
+
+-- name: ListPlayerCareerSeasons :many
+SELECT p.id, p.season_id, s.name AS season_name, p.team_id, t.name AS team_name, p.jersey_number,
+       u.first_name, u.last_name
+FROM players p
+INNER JOIN seasons s ON s.id = p.season_id
+INNER JOIN users u ON u.id = p.user_id
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE p.user_id = (SELECT user_id FROM players WHERE id = sqlc.arg('player_id'))
+ORDER BY s.start_date, p.id;
@@ -0,0 +1,69 @@
+-- name: CreateRefund :one
+INSERT INTO refunds (payment_id, amount, reason, created_by, created_at, updated_at)
+VALUES ($1, $2, $3, $4, NOW(), NOW())
+RETURNING *;
+
+-- name: GetRefundById :one
+SELECT * FROM refunds WHERE id = $1;
+
+-- name: SetRefundProviderResult :one
+UPDATE refunds
+SET provider_refund_id = COALESCE(sqlc.narg('provider_refund_id')::text, provider_refund_id),
+    status = CASE WHEN sqlc.arg('status')::text = 'pending' THEN status ELSE sqlc.arg('status')::text END,
+    updated_at = NOW()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: UpsertProviderRefund :one
+INSERT INTO refunds (payment_id, amount, reason, status, provider_refund_id, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+ON CONFLICT (provider_refund_id) DO UPDATE
+SET status = CASE WHEN EXCLUDED.status = 'pending' THEN refunds.status ELSE EXCLUDED.status END,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: ClaimChargeRefund :one
+UPDATE refunds
+SET provider_refund_id = sqlc.arg('provider_refund_id')::text,
+    status = CASE WHEN sqlc.arg('status')::text = 'pending' THEN status ELSE sqlc.arg('status')::text END,
+    updated_at = NOW()
+WHERE id = (
+    SELECT r.id FROM refunds r
+    WHERE r.payment_id = sqlc.arg('payment_id')::bigint AND r.amount = sqlc.arg('amount')::numeric
+      AND r.provider_refund_id LIKE 'charge:%'
+    ORDER BY r.id
+    LIMIT 1
+)
+RETURNING *;
+
+-- name: GetCommittedRefundAmount :one
+SELECT COALESCE(SUM(amount), 0)::numeric AS committed
+FROM refunds
+WHERE payment_id = $1 AND status <> 'failed';
+
+-- name: SyncPaymentRefunds :exec
+UPDATE payments py
+SET amount_refunded = r.total,
+    status = CASE WHEN r.total >= py.amount THEN 'refunded' ELSE 'completed' END
+FROM (
+    SELECT COALESCE(SUM(amount), 0) AS total
+    FROM refunds
+    WHERE payment_id = sqlc.arg('id') AND status = 'succeeded'
+) r
+WHERE py.id = sqlc.arg('id') AND py.status IN ('completed', 'refunded');
+
+-- name: ListRefundsByPayment :many
+SELECT * FROM refunds
+WHERE payment_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListRefundsWithPlayerInfo :many
+SELECT r.*, py.player_id, u.first_name, u.last_name, u.email
+FROM refunds r
+INNER JOIN payments py ON py.id = r.payment_id
+INNER JOIN players p ON p.id = py.player_id
+INNER JOIN users u ON u.id = p.user_id
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE p.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR t.division_id = sqlc.narg('division_id'))
+ORDER BY r.created_at DESC;
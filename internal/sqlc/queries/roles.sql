@@ -0,0 +1,57 @@
+-- name: IsTeamCaptain :one
+SELECT EXISTS (
+    SELECT 1 FROM team_captains
+    WHERE team_id = $1 AND user_id = $2
+);
+
+-- name: ListTeamCaptains :many
+SELECT u.id, u.email, u.first_name, u.last_name, tc.created_at
+FROM team_captains tc
+INNER JOIN users u ON tc.user_id = u.id
+WHERE tc.team_id = $1
+ORDER BY u.last_name, u.first_name;
+
+-- name: ListCaptainedTeamsByUser :many
+SELECT t.*
+FROM team_captains tc
+INNER JOIN teams t ON tc.team_id = t.id
+WHERE tc.user_id = $1
+ORDER BY t.name;
+
+-- name: AddTeamCaptain :exec
+INSERT INTO team_captains (team_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveTeamCaptain :exec
+DELETE FROM team_captains
+WHERE team_id = $1 AND user_id = $2;
+
+-- name: IsAssignedToGame :one
+SELECT EXISTS (
+    SELECT 1 FROM game_assignments
+    WHERE game_id = $1 AND user_id = $2
+);
+
+-- name: ListGameAssignments :many
+SELECT u.id, u.email, u.first_name, u.last_name, u.role, ga.created_at
+FROM game_assignments ga
+INNER JOIN users u ON ga.user_id = u.id
+WHERE ga.game_id = $1
+ORDER BY u.last_name, u.first_name;
+
+-- name: ListAssignedGamesByUser :many
+SELECT g.*
+FROM game_assignments ga
+INNER JOIN games g ON ga.game_id = g.id
+WHERE ga.user_id = $1
+ORDER BY g.game_time;
+
+-- name: AssignUserToGame :exec
+INSERT INTO game_assignments (game_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: UnassignUserFromGame :exec
+DELETE FROM game_assignments
+WHERE game_id = $1 AND user_id = $2;
@@ -0,0 +1,33 @@
+-- name: GetSeasonById :one
+SELECT * FROM seasons WHERE id = $1;
+
+-- name: GetCurrentSeason :one
+SELECT * FROM seasons WHERE is_current;
+
+-- name: ListSeasons :many
+SELECT * FROM seasons
+ORDER BY start_date DESC;
+
+-- name: CreateSeason :one
+INSERT INTO seasons (name, start_date, end_date, registration_fee, created_at, updated_at)
+VALUES ($1, $2, $3, $4, NOW(), NOW())
+RETURNING *;
+
+-- name: UpdateSeason :execrows
+UPDATE seasons
+SET name = $1, start_date = $2, end_date = $3, registration_fee = $4, updated_at = NOW()
+WHERE id = $5;
+
+-- name: ClearCurrentSeason :exec
+UPDATE seasons
+SET is_current = FALSE, updated_at = NOW()
+WHERE is_current;
+
+-- name: SetCurrentSeason :execrows
+UPDATE seasons
+SET is_current = TRUE, updated_at = NOW()
+WHERE id = $1;
+
+-- name: DeleteSeason :exec
+DELETE FROM seasons
+WHERE id = $1;
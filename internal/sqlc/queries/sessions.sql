@@ -0,0 +1,46 @@
+-- name: CreateSession :one
+INSERT INTO sessions (user_id, refresh_token_hash, user_agent, ip_address, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id;
+
+-- name: IsSessionActive :one
+SELECT EXISTS (
+    SELECT 1 FROM sessions
+    WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+);
+
+-- name: RotateSessionToken :one
+UPDATE sessions
+SET refresh_token_hash = sqlc.arg('new_hash'), previous_token_hash = refresh_token_hash, last_used_at = NOW()
+WHERE refresh_token_hash = sqlc.arg('old_hash') AND revoked_at IS NULL AND expires_at > NOW()
+RETURNING id, user_id;
+
+-- name: RevokeSessionByPreviousHash :execrows
+UPDATE sessions
+SET revoked_at = NOW()
+WHERE previous_token_hash = $1 AND revoked_at IS NULL;
+
+-- name: RevokeSessionByRefreshHash :exec
+UPDATE sessions
+SET revoked_at = NOW()
+WHERE refresh_token_hash = $1 AND revoked_at IS NULL;
+
+-- name: RevokeUserSession :execrows
+UPDATE sessions
+SET revoked_at = NOW()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: RevokeAllUserSessions :execrows
+UPDATE sessions
+SET revoked_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- name: ListActiveSessionsByUser :many
+SELECT id, user_agent, ip_address, created_at, last_used_at, expires_at
+FROM sessions
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+ORDER BY last_used_at DESC;
+
+-- name: DeleteExpiredSessions :execrows
+DELETE FROM sessions
+WHERE expires_at < NOW();
@@ -0,0 +1,49 @@
+-- name: ListStatLeaders :many
+SELECT p.id AS player_id, u.first_name, u.last_name, p.jersey_number, p.team_id, t.name AS team_name,
+       COUNT(*) AS games_played,
+       SUM(s.made)::bigint AS total,
+       SUM(s.attempted)::bigint AS attempted,
+       (CASE sqlc.arg('kind')::text
+            WHEN 'total' THEN SUM(s.made)
+            WHEN 'per_game' THEN ROUND(SUM(s.made)::numeric / COUNT(*), 1)
+            ELSE ROUND(100 * SUM(s.made)::numeric / NULLIF(SUM(s.attempted), 0), 1)
+        END)::float8 AS value,
+       RANK() OVER (ORDER BY (CASE sqlc.arg('kind')::text
+            WHEN 'total' THEN SUM(s.made)
+            WHEN 'per_game' THEN ROUND(SUM(s.made)::numeric / COUNT(*), 1)
+            ELSE ROUND(100 * SUM(s.made)::numeric / NULLIF(SUM(s.attempted), 0), 1)
+        END) DESC) AS rank
+FROM game_details gd
+INNER JOIN games g ON g.id = gd.game_id
+INNER JOIN players p ON p.id = gd.player_id
+INNER JOIN users u ON u.id = p.user_id
+LEFT JOIN teams t ON t.id = p.team_id
+CROSS JOIN LATERAL (
+    SELECT CASE sqlc.arg('stat')::text
+               WHEN 'points' THEN gd.score
+               WHEN 'rebounds' THEN gd.rebounds
+               WHEN 'assists' THEN gd.assists
+               WHEN 'steals' THEN gd.steals
+               WHEN 'blocks' THEN gd.blocks
+               WHEN 'turnovers' THEN gd.turnovers
+               WHEN 'fouls' THEN gd.fouls
+               WHEN 'field_goals' THEN gd.field_goals_made
+               WHEN 'three_pointers' THEN gd.three_pointers_made
+               WHEN 'free_throws' THEN gd.free_throws_made
+           END AS made,
+           CASE sqlc.arg('stat')::text
+               WHEN 'field_goals' THEN gd.field_goals_attempted
+               WHEN 'three_pointers' THEN gd.three_pointers_attempted
+               WHEN 'free_throws' THEN gd.free_throws_attempted
+               ELSE 0
+           END AS attempted
+) s
+WHERE g.status = 'completed'
+  AND p.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR t.division_id = sqlc.narg('division_id'))
+  AND (sqlc.narg('team_id')::bigint IS NULL OR p.team_id = sqlc.narg('team_id'))
+GROUP BY p.id, u.id, t.id
+HAVING COUNT(*) >= sqlc.arg('min_games')::int
+   AND SUM(s.attempted) >= sqlc.arg('min_attempts')::int
+ORDER BY rank, u.last_name, u.first_name, p.id
+LIMIT sqlc.arg('max_rows')::int;
@@ -3,18 +3,21 @@ SELECT * FROM teams where id = $1;
 
 -- name: ListTeams :many
 SELECT * FROM teams
+WHERE season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR division_id = sqlc.narg('division_id'))
 ORDER BY name;
 
 -- name: CreateTeam :one
-INSERT INTO teams (name, wins, losses, draws, points_for, points_against, created_at, updated_at)
-values ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+INSERT INTO teams (name, season_id, division_id, created_at, updated_at)
+values (sqlc.arg('name'), COALESCE(sqlc.narg('season_id')::bigint, current_season_id()), sqlc.narg('division_id'), NOW(), NOW())
 RETURNING *;
 
 
--- name: UpdateTeam :exec
+-- name: UpdateTeam :one
 UPDATE teams
-SET name = $1, wins = $2, losses = $3, draws = $4, points_for = $5, points_against = $6, updated_at = NOW()
-WHERE id = $7;
+SET name = $1, division_id = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING season_id;
 
 -- name: DeleteTeam :exec
 DELETE FROM teams
@@ -33,13 +36,29 @@ u.first_name, u.last_name
 FROM teams t
 LEFT JOIN players p on t.id = p.team_id
 LEFT JOIN users u on u.id = p.user_id
+WHERE t.season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR t.division_id = sqlc.narg('division_id'))
 ORDER BY t.id;
 
 -- name: GetTeamStats :one
 SELECT t.*,
-       COUNT(p.id) as player_count
+       (SELECT COUNT(*) FROM players p WHERE p.team_id = t.id AND p.is_active = true)::bigint as player_count,
+       COALESCE(SUM(gd.rebounds), 0)::bigint AS rebounds,
+       COALESCE(SUM(gd.assists), 0)::bigint AS assists,
+       COALESCE(SUM(gd.steals), 0)::bigint AS steals,
+       COALESCE(SUM(gd.blocks), 0)::bigint AS blocks,
+       COALESCE(SUM(gd.turnovers), 0)::bigint AS turnovers,
+       COALESCE(SUM(gd.fouls), 0)::bigint AS fouls,
+       COALESCE(SUM(gd.field_goals_made), 0)::bigint AS field_goals_made,
+       COALESCE(SUM(gd.field_goals_attempted), 0)::bigint AS field_goals_attempted,
+       COALESCE(SUM(gd.three_pointers_made), 0)::bigint AS three_pointers_made,
+       COALESCE(SUM(gd.three_pointers_attempted), 0)::bigint AS three_pointers_attempted,
+       COALESCE(SUM(gd.free_throws_made), 0)::bigint AS free_throws_made,
+       COALESCE(SUM(gd.free_throws_attempted), 0)::bigint AS free_throws_attempted
 FROM teams t
-LEFT JOIN players p ON p.team_id = t.id AND p.is_active = true
+LEFT JOIN players tp ON tp.team_id = t.id
+LEFT JOIN game_details gd ON gd.player_id = tp.id
+  AND gd.game_id IN (SELECT id FROM games WHERE status = 'completed' AND t.id IN (home_team_id, away_team_id))
 WHERE t.id = $1
 GROUP BY t.id;
 
@@ -48,4 +67,37 @@ SELECT id, name, wins, losses, draws, points_for, points_against, created_at, up
        (wins * 3 + draws) as points,
        (points_for - points_against) as point_differential
 FROM teams
+WHERE season_id = sqlc.arg('season_id')
+  AND (sqlc.narg('division_id')::bigint IS NULL OR division_id = sqlc.narg('division_id'))
 ORDER BY points DESC, point_differential DESC, name ASC;
+
+-- name: LockTeamStandings :exec
+SELECT pg_advisory_xact_lock(hashtext('team_standings'));
+
+-- name: RecomputeTeamStandings :execrows
+UPDATE teams t
+SET wins = s.wins, losses = s.losses, draws = s.draws,
+    points_for = s.points_for, points_against = s.points_against,
+    updated_at = NOW()
+FROM (
+    SELECT tm.id,
+           COUNT(r.team_id) FILTER (WHERE r.scored > r.allowed)::int AS wins,
+           COUNT(r.team_id) FILTER (WHERE r.scored < r.allowed)::int AS losses,
+           COUNT(r.team_id) FILTER (WHERE r.scored = r.allowed)::int AS draws,
+           COALESCE(SUM(r.scored), 0)::int AS points_for,
+           COALESCE(SUM(r.allowed), 0)::int AS points_against
+    FROM teams tm
+    LEFT JOIN (
+        SELECT home_team_id AS team_id, home_score AS scored, away_score AS allowed
+        FROM games g WHERE status = 'completed'
+          AND NOT EXISTS (SELECT 1 FROM bracket_matches bm WHERE bm.game_id = g.id)
+        UNION ALL
+        SELECT away_team_id, away_score, home_score
+        FROM games g WHERE status = 'completed'
+          AND NOT EXISTS (SELECT 1 FROM bracket_matches bm WHERE bm.game_id = g.id)
+    ) r ON r.team_id = tm.id
+    GROUP BY tm.id
+) s
+WHERE t.id = s.id
+  AND (t.wins, t.losses, t.draws, t.points_for, t.points_against)
+      IS DISTINCT FROM (s.wins, s.losses, s.draws, s.points_for, s.points_against);
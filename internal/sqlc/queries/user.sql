@@ -8,7 +8,7 @@ SELECT id, email, phone_number, first_name, last_name, role, created_at
 FROM users WHERE email = $1;
 
 -- name: GetUserById :one
-SELECT id, email, phone_number, first_name, last_name, role, created_at
+SELECT id, email, phone_number, first_name, last_name, role, payment_reminders_opt_out, created_at
 FROM users WHERE id = $1;
 
 -- name: ListUsers :many
@@ -0,0 +1,16 @@
+-- name: RecordWebhookEvent :one
+INSERT INTO webhook_events (provider, event_id, event_type, payload)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (provider, event_id) DO NOTHING
+RETURNING id;
+
+-- name: SetWebhookEventOutcome :exec
+UPDATE webhook_events
+SET outcome = $1, note = $2
+WHERE id = $3;
+
+-- name: ListWebhookEvents :many
+SELECT id, provider, event_id, event_type, outcome, note, received_at
+FROM webhook_events
+ORDER BY received_at DESC
+LIMIT $1;
@@ -0,0 +1,19 @@
+-- Migration: One box-score row per player per game
+-- The original unique_score constraint included the score column, so the same
+-- player could be entered more than once for a game. Box-score upserts need a
+-- single row per (game_id, player_id). Duplicates can only be repeated or
+-- corrected entries of the same line, so keep the newest and drop the rest.
+
+DELETE FROM game_details gd
+USING game_details newer
+WHERE gd.game_id = newer.game_id
+  AND gd.player_id = newer.player_id
+  AND gd.id < newer.id;
+
+ALTER TABLE game_details DROP CONSTRAINT unique_score;
+
+ALTER TABLE game_details
+ADD CONSTRAINT unique_game_player UNIQUE (game_id, player_id);
+
+ALTER TABLE game_details
+ADD CONSTRAINT score_not_negative CHECK (score >= 0);
@@ -0,0 +1,124 @@
+-- Migration: Seasons and divisions
+-- Teams and player registrations belong to a season, and teams can be placed
+-- in a division within that season. Games and payments are scoped through the
+-- teams and players they reference. Existing data is moved into a default
+-- season that is marked as current.
+
+CREATE TABLE seasons (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT UNIQUE NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    is_current BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT season_dates_ordered CHECK (end_date >= start_date)
+);
+
+-- Only one season can be current at a time
+CREATE UNIQUE INDEX idx_seasons_single_current ON seasons (is_current) WHERE is_current;
+
+CREATE TABLE divisions (
+    id BIGSERIAL PRIMARY KEY,
+    season_id BIGINT NOT NULL REFERENCES seasons(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_division_name_per_season UNIQUE (season_id, name)
+);
+
+CREATE INDEX idx_divisions_season_id ON divisions(season_id);
+
+-- Returns the id of the current season, or NULL if none is set.
+-- Used as the default season for new teams and players.
+CREATE OR REPLACE FUNCTION current_season_id()
+RETURNS BIGINT AS $$
+    SELECT id FROM seasons WHERE is_current;
+$$ LANGUAGE sql STABLE;
+
+INSERT INTO seasons (name, start_date, end_date, is_current)
+SELECT 'Season ' || EXTRACT(YEAR FROM start_date)::text, start_date, GREATEST(end_date, start_date), TRUE
+FROM (
+    SELECT COALESCE(MIN(game_time)::date, CURRENT_DATE) AS start_date,
+           COALESCE(MAX(game_time)::date, CURRENT_DATE) AS end_date
+    FROM games
+) bounds;
+
+---------------------------------------------------
+-- Teams belong to a season and optionally a division. Seasons with teams
+-- or players can't be deleted, past seasons stay as history.
+---------------------------------------------------
+ALTER TABLE teams ADD COLUMN season_id BIGINT REFERENCES seasons(id) ON DELETE RESTRICT DEFAULT current_season_id();
+ALTER TABLE teams ADD COLUMN division_id BIGINT REFERENCES divisions(id) ON DELETE SET NULL;
+
+UPDATE teams SET season_id = current_season_id();
+ALTER TABLE teams ALTER COLUMN season_id SET NOT NULL;
+
+-- Team names only need to be unique within a season
+ALTER TABLE teams DROP CONSTRAINT teams_name_key;
+ALTER TABLE teams ADD CONSTRAINT unique_team_name_per_season UNIQUE (season_id, name);
+
+CREATE INDEX idx_teams_season_id ON teams(season_id);
+CREATE INDEX idx_teams_division_id ON teams(division_id);
+
+CREATE OR REPLACE FUNCTION validate_division_in_season(p_division_id BIGINT, p_season_id BIGINT)
+RETURNS BOOLEAN AS $$
+BEGIN
+    IF p_division_id IS NULL THEN
+        RETURN TRUE;
+    END IF;
+
+    RETURN EXISTS (
+        SELECT 1 FROM divisions WHERE id = p_division_id AND season_id = p_season_id
+    );
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+ALTER TABLE teams
+ADD CONSTRAINT team_division_must_be_in_season
+CHECK (validate_division_in_season(division_id, season_id));
+
+---------------------------------------------------
+-- Players register once per season
+---------------------------------------------------
+ALTER TABLE players ADD COLUMN season_id BIGINT REFERENCES seasons(id) ON DELETE RESTRICT DEFAULT current_season_id();
+
+UPDATE players SET season_id = current_season_id();
+ALTER TABLE players ALTER COLUMN season_id SET NOT NULL;
+
+ALTER TABLE players DROP CONSTRAINT players_user_id_key;
+ALTER TABLE players ADD CONSTRAINT unique_player_per_season UNIQUE (user_id, season_id);
+
+CREATE INDEX idx_players_season_id ON players(season_id);
+
+CREATE OR REPLACE FUNCTION validate_team_in_season(p_team_id BIGINT, p_season_id BIGINT)
+RETURNS BOOLEAN AS $$
+BEGIN
+    IF p_team_id IS NULL THEN
+        RETURN TRUE;
+    END IF;
+
+    RETURN EXISTS (
+        SELECT 1 FROM teams WHERE id = p_team_id AND season_id = p_season_id
+    );
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+ALTER TABLE players
+ADD CONSTRAINT player_team_must_be_in_season
+CHECK (validate_team_in_season(team_id, season_id));
+
+---------------------------------------------------
+-- Games can only be played between teams of the same season
+---------------------------------------------------
+CREATE OR REPLACE FUNCTION validate_game_teams_same_season(p_home_team_id BIGINT, p_away_team_id BIGINT)
+RETURNS BOOLEAN AS $$
+BEGIN
+    RETURN (SELECT season_id FROM teams WHERE id = p_home_team_id)
+         = (SELECT season_id FROM teams WHERE id = p_away_team_id);
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+ALTER TABLE games
+ADD CONSTRAINT game_teams_must_share_season
+CHECK (validate_game_teams_same_season(home_team_id, away_team_id));
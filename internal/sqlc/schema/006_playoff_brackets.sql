@@ -0,0 +1,35 @@
+-- Migration: Playoff brackets
+-- A bracket is a single elimination tournament within a season, seeded from
+-- the standings. Each match links to the game it is played in and to the
+-- match its winner advances to.
+
+CREATE TABLE brackets (
+    id BIGSERIAL PRIMARY KEY,
+    season_id BIGINT NOT NULL REFERENCES seasons(id) ON DELETE CASCADE,
+    division_id BIGINT REFERENCES divisions(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_bracket_name_per_season UNIQUE (season_id, name)
+);
+
+CREATE TABLE bracket_matches (
+    id BIGSERIAL PRIMARY KEY,
+    bracket_id BIGINT NOT NULL REFERENCES brackets(id) ON DELETE CASCADE,
+    round INT NOT NULL CHECK (round >= 1),
+    position INT NOT NULL CHECK (position >= 1),
+    home_seed INT,
+    away_seed INT,
+    home_team_id BIGINT REFERENCES teams(id) ON DELETE SET NULL,
+    away_team_id BIGINT REFERENCES teams(id) ON DELETE SET NULL,
+    game_id BIGINT UNIQUE REFERENCES games(id) ON DELETE SET NULL,
+    winner_team_id BIGINT REFERENCES teams(id) ON DELETE SET NULL,
+    next_match_id BIGINT REFERENCES bracket_matches(id) ON DELETE SET NULL,
+    next_match_slot TEXT CHECK (next_match_slot IN ('home', 'away')),
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_bracket_position UNIQUE (bracket_id, round, position),
+    CONSTRAINT next_match_needs_slot CHECK ((next_match_id IS NULL) = (next_match_slot IS NULL))
+);
+
+CREATE INDEX idx_bracket_matches_bracket_id ON bracket_matches(bracket_id);
@@ -0,0 +1,27 @@
+-- Migration: Roles and scoped permissions
+-- Besides normal users and admins, users can be captains, scorekeepers,
+-- treasurers or referees. What each role may do is defined in the auth package.
+-- Captains only manage the teams they captain, and scorekeepers and referees
+-- only the games they are assigned to.
+
+ALTER TABLE users DROP CONSTRAINT users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check
+    CHECK (role IN ('normal', 'admin', 'captain', 'scorekeeper', 'treasurer', 'referee'));
+
+CREATE TABLE team_captains (
+    team_id BIGINT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX idx_team_captains_user_id ON team_captains(user_id);
+
+CREATE TABLE game_assignments (
+    game_id BIGINT NOT NULL REFERENCES games(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (game_id, user_id)
+);
+
+CREATE INDEX idx_game_assignments_user_id ON game_assignments(user_id);
@@ -0,0 +1,23 @@
+-- Migration: Stripe webhooks
+-- Payment status is kept in sync with Stripe through webhooks. Every event is
+-- recorded by its Stripe id so a redelivered event is only applied once.
+
+ALTER TABLE payments DROP CONSTRAINT payments_status_check;
+ALTER TABLE payments ADD CONSTRAINT payments_status_check
+    CHECK (status IN ('completed', 'pending', 'failed', 'refunded'));
+
+-- Total refunded so far. A payment is only marked refunded once it is refunded
+-- in full, partial refunds leave it completed.
+ALTER TABLE payments ADD COLUMN amount_refunded DECIMAL(10, 2) NOT NULL DEFAULT 0.00;
+
+CREATE TABLE webhook_events (
+    id BIGSERIAL PRIMARY KEY,
+    provider TEXT NOT NULL,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    outcome TEXT NOT NULL DEFAULT 'processed' CHECK (outcome IN ('processed', 'ignored')),
+    note TEXT NOT NULL DEFAULT '',
+    received_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_webhook_event UNIQUE (provider, event_id)
+);
@@ -0,0 +1,10 @@
+-- Migration: Hosted checkout sessions
+-- Players pay their registration fee through a provider's hosted checkout.
+-- The payment is recorded as pending under the checkout session id when the
+-- session is created, and completed by the provider's webhook. The payment
+-- intent id is filled in once known, refunds are reported against it.
+
+ALTER TABLE payments ADD COLUMN provider TEXT NOT NULL DEFAULT 'stripe';
+ALTER TABLE payments ADD COLUMN payment_intent_id TEXT UNIQUE;
+ALTER TABLE payments ADD COLUMN checkout_url TEXT;
+ALTER TABLE payments ADD COLUMN checkout_expires_at TIMESTAMP WITHOUT TIME ZONE;
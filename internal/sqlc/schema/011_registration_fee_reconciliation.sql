@@ -0,0 +1,36 @@
+-- Migration: Registration fee reconciliation
+-- A player's registration_fee_due and is_fully_registered are derived from
+-- their payments instead of being entered by hand. The fee comes from the
+-- season, a division or the player can override it. The balance is the fee
+-- less completed payments net of refunds, and is recomputed whenever a
+-- payment changes.
+
+ALTER TABLE seasons ADD COLUMN registration_fee DECIMAL(10, 2) NOT NULL DEFAULT 0.00
+    CONSTRAINT season_fee_not_negative CHECK (registration_fee >= 0);
+ALTER TABLE divisions ADD COLUMN registration_fee DECIMAL(10, 2)
+    CONSTRAINT division_fee_not_negative CHECK (registration_fee >= 0);
+
+-- A player's own fee, overriding the division's and season's. NULL when
+-- theirs applies.
+ALTER TABLE players ADD COLUMN registration_fee DECIMAL(10, 2) CHECK (registration_fee >= 0);
+
+-- Existing players keep the fee implied by what they have paid plus what they
+-- still owe, so reconciling leaves their balance as it is.
+UPDATE players p
+SET registration_fee = GREATEST(p.registration_fee_due + COALESCE((
+    SELECT SUM(py.amount - py.amount_refunded)
+    FROM payments py
+    WHERE py.player_id = p.id AND py.status IN ('completed', 'refunded')
+), 0), 0);
+
+-- Existing seasons have no fee schedule. Players registering from now on pay
+-- the largest fee any existing player of the season was charged, until it is
+-- changed.
+UPDATE seasons s
+SET registration_fee = f.fee
+FROM (
+    SELECT season_id, MAX(registration_fee) AS fee
+    FROM players
+    GROUP BY season_id
+) f
+WHERE f.season_id = s.id;
@@ -0,0 +1,25 @@
+-- Migration: Refunds
+-- Each refund of a payment is recorded with its amount, reason and status,
+-- instead of only the running total on the payment. A payment can be refunded
+-- in several parts up to its amount. payments.amount_refunded is kept as the
+-- total of its succeeded refunds.
+
+CREATE TABLE refunds (
+    id BIGSERIAL PRIMARY KEY,
+    payment_id BIGINT NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+    amount DECIMAL(10, 2) NOT NULL CONSTRAINT refund_amount_positive CHECK (amount > 0),
+    reason TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    provider_refund_id TEXT UNIQUE, -- Set once the provider has accepted the refund
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL, -- NULL when issued outside the API
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_refunds_payment_id ON refunds(payment_id);
+
+-- Refunds applied from webhooks before this migration only left a total
+INSERT INTO refunds (payment_id, amount, reason, status)
+SELECT id, amount_refunded, 'Recorded before refunds were tracked', 'succeeded'
+FROM payments
+WHERE amount_refunded > 0;
@@ -0,0 +1,75 @@
+-- Migration: Fee schedules, discounts and promo codes
+-- A player's registration fee is assessed when they register or are
+-- re-activated. It starts from the division's or season's fee and is adjusted
+-- by the season's fee schedule: an early-bird discount, a late fee, and
+-- discounts for returning players and siblings, then by a promo code. The
+-- breakdown is stored, and the total is what reconciliation charges the player.
+
+CREATE TABLE fee_schedules (
+    id BIGSERIAL PRIMARY KEY,
+    season_id BIGINT UNIQUE NOT NULL REFERENCES seasons(id) ON DELETE CASCADE,
+    early_bird_deadline DATE, -- Registrations on or before this date get the early-bird discount
+    early_bird_discount DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+    late_deadline DATE, -- Registrations after this date pay the late fee
+    late_fee DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+    returning_player_discount DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+    sibling_discount DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fee_schedule_amounts_not_negative CHECK (
+        early_bird_discount >= 0 AND late_fee >= 0
+        AND returning_player_discount >= 0 AND sibling_discount >= 0
+    ),
+    CONSTRAINT fee_schedule_deadlines_ordered CHECK (late_deadline >= early_bird_deadline)
+);
+
+CREATE TABLE promo_codes (
+    id BIGSERIAL PRIMARY KEY,
+    season_id BIGINT REFERENCES seasons(id) ON DELETE CASCADE, -- NULL for codes valid in any season
+    code TEXT UNIQUE NOT NULL CHECK (code = UPPER(code)),
+    description TEXT NOT NULL DEFAULT '',
+    discount_type TEXT NOT NULL CHECK (discount_type IN ('amount', 'percent')),
+    discount_value DECIMAL(10, 2) NOT NULL,
+    max_redemptions INT, -- NULL for unlimited
+    redemptions INT NOT NULL DEFAULT 0,
+    valid_from DATE,
+    valid_until DATE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT promo_code_discount_valid CHECK (
+        discount_value > 0 AND (discount_type = 'amount' OR discount_value <= 100)
+    ),
+    CONSTRAINT promo_code_redemption_limit CHECK (max_redemptions IS NULL OR redemptions <= max_redemptions),
+    CONSTRAINT promo_code_dates_ordered CHECK (valid_until >= valid_from)
+);
+
+-- A player redeems at most one promo code, which is applied again whenever
+-- their fee is re-assessed
+CREATE TABLE promo_code_redemptions (
+    id BIGSERIAL PRIMARY KEY,
+    promo_code_id BIGINT NOT NULL REFERENCES promo_codes(id) ON DELETE RESTRICT,
+    player_id BIGINT UNIQUE NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    redeemed_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_promo_code_redemptions_promo_code_id ON promo_code_redemptions(promo_code_id);
+
+-- The player whose registration makes this one eligible for the sibling discount
+ALTER TABLE players ADD COLUMN sibling_player_id BIGINT REFERENCES players(id) ON DELETE SET NULL;
+
+-- The day the player registered or was last re-activated. Their fee is
+-- assessed as of this day, however often it is assessed again.
+ALTER TABLE players ADD COLUMN fee_assessed_on DATE NOT NULL DEFAULT CURRENT_DATE;
+UPDATE players SET fee_assessed_on = created_at::date;
+
+CREATE TABLE registration_fee_items (
+    id BIGSERIAL PRIMARY KEY,
+    player_id BIGINT NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('base', 'early_bird', 'late_fee', 'returning_player', 'sibling', 'promo_code')),
+    description TEXT NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL, -- Negative for discounts
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_registration_fee_items_player_id ON registration_fee_items(player_id);
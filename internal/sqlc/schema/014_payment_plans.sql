@@ -0,0 +1,37 @@
+-- Migration: Payment plans
+-- A player who cannot pay their fee up front is put on a plan of installments
+-- with due dates. Payments made after the plan starts pay off the installments
+-- in due date order, an unpaid installment past its due date is overdue. The
+-- player is only fully registered once the whole fee, and so the plan, is paid.
+
+CREATE TABLE payment_plans (
+    id BIGSERIAL PRIMARY KEY,
+    player_id BIGINT NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'completed', 'cancelled')),
+    total_amount DECIMAL(10, 2) NOT NULL CHECK (total_amount > 0),
+    -- Net amount the player had paid when the plan started, which does not
+    -- count towards the installments
+    paid_before_plan DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+    notes TEXT NOT NULL DEFAULT '',
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_payment_plans_player_id ON payment_plans(player_id);
+CREATE UNIQUE INDEX unique_active_payment_plan_per_player ON payment_plans(player_id) WHERE status = 'active';
+
+CREATE TABLE payment_plan_installments (
+    id BIGSERIAL PRIMARY KEY,
+    plan_id BIGINT NOT NULL REFERENCES payment_plans(id) ON DELETE CASCADE,
+    installment_number INT NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount > 0),
+    due_date DATE NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'paid', 'overdue')),
+    paid_at TIMESTAMP WITHOUT TIME ZONE,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_installment_number_per_plan UNIQUE (plan_id, installment_number)
+);
+
+CREATE INDEX idx_payment_plan_installments_due_date ON payment_plan_installments(due_date) WHERE status = 'pending';
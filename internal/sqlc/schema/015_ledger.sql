@@ -0,0 +1,157 @@
+-- Migration: Double-entry ledger
+-- Every change to what a player owes is posted as a balanced transaction of
+-- ledger entries, debits positive and credits negative. Each player has their
+-- own receivable account, its balance is their registration fee due. Charges,
+-- discounts, payments and refunds are posted from the fee items, payments and
+-- refunds tables whenever a player is reconciled, write-offs are posted by
+-- hand. The ledger is append-only, mistakes are corrected by posting a
+-- transaction with the opposite amount.
+
+CREATE TABLE ledger_transactions (
+    id BIGSERIAL PRIMARY KEY,
+    kind TEXT NOT NULL CHECK (kind IN ('charge', 'discount', 'payment', 'refund', 'write_off')),
+    -- Not foreign keys, the books keep the ids of deleted players, seasons,
+    -- payments and users
+    player_id BIGINT NOT NULL,
+    season_id BIGINT NOT NULL,
+    payment_id BIGINT,
+    refund_id BIGINT,
+    -- Negative when correcting an earlier transaction of the same kind
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount <> 0),
+    description TEXT NOT NULL,
+    created_by BIGINT,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_ledger_transactions_player_id ON ledger_transactions(player_id);
+CREATE INDEX idx_ledger_transactions_season_id ON ledger_transactions(season_id);
+CREATE INDEX idx_ledger_transactions_payment_id ON ledger_transactions(payment_id);
+CREATE INDEX idx_ledger_transactions_refund_id ON ledger_transactions(refund_id);
+
+CREATE TABLE ledger_entries (
+    id BIGSERIAL PRIMARY KEY,
+    transaction_id BIGINT NOT NULL REFERENCES ledger_transactions(id),
+    account TEXT NOT NULL CHECK (account IN ('cash', 'receivable', 'revenue', 'discounts', 'write_offs')),
+    -- The player whose receivable account the entry is in
+    player_id BIGINT,
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount <> 0),
+    CONSTRAINT receivable_entry_has_player CHECK ((account = 'receivable') = (player_id IS NOT NULL))
+);
+
+CREATE INDEX idx_ledger_entries_transaction_id ON ledger_entries(transaction_id);
+CREATE INDEX idx_ledger_entries_player_id ON ledger_entries(player_id) WHERE account = 'receivable';
+
+CREATE OR REPLACE FUNCTION ledger_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger is append-only, post a correcting transaction instead';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ledger_transactions_append_only
+BEFORE UPDATE OR DELETE ON ledger_transactions
+FOR EACH ROW EXECUTE FUNCTION ledger_append_only();
+
+CREATE TRIGGER ledger_entries_append_only
+BEFORE UPDATE OR DELETE ON ledger_entries
+FOR EACH ROW EXECUTE FUNCTION ledger_append_only();
+
+-- Checked at commit, once all of a transaction's entries are in
+CREATE OR REPLACE FUNCTION validate_ledger_transaction_balanced()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (SELECT SUM(amount) FROM ledger_entries WHERE transaction_id = NEW.transaction_id) <> 0 THEN
+        RAISE EXCEPTION 'ledger transaction % does not balance', NEW.transaction_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER ledger_entries_balanced
+AFTER INSERT ON ledger_entries
+DEFERRABLE INITIALLY DEFERRED
+FOR EACH ROW EXECUTE FUNCTION validate_ledger_transaction_balanced();
+
+-- The transactions that would bring the ledger in line with a player's, or a
+-- season's players', fee items, payments and refunds. Payments and refunds
+-- are matched by id, charges and discounts by player. Posted payments and
+-- refunds that no longer exist are reversed. Reconciling posts them, reports
+-- preview them.
+CREATE OR REPLACE FUNCTION unposted_ledger_changes(p_player_id BIGINT, p_season_id BIGINT)
+RETURNS TABLE (player_id BIGINT, season_id BIGINT, payment_id BIGINT, refund_id BIGINT,
+               kind TEXT, amount NUMERIC, description TEXT) AS $$
+    WITH scoped AS (
+        SELECT p.id, p.season_id, p.registration_fee, d.registration_fee AS division_fee, s.registration_fee AS season_fee
+        FROM players p
+        INNER JOIN seasons s ON s.id = p.season_id
+        LEFT JOIN teams t ON t.id = p.team_id
+        LEFT JOIN divisions d ON d.id = t.division_id
+        WHERE (p_player_id IS NULL OR p.id = p_player_id)
+          AND (p_season_id IS NULL OR p.season_id = p_season_id)
+    ), expected AS (
+        SELECT sp.id AS player_id, sp.season_id, NULL::bigint AS payment_id, NULL::bigint AS refund_id, 'charge' AS kind,
+               COALESCE(fi.charges, sp.registration_fee, sp.division_fee, sp.season_fee) AS amount,
+               'Registration fee' AS description
+        FROM scoped sp
+        LEFT JOIN (
+            SELECT player_id, SUM(amount) FILTER (WHERE amount > 0) AS charges
+            FROM registration_fee_items
+            GROUP BY player_id
+        ) fi ON fi.player_id = sp.id
+        UNION ALL
+        SELECT sp.id, sp.season_id, NULL, NULL, 'discount', COALESCE(-SUM(fi.amount), 0), 'Registration discounts'
+        FROM scoped sp
+        LEFT JOIN registration_fee_items fi ON fi.player_id = sp.id AND fi.amount < 0
+        GROUP BY sp.id, sp.season_id
+        UNION ALL
+        SELECT sp.id, sp.season_id, py.id, NULL, 'payment',
+               CASE WHEN py.status IN ('completed', 'refunded') THEN py.amount ELSE 0 END,
+               'Payment ' || py.stripe_id
+        FROM scoped sp
+        INNER JOIN payments py ON py.player_id = sp.id
+        UNION ALL
+        SELECT sp.id, sp.season_id, py.id, r.id, 'refund',
+               CASE WHEN r.status = 'succeeded' THEN r.amount ELSE 0 END,
+               'Refund of payment ' || py.stripe_id
+        FROM scoped sp
+        INNER JOIN payments py ON py.player_id = sp.id
+        INNER JOIN refunds r ON r.payment_id = py.id
+    ), posted AS (
+        SELECT lt.player_id, lt.season_id, lt.kind, lt.payment_id, lt.refund_id, SUM(lt.amount) AS amount
+        FROM ledger_transactions lt
+        INNER JOIN scoped sp ON sp.id = lt.player_id
+        WHERE lt.kind <> 'write_off'
+        GROUP BY lt.player_id, lt.season_id, lt.kind, lt.payment_id, lt.refund_id
+    )
+    SELECT COALESCE(e.player_id, po.player_id) AS player_id,
+           COALESCE(e.season_id, po.season_id) AS season_id,
+           COALESCE(e.payment_id, po.payment_id) AS payment_id,
+           COALESCE(e.refund_id, po.refund_id) AS refund_id,
+           COALESCE(e.kind, po.kind)::text AS kind,
+           (COALESCE(e.amount, 0) - COALESCE(po.amount, 0))::numeric AS amount,
+           CASE
+               WHEN e.description IS NULL THEN 'Reversal of deleted ' || po.kind
+               WHEN po.amount IS NULL THEN e.description
+               ELSE e.description || ' correction'
+           END::text AS description
+    FROM expected e
+    FULL JOIN posted po ON po.player_id = e.player_id AND po.kind = e.kind
+        AND COALESCE(po.payment_id, 0) = COALESCE(e.payment_id, 0)
+        AND COALESCE(po.refund_id, 0) = COALESCE(e.refund_id, 0)
+    WHERE COALESCE(e.amount, 0) - COALESCE(po.amount, 0) <> 0;
+$$ LANGUAGE sql STABLE;
+
+-- Opening transactions for existing players, with nothing in the ledger yet
+WITH posted AS (
+    INSERT INTO ledger_transactions (kind, player_id, season_id, payment_id, refund_id, amount, description)
+    SELECT kind, player_id, season_id, payment_id, refund_id, amount, description
+    FROM unposted_ledger_changes(NULL, NULL)
+    RETURNING id, kind, player_id, amount
+)
+INSERT INTO ledger_entries (transaction_id, account, player_id, amount)
+SELECT id, 'receivable', player_id, CASE WHEN kind IN ('charge', 'refund') THEN amount ELSE -amount END
+FROM posted
+UNION ALL
+SELECT id, CASE kind WHEN 'charge' THEN 'revenue' WHEN 'discount' THEN 'discounts' ELSE 'cash' END, NULL,
+       CASE WHEN kind IN ('charge', 'refund') THEN -amount ELSE amount END
+FROM posted;
@@ -0,0 +1,24 @@
+-- Migration: Payment reminders
+-- Players who still owe their registration fee are sent escalating reminders
+-- by a background job, a set number of days after they registered. Users can
+-- opt out of the emails. Every reminder is recorded. Optionally players who
+-- have not paid some days after registering are locked out of game rosters
+-- until they pay or are put on a payment plan.
+
+ALTER TABLE users ADD COLUMN payment_reminders_opt_out BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE players ADD COLUMN roster_locked_at TIMESTAMP WITHOUT TIME ZONE;
+
+CREATE TABLE payment_reminders (
+    id BIGSERIAL PRIMARY KEY,
+    player_id BIGINT NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    -- 1 for the first reminder, counting up to the final notice
+    stage INT NOT NULL CHECK (stage > 0),
+    amount_due DECIMAL(10, 2) NOT NULL,
+    sent_to TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('sent', 'failed')),
+    error TEXT,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_payment_reminders_player_id ON payment_reminders(player_id);
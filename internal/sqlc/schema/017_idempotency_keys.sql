@@ -0,0 +1,22 @@
+-- Migration: Idempotency keys
+-- Requests that move money can carry an Idempotency-Key header. The first
+-- request with a key claims it and stores its response once it finishes, and
+-- repeats of the request until the key expires replay that response instead of
+-- running again. Keys belong to the user who sent them, so one user can never
+-- be replayed another's response.
+
+CREATE TABLE idempotency_keys (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    idempotency_key TEXT NOT NULL,
+    method TEXT NOT NULL,
+    path TEXT NOT NULL,
+    request_hash TEXT NOT NULL, -- SHA-256 of the method, path and body, to spot a key reused for another request
+    status_code INT, -- NULL while the first request is in progress
+    content_type TEXT NOT NULL DEFAULT '',
+    response_body BYTEA NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    PRIMARY KEY (user_id, idempotency_key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
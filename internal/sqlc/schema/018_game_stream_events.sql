@@ -0,0 +1,112 @@
+-- Migration: Live game events
+-- Score changes, status changes and box score updates are recorded as events
+-- by triggers, whichever request made them, for fans following games over
+-- Server-Sent Events. The id is the SSE event id, clients that reconnect send
+-- the last one they saw to catch up on what they missed. New events are
+-- announced on the game_stream_events channel, so every API instance streams
+-- them. Events are only kept for a few days, long enough to reconnect.
+
+CREATE TABLE game_stream_events (
+    id BIGSERIAL PRIMARY KEY,
+    game_id BIGINT NOT NULL, -- No foreign key, deleting a game deletes its box score and records that
+    game_date DATE NOT NULL, -- The day the game is scheduled, for game day streams
+    kind TEXT NOT NULL CHECK (kind IN ('score', 'status', 'box_score')),
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_game_stream_events_game_id ON game_stream_events(game_id, id);
+CREATE INDEX idx_game_stream_events_game_date ON game_stream_events(game_date, id);
+CREATE INDEX idx_game_stream_events_created_at ON game_stream_events(created_at);
+
+-- Ids must follow commit order, or a client that has seen event 11 would
+-- never be sent an event 10 committed after it. Transactions recording events
+-- take turns, and the id is only handed out once it is this one's turn, so it
+-- is larger than the id of every event already committed.
+CREATE OR REPLACE FUNCTION order_game_stream_event()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('game_stream_events'));
+    NEW.id := nextval(pg_get_serial_sequence('game_stream_events', 'id'));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER game_stream_events_order
+BEFORE INSERT ON game_stream_events
+FOR EACH ROW EXECUTE FUNCTION order_game_stream_event();
+
+-- Delivered when the transaction that recorded the event commits
+CREATE OR REPLACE FUNCTION notify_game_stream_event()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('game_stream_events', NEW.id::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER game_stream_events_notify
+AFTER INSERT ON game_stream_events
+FOR EACH ROW EXECUTE FUNCTION notify_game_stream_event();
+
+CREATE OR REPLACE FUNCTION record_game_stream_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    game JSONB := jsonb_build_object(
+        'gameId', NEW.id,
+        'homeTeamId', NEW.home_team_id,
+        'awayTeamId', NEW.away_team_id,
+        'homeScore', NEW.home_score,
+        'awayScore', NEW.away_score,
+        'status', NEW.status
+    );
+BEGIN
+    IF NEW.status IS DISTINCT FROM OLD.status THEN
+        INSERT INTO game_stream_events (game_id, game_date, kind, payload)
+        VALUES (NEW.id, NEW.game_time::date, 'status', game || jsonb_build_object('previousStatus', OLD.status));
+    END IF;
+    IF NEW.home_score <> OLD.home_score OR NEW.away_score <> OLD.away_score THEN
+        INSERT INTO game_stream_events (game_id, game_date, kind, payload)
+        VALUES (NEW.id, NEW.game_time::date, 'score', game);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER games_stream_events
+AFTER UPDATE ON games
+FOR EACH ROW EXECUTE FUNCTION record_game_stream_event();
+
+-- A deleted line is sent with deleted set, so clients can drop the player
+CREATE OR REPLACE FUNCTION record_box_score_stream_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    line game_details%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        line := OLD;
+    ELSE
+        line := NEW;
+    END IF;
+
+    INSERT INTO game_stream_events (game_id, game_date, kind, payload)
+    SELECT g.id, g.game_time::date, 'box_score', jsonb_build_object(
+        'gameId', g.id,
+        'playerId', line.player_id,
+        'teamId', p.team_id,
+        'firstName', u.first_name,
+        'lastName', u.last_name,
+        'score', line.score,
+        'deleted', TG_OP = 'DELETE'
+    )
+    FROM games g
+    LEFT JOIN players p ON p.id = line.player_id
+    LEFT JOIN users u ON u.id = p.user_id
+    WHERE g.id = line.game_id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER game_details_stream_events
+AFTER INSERT OR UPDATE OR DELETE ON game_details
+FOR EACH ROW EXECUTE FUNCTION record_box_score_stream_event();
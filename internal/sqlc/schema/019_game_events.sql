@@ -0,0 +1,149 @@
+-- Migration: Play-by-play game events
+-- Scorekeepers record a game as it happens: scoring plays, fouls, timeouts,
+-- substitutions and the start and end of each period, each at a game clock
+-- value (seconds left in the period). The log is append-only, a mistake is
+-- corrected by recording an undo event for it. Once a game has events its
+-- score and box score are derived from them, the triggers below reject any
+-- change that would make them disagree with the log.
+
+CREATE TABLE game_events (
+    id BIGSERIAL PRIMARY KEY,
+    game_id BIGINT NOT NULL REFERENCES games(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('score', 'foul', 'timeout', 'substitution', 'period_start', 'period_end', 'undo')),
+    period INT NOT NULL CHECK (period > 0),
+    clock_seconds INT NOT NULL CHECK (clock_seconds >= 0),
+    team_id BIGINT REFERENCES teams(id) ON DELETE CASCADE,
+    player_id BIGINT REFERENCES players(id) ON DELETE CASCADE, -- The scorer, fouler or player subbed in
+    player_out_id BIGINT REFERENCES players(id) ON DELETE CASCADE, -- The player subbed out
+    points INT CHECK (points IN (1, 2, 3)),
+    undoes_event_id BIGINT UNIQUE REFERENCES game_events(id) ON DELETE CASCADE, -- An event can only be undone once
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT game_event_fields CHECK (
+        (points IS NOT NULL) = (kind = 'score')
+        AND (player_out_id IS NOT NULL) = (kind = 'substitution')
+        AND (undoes_event_id IS NOT NULL) = (kind = 'undo')
+        AND (player_id IS NOT NULL) = (kind IN ('score', 'foul', 'substitution'))
+        AND (team_id IS NOT NULL) = (kind IN ('score', 'foul', 'timeout', 'substitution'))
+    )
+);
+
+CREATE INDEX idx_game_events_game_id ON game_events(game_id, period, clock_seconds DESC, id);
+CREATE INDEX idx_game_events_player_id ON game_events(player_id);
+
+-- An event's team must play in the game and its players must be on that team.
+-- A trigger rather than a CHECK, since it reads games and players. Events
+-- can't be updated, so only inserts are checked.
+CREATE OR REPLACE FUNCTION validate_game_event_team()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.team_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM games
+        WHERE id = NEW.game_id AND NEW.team_id IN (home_team_id, away_team_id)
+    ) OR EXISTS (
+        SELECT 1 FROM unnest(ARRAY[NEW.player_id, NEW.player_out_id]) AS player(id)
+        WHERE player.id IS NOT NULL
+          AND NOT EXISTS (SELECT 1 FROM players WHERE id = player.id AND team_id = NEW.team_id)
+    ) THEN
+        RAISE EXCEPTION 'game event team % must play in game % and its players must be on it', NEW.team_id, NEW.game_id
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'game_event_team_must_play_in_game';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER game_event_team_must_play_in_game
+BEFORE INSERT ON game_events
+FOR EACH ROW EXECUTE FUNCTION validate_game_event_team();
+
+-- Events are only removed along with their game, team or player
+CREATE OR REPLACE FUNCTION prevent_game_event_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF pg_trigger_depth() > 1 THEN
+        IF TG_OP = 'DELETE' THEN
+            RETURN OLD;
+        END IF;
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'game events are append-only, record an undo event instead'
+        USING ERRCODE = 'check_violation', CONSTRAINT = 'game_events_append_only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER game_events_append_only
+BEFORE UPDATE OR DELETE ON game_events
+FOR EACH ROW EXECUTE FUNCTION prevent_game_event_changes();
+
+-- Points scored in a game by a team, a player, or both, from the scoring
+-- events that have not been undone
+CREATE OR REPLACE FUNCTION game_event_points(p_game_id BIGINT, p_team_id BIGINT, p_player_id BIGINT)
+RETURNS INT AS $$
+    SELECT COALESCE(SUM(e.points), 0)::int
+    FROM game_events e
+    WHERE e.game_id = p_game_id
+      AND e.kind = 'score'
+      AND (p_team_id IS NULL OR e.team_id = p_team_id)
+      AND (p_player_id IS NULL OR e.player_id = p_player_id)
+      AND NOT EXISTS (SELECT 1 FROM game_events u WHERE u.undoes_event_id = e.id);
+$$ LANGUAGE sql STABLE;
+
+-- Only changes to the score are checked, so the rest of a game can still be
+-- edited
+CREATE OR REPLACE FUNCTION validate_game_score_from_events()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (NEW.home_score, NEW.away_score) IS DISTINCT FROM (OLD.home_score, OLD.away_score)
+       AND EXISTS (SELECT 1 FROM game_events WHERE game_id = NEW.id)
+       AND (NEW.home_score <> game_event_points(NEW.id, NEW.home_team_id, NULL)
+            OR NEW.away_score <> game_event_points(NEW.id, NEW.away_team_id, NULL)) THEN
+        RAISE EXCEPTION 'game % score must match its play-by-play', NEW.id
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'game_score_from_events';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER games_score_from_events
+BEFORE UPDATE ON games
+FOR EACH ROW EXECUTE FUNCTION validate_game_score_from_events();
+
+-- Box score lines can still be added for players who didn't score, and
+-- removed along with their game or player
+CREATE OR REPLACE FUNCTION validate_game_details_from_events()
+RETURNS TRIGGER AS $$
+DECLARE
+    line game_details%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        IF pg_trigger_depth() > 1 THEN
+            RETURN OLD;
+        END IF;
+        line := OLD;
+        line.score := 0;
+    ELSIF TG_OP = 'UPDATE' AND NEW.score = OLD.score THEN
+        RETURN NEW;
+    ELSE
+        line := NEW;
+    END IF;
+
+    IF EXISTS (SELECT 1 FROM game_events WHERE game_id = line.game_id)
+       AND line.score <> game_event_points(line.game_id, NULL, line.player_id) THEN
+        RAISE EXCEPTION 'player % score in game % must match its play-by-play', line.player_id, line.game_id
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'game_details_score_from_events';
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER game_details_score_from_events
+BEFORE INSERT OR UPDATE OR DELETE ON game_details
+FOR EACH ROW EXECUTE FUNCTION validate_game_details_from_events();
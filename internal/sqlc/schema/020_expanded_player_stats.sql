@@ -0,0 +1,136 @@
+-- Migration: Expanded player stats
+-- Box score lines record rebounds, assists, steals, blocks, turnovers, fouls
+-- and shooting as well as points. Field goals include three pointers. When
+-- shots are recorded the score must add up from them, lines with only a score
+-- stay valid.
+
+ALTER TABLE game_details
+ADD COLUMN rebounds INT NOT NULL DEFAULT 0,
+ADD COLUMN assists INT NOT NULL DEFAULT 0,
+ADD COLUMN steals INT NOT NULL DEFAULT 0,
+ADD COLUMN blocks INT NOT NULL DEFAULT 0,
+ADD COLUMN turnovers INT NOT NULL DEFAULT 0,
+ADD COLUMN fouls INT NOT NULL DEFAULT 0,
+ADD COLUMN field_goals_made INT NOT NULL DEFAULT 0,
+ADD COLUMN field_goals_attempted INT NOT NULL DEFAULT 0,
+ADD COLUMN three_pointers_made INT NOT NULL DEFAULT 0,
+ADD COLUMN three_pointers_attempted INT NOT NULL DEFAULT 0,
+ADD COLUMN free_throws_made INT NOT NULL DEFAULT 0,
+ADD COLUMN free_throws_attempted INT NOT NULL DEFAULT 0;
+
+ALTER TABLE game_details
+ADD CONSTRAINT stats_not_negative CHECK (
+    LEAST(rebounds, assists, steals, blocks, turnovers, fouls,
+          field_goals_made, field_goals_attempted, three_pointers_made,
+          three_pointers_attempted, free_throws_made, free_throws_attempted) >= 0
+),
+ADD CONSTRAINT field_goals_made_within_attempted CHECK (field_goals_made <= field_goals_attempted),
+ADD CONSTRAINT three_pointers_made_within_attempted CHECK (three_pointers_made <= three_pointers_attempted),
+ADD CONSTRAINT free_throws_made_within_attempted CHECK (free_throws_made <= free_throws_attempted),
+ADD CONSTRAINT three_pointers_within_field_goals CHECK (
+    three_pointers_made <= field_goals_made AND three_pointers_attempted <= field_goals_attempted
+),
+ADD CONSTRAINT score_matches_shots CHECK (
+    (field_goals_made = 0 AND free_throws_made = 0)
+    OR score = 2 * field_goals_made + three_pointers_made + free_throws_made
+);
+
+-- A player's line in a game from its play-by-play: points, fouls and made
+-- shots from the events that have not been undone. Misses aren't recorded as
+-- plays, so attempts and the other stats are entered on the box score.
+CREATE OR REPLACE FUNCTION game_event_line(p_game_id BIGINT, p_player_id BIGINT)
+RETURNS TABLE (score INT, fouls INT, field_goals_made INT, three_pointers_made INT, free_throws_made INT) AS $$
+    SELECT COALESCE(SUM(e.points) FILTER (WHERE e.kind = 'score'), 0)::int,
+           (COUNT(*) FILTER (WHERE e.kind = 'foul'))::int,
+           (COUNT(*) FILTER (WHERE e.kind = 'score' AND e.points > 1))::int,
+           (COUNT(*) FILTER (WHERE e.kind = 'score' AND e.points = 3))::int,
+           (COUNT(*) FILTER (WHERE e.kind = 'score' AND e.points = 1))::int
+    FROM game_events e
+    WHERE e.game_id = p_game_id
+      AND e.player_id = p_player_id
+      AND NOT EXISTS (SELECT 1 FROM game_events u WHERE u.undoes_event_id = e.id);
+$$ LANGUAGE sql STABLE;
+
+-- Replaces the check from 019 so fouls and made shots must match the
+-- play-by-play too. The other stats can be edited freely.
+CREATE OR REPLACE FUNCTION validate_game_details_from_events()
+RETURNS TRIGGER AS $$
+DECLARE
+    line game_details%ROWTYPE;
+    derived RECORD;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        IF pg_trigger_depth() > 1 THEN
+            RETURN OLD;
+        END IF;
+        line := OLD;
+        line.score := 0;
+        line.fouls := 0;
+        line.field_goals_made := 0;
+        line.three_pointers_made := 0;
+        line.free_throws_made := 0;
+    ELSIF TG_OP = 'UPDATE'
+          AND (NEW.score, NEW.fouls, NEW.field_goals_made, NEW.three_pointers_made, NEW.free_throws_made)
+              = (OLD.score, OLD.fouls, OLD.field_goals_made, OLD.three_pointers_made, OLD.free_throws_made) THEN
+        RETURN NEW;
+    ELSE
+        line := NEW;
+    END IF;
+
+    IF EXISTS (SELECT 1 FROM game_events WHERE game_id = line.game_id) THEN
+        SELECT * INTO derived FROM game_event_line(line.game_id, line.player_id);
+        IF (line.score, line.fouls, line.field_goals_made, line.three_pointers_made, line.free_throws_made)
+           IS DISTINCT FROM (derived.score, derived.fouls, derived.field_goals_made, derived.three_pointers_made, derived.free_throws_made) THEN
+            RAISE EXCEPTION 'player % line in game % must match its play-by-play', line.player_id, line.game_id
+                USING ERRCODE = 'check_violation', CONSTRAINT = 'game_details_score_from_events';
+        END IF;
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Replaces the box score event from 018 to send the whole line
+CREATE OR REPLACE FUNCTION record_box_score_stream_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    line game_details%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        line := OLD;
+    ELSE
+        line := NEW;
+    END IF;
+
+    INSERT INTO game_stream_events (game_id, game_date, kind, payload)
+    SELECT g.id, g.game_time::date, 'box_score', jsonb_build_object(
+        'gameId', g.id,
+        'playerId', line.player_id,
+        'teamId', p.team_id,
+        'firstName', u.first_name,
+        'lastName', u.last_name,
+        'score', line.score,
+        'rebounds', line.rebounds,
+        'assists', line.assists,
+        'steals', line.steals,
+        'blocks', line.blocks,
+        'turnovers', line.turnovers,
+        'fouls', line.fouls,
+        'fieldGoalsMade', line.field_goals_made,
+        'fieldGoalsAttempted', line.field_goals_attempted,
+        'threePointersMade', line.three_pointers_made,
+        'threePointersAttempted', line.three_pointers_attempted,
+        'freeThrowsMade', line.free_throws_made,
+        'freeThrowsAttempted', line.free_throws_attempted,
+        'deleted', TG_OP = 'DELETE'
+    )
+    FROM games g
+    LEFT JOIN players p ON p.id = line.player_id
+    LEFT JOIN users u ON u.id = p.user_id
+    WHERE g.id = line.game_id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,134 @@
+// Package stats totals players' box score lines into summaries: games played,
+// totals, per game averages, single game highs and shooting percentages.
+package stats
+
+import "math"
+
+// Line is a player's box score line for a game, or the sum of many. Field
+// goals include three pointers.
+type Line struct {
+	Points                 int `json:"points"`
+	Rebounds               int `json:"rebounds"`
+	Assists                int `json:"assists"`
+	Steals                 int `json:"steals"`
+	Blocks                 int `json:"blocks"`
+	Turnovers              int `json:"turnovers"`
+	Fouls                  int `json:"fouls"`
+	FieldGoalsMade         int `json:"fieldGoalsMade"`
+	FieldGoalsAttempted    int `json:"fieldGoalsAttempted"`
+	ThreePointersMade      int `json:"threePointersMade"`
+	ThreePointersAttempted int `json:"threePointersAttempted"`
+	FreeThrowsMade         int `json:"freeThrowsMade"`
+	FreeThrowsAttempted    int `json:"freeThrowsAttempted"`
+}
+
+// Averages are per game averages, rounded to one decimal place
+type Averages struct {
+	Points                 float64 `json:"points"`
+	Rebounds               float64 `json:"rebounds"`
+	Assists                float64 `json:"assists"`
+	Steals                 float64 `json:"steals"`
+	Blocks                 float64 `json:"blocks"`
+	Turnovers              float64 `json:"turnovers"`
+	Fouls                  float64 `json:"fouls"`
+	FieldGoalsMade         float64 `json:"fieldGoalsMade"`
+	FieldGoalsAttempted    float64 `json:"fieldGoalsAttempted"`
+	ThreePointersMade      float64 `json:"threePointersMade"`
+	ThreePointersAttempted float64 `json:"threePointersAttempted"`
+	FreeThrowsMade         float64 `json:"freeThrowsMade"`
+	FreeThrowsAttempted    float64 `json:"freeThrowsAttempted"`
+}
+
+// Percentages are shooting percentages, rounded to one decimal place. They
+// are nil when no shots of that kind were attempted.
+type Percentages struct {
+	FieldGoals    *float64 `json:"fieldGoals"`
+	ThreePointers *float64 `json:"threePointers"`
+	FreeThrows    *float64 `json:"freeThrows"`
+}
+
+// Summary sums up the games a player played
+type Summary struct {
+	GamesPlayed int         `json:"gamesPlayed"`
+	Totals      Line        `json:"totals"`
+	Averages    Averages    `json:"averages"`
+	Highs       Line        `json:"highs"`
+	Shooting    Percentages `json:"shooting"`
+}
+
+// Add counts another game in the summary
+func (s *Summary) Add(line Line) {
+	s.GamesPlayed++
+	s.Totals = s.Totals.plus(line)
+	s.Highs = s.Highs.max(line)
+
+	t, games := s.Totals, float64(s.GamesPlayed)
+	s.Averages = Averages{
+		Points:                 round(float64(t.Points) / games),
+		Rebounds:               round(float64(t.Rebounds) / games),
+		Assists:                round(float64(t.Assists) / games),
+		Steals:                 round(float64(t.Steals) / games),
+		Blocks:                 round(float64(t.Blocks) / games),
+		Turnovers:              round(float64(t.Turnovers) / games),
+		Fouls:                  round(float64(t.Fouls) / games),
+		FieldGoalsMade:         round(float64(t.FieldGoalsMade) / games),
+		FieldGoalsAttempted:    round(float64(t.FieldGoalsAttempted) / games),
+		ThreePointersMade:      round(float64(t.ThreePointersMade) / games),
+		ThreePointersAttempted: round(float64(t.ThreePointersAttempted) / games),
+		FreeThrowsMade:         round(float64(t.FreeThrowsMade) / games),
+		FreeThrowsAttempted:    round(float64(t.FreeThrowsAttempted) / games),
+	}
+	s.Shooting = Percentages{
+		FieldGoals:    percentage(t.FieldGoalsMade, t.FieldGoalsAttempted),
+		ThreePointers: percentage(t.ThreePointersMade, t.ThreePointersAttempted),
+		FreeThrows:    percentage(t.FreeThrowsMade, t.FreeThrowsAttempted),
+	}
+}
+
+func (l Line) plus(o Line) Line {
+	return Line{
+		Points:                 l.Points + o.Points,
+		Rebounds:               l.Rebounds + o.Rebounds,
+		Assists:                l.Assists + o.Assists,
+		Steals:                 l.Steals + o.Steals,
+		Blocks:                 l.Blocks + o.Blocks,
+		Turnovers:              l.Turnovers + o.Turnovers,
+		Fouls:                  l.Fouls + o.Fouls,
+		FieldGoalsMade:         l.FieldGoalsMade + o.FieldGoalsMade,
+		FieldGoalsAttempted:    l.FieldGoalsAttempted + o.FieldGoalsAttempted,
+		ThreePointersMade:      l.ThreePointersMade + o.ThreePointersMade,
+		ThreePointersAttempted: l.ThreePointersAttempted + o.ThreePointersAttempted,
+		FreeThrowsMade:         l.FreeThrowsMade + o.FreeThrowsMade,
+		FreeThrowsAttempted:    l.FreeThrowsAttempted + o.FreeThrowsAttempted,
+	}
+}
+
+func (l Line) max(o Line) Line {
+	return Line{
+		Points:                 max(l.Points, o.Points),
+		Rebounds:               max(l.Rebounds, o.Rebounds),
+		Assists:                max(l.Assists, o.Assists),
+		Steals:                 max(l.Steals, o.Steals),
+		Blocks:                 max(l.Blocks, o.Blocks),
+		Turnovers:              max(l.Turnovers, o.Turnovers),
+		Fouls:                  max(l.Fouls, o.Fouls),
+		FieldGoalsMade:         max(l.FieldGoalsMade, o.FieldGoalsMade),
+		FieldGoalsAttempted:    max(l.FieldGoalsAttempted, o.FieldGoalsAttempted),
+		ThreePointersMade:      max(l.ThreePointersMade, o.ThreePointersMade),
+		ThreePointersAttempted: max(l.ThreePointersAttempted, o.ThreePointersAttempted),
+		FreeThrowsMade:         max(l.FreeThrowsMade, o.FreeThrowsMade),
+		FreeThrowsAttempted:    max(l.FreeThrowsAttempted, o.FreeThrowsAttempted),
+	}
+}
+
+func percentage(made, attempted int) *float64 {
+	if attempted == 0 {
+		return nil
+	}
+	p := round(100 * float64(made) / float64(attempted))
+	return &p
+}
+
+func round(f float64) float64 {
+	return math.Round(f*10) / 10
+}
@@ -0,0 +1,82 @@
+package stripe
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testSecret = "whsec_test"
+
+func TestVerifySignature(t *testing.T) {
+	fixtures, err := filepath.Glob("testdata/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("no fixtures in testdata")
+	}
+
+	now := time.Unix(1760000000, 0)
+	for _, fixture := range fixtures {
+		payload, err := os.ReadFile(fixture)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ts := fmt.Sprintf("t=%d", now.Unix())
+		sig := "v1=" + hex.EncodeToString(computeSignature(payload, testSecret, now.Unix()))
+		otherSig := "v1=" + hex.EncodeToString(computeSignature(payload, "whsec_other", now.Unix()))
+		signed := SignatureHeader(payload, testSecret, now)
+		tampered := append(append([]byte{}, payload...), ' ')
+
+		tests := []struct {
+			name    string
+			payload []byte
+			header  string
+			secret  string
+			now     time.Time
+			want    error
+		}{
+			{name: "valid", payload: payload, header: signed, secret: testSecret, now: now},
+			{name: "within tolerance", payload: payload, header: signed, secret: testSecret, now: now.Add(DefaultTolerance)},
+			{name: "tampered body", payload: tampered, header: signed, secret: testSecret, now: now, want: ErrNoValidSignature},
+			{name: "wrong secret", payload: payload, header: signed, secret: "whsec_other", now: now, want: ErrNoValidSignature},
+			{name: "too old", payload: payload, header: signed, secret: testSecret, now: now.Add(DefaultTolerance + time.Second), want: ErrTooOld},
+			{name: "from the future", payload: payload, header: signed, secret: testSecret, now: now.Add(-DefaultTolerance - time.Second), want: ErrTooOld},
+			{name: "several signatures", payload: payload, header: ts + "," + otherSig + "," + sig, secret: testSecret, now: now},
+			{name: "several signatures none valid", payload: payload, header: ts + "," + otherSig + ",v1=00ff", secret: testSecret, now: now, want: ErrNoValidSignature},
+			{name: "empty header", payload: payload, header: "", secret: testSecret, now: now, want: ErrInvalidHeader},
+			{name: "no timestamp", payload: payload, header: sig, secret: testSecret, now: now, want: ErrInvalidHeader},
+			{name: "bad timestamp", payload: payload, header: "t=soon," + sig, secret: testSecret, now: now, want: ErrInvalidHeader},
+			{name: "no signature", payload: payload, header: ts, secret: testSecret, now: now, want: ErrInvalidHeader},
+			{name: "only v0 signature", payload: payload, header: ts + ",v0=abcd", secret: testSecret, now: now, want: ErrInvalidHeader},
+		}
+		for _, tt := range tests {
+			t.Run(filepath.Base(fixture)+"/"+tt.name, func(t *testing.T) {
+				err := VerifySignature(tt.payload, tt.header, tt.secret, DefaultTolerance, tt.now)
+				if !errors.Is(err, tt.want) {
+					t.Errorf("VerifySignature() = %v, want %v", err, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestConstructEvent(t *testing.T) {
+	payload, err := os.ReadFile("testdata/payment_intent_succeeded.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := ConstructEvent(payload, SignatureHeader(payload, testSecret, time.Now()), testSecret, DefaultTolerance)
+	if err != nil {
+		t.Fatalf("ConstructEvent() returned error: %v", err)
+	}
+	if event.Type != EventPaymentIntentSucceeded {
+		t.Errorf("ConstructEvent() type = %q, want %q", event.Type, EventPaymentIntentSucceeded)
+	}
+}
@@ -4,6 +4,7 @@ package router
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gbart/fcabl-api/internal/auth"
 	"github.com/gbart/fcabl-api/internal/handlers"
@@ -12,14 +13,15 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(h *handlers.Handler, frontendURL string, jwtService *auth.JWTService) *gin.Engine {
+func SetupRouter(h *handlers.Handler, frontendURL string, jwtService *auth.JWTService, sessions middleware.SessionStore, idempotencyKeys middleware.IdempotencyStore, idempotencyTTL time.Duration) *gin.Engine {
 	r := gin.Default()
 
 	// CORS configuration for HTTP-only cookies
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173", "http://192.168.1.130:5173", "http://192.168.1.21:5173", "http://192.168.1.137:5173"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", middleware.IdempotencyKeyHeader, "Last-Event-ID"},
+		ExposeHeaders:    []string{middleware.IdempotentReplayedHeader},
 		AllowCredentials: true, // Required for cookies
 		MaxAge:           12 * 3600,
 	}))
@@ -37,11 +39,21 @@ func SetupRouter(h *handlers.Handler, frontendURL string, jwtService *auth.JWTSe
 		authGroup.POST("/register", h.Register)
 		authGroup.POST("/login", h.Login)
 		authGroup.POST("/verify", h.VerifyToken)
+		authGroup.POST("/refresh", h.RefreshSession)
 		authGroup.POST("/logout", h.Logout)
 		authGroup.POST("/password-reset/request", h.RequestPasswordReset)
 		authGroup.POST("/password-reset/confirm", h.ResetPassword)
 	}
 
+	// Webhooks (public, verified by signature)
+	r.POST("/api/webhook/stripe", h.StripeWebhook)
+
+	// Public season/division routes
+	r.GET("/api/season/list", h.ListSeasons)
+	r.GET("/api/season/current", h.GetCurrentSeason)
+	r.GET("/api/season", h.GetSeason)
+	r.GET("/api/division/list", h.ListDivisions)
+
 	// Public game/team routes
 	r.GET("/api/team/list", h.ListTeams)
 	r.GET("/api/team/standings", h.GetTeamStandings)
@@ -54,66 +66,197 @@ func SetupRouter(h *handlers.Handler, frontendURL string, jwtService *auth.JWTSe
 	r.GET("/api/game/list-with-teams", h.ListGamesWithTeams)
 	r.GET("/api/game", h.GetGame)
 	r.GET("/api/team/stats", h.GetTeamStats)
+	r.GET("/api/player/stats", h.GetPlayerGameStats)
+	r.GET("/api/player/stats/season", h.GetPlayerSeasonStats)
+	r.GET("/api/player/stats/career", h.GetPlayerCareerStats)
+	r.GET("/api/stats/leaders", h.GetStatLeaders)
 	r.GET("/api/team/players", h.GetTeamWithPlayers)
 	r.GET("/api/team/players/list", h.ListTeamsWithPlayers)
 	r.GET("/api/game/with-teams", h.GetGameWithTeams)
 	r.GET("/api/game/team", h.ListGamesByTeam)
+	r.GET("/api/game/details", h.GetGameBoxScore)
+	r.GET("/api/game/events", h.ListGameEvents)
+	r.GET("/api/game/stream", h.StreamGame)
+	r.GET("/api/game/stream/day", h.StreamGameDay)
+	r.GET("/api/bracket/list", h.ListBrackets)
+	r.GET("/api/bracket", h.GetBracket)
 
 	// Protected routes (require authentication)
 	protected := r.Group("/api")
-	protected.Use(middleware.AuthMiddleware(jwtService))
+	protected.Use(middleware.AuthMiddleware(jwtService, sessions))
 	{
+		// Retried payment and play-by-play requests with the same Idempotency-Key
+		// run once
+		idempotent := middleware.Idempotency(idempotencyKeys, idempotencyTTL)
+
 		// User routes
 		protected.GET("/user", h.GetUser)
 
-		// Admin-only routes
-		admin := protected.Group("")
-		admin.Use(middleware.AdminMiddleware())
+		// Session routes
+		protected.GET("/auth/sessions", h.ListSessions)
+		protected.DELETE("/auth/sessions/:id", h.RevokeSession)
+		protected.POST("/auth/logout-all", h.LogoutAll)
+
+		// Registration fee checkout for the signed in player
+		protected.POST("/player/checkout", idempotent, h.CreateCheckoutSession)
+		protected.GET("/player/fees", h.GetMyRegistrationFees)
+		protected.POST("/player/promo-code", h.ApplyPromoCode)
+		protected.GET("/player/payment-plans", h.GetMyPaymentPlans)
+		protected.GET("/player/payments", h.GetMyPayments)
+		protected.GET("/player/payments/receipt", h.GetMyPaymentReceipt)
+		protected.GET("/player/invoice", h.GetMyInvoice)
+		protected.PATCH("/user/payment-reminders", h.SetPaymentRemindersOptOut)
+
+		// Roles and permissions for the signed in user
+		protected.GET("/user/access", h.GetUserAccess)
+
+		// Each group below declares the permission it needs. Captains,
+		// scorekeepers and referees have scoped permissions, the handlers
+		// check they captain the team or are assigned to the game.
+
+		// User management
+		users := protected.Group("")
+		users.Use(middleware.RequirePermission(auth.PermManageUsers))
 		{
-			// User management
-			admin.GET("/user/list", h.ListUsers)
-			admin.POST("/user", h.CreateUser)
-			admin.PUT("/user", h.UpdateUser)
-			admin.DELETE("/user/:id", h.DeleteUser)
+			users.GET("/user/list", h.ListUsers)
+			users.POST("/user", h.CreateUser)
+			users.PUT("/user", h.UpdateUser)
+			users.DELETE("/user/:id", h.DeleteUser)
+			users.GET("/user/sessions", h.ListUserSessions)
+			users.DELETE("/user/sessions/:userId", h.RevokeUserSessions)
+		}
+
+		// League management
+		league := protected.Group("")
+		league.Use(middleware.RequirePermission(auth.PermManageLeague))
+		{
+			// Season and division management
+			league.POST("/season", h.CreateSeason)
+			league.PUT("/season", h.UpdateSeason)
+			league.PATCH("/season/current", h.SetCurrentSeason)
+			league.DELETE("/season/:id", h.DeleteSeason)
+			league.POST("/division", h.CreateDivision)
+			league.PUT("/division", h.UpdateDivision)
+			league.DELETE("/division/:id", h.DeleteDivision)
 
 			// Team management
-			admin.POST("/team", h.CreateTeam)
-			admin.PUT("/team", h.UpdateTeam)
-			admin.DELETE("/team/:id", h.DeleteTeam)
-
-			// Player management
-			admin.GET("/player/list", h.ListPlayers)
-			admin.GET("/player/active", h.ListActivePlayers)
-			admin.GET("/player/team", h.ListPlayersByTeam)
-			admin.GET("/player/free-agents", h.ListFreeAgents)
-			admin.GET("/player/with-user", h.GetPlayerWithUser)
-			admin.GET("/player/with-team", h.GetPlayerWithTeam)
-			admin.GET("/player/list-with-users", h.ListPlayersWithUsers)
-			admin.GET("/player", h.GetPlayer)
-			admin.POST("/player", h.CreatePlayer)
-			admin.PUT("/player", h.UpdatePlayer)
-			admin.PATCH("/player/team", h.UpdatePlayerTeam)
-			admin.PATCH("/player/registration", h.UpdatePlayerRegistrationStatus)
-			admin.DELETE("/player/:id", h.DeletePlayer)
+			league.POST("/team", h.CreateTeam)
+			league.PUT("/team", h.UpdateTeam)
+			league.DELETE("/team/:id", h.DeleteTeam)
+			league.POST("/team/standings/recompute", h.RecomputeTeamStandings)
+			league.GET("/team/captain/list", h.ListTeamCaptains)
+			league.POST("/team/captain", h.AddTeamCaptain)
+			league.DELETE("/team/captain/:teamId/:userId", h.RemoveTeamCaptain)
 
 			// Game management
-			admin.POST("/game", h.CreateGame)
-			admin.PUT("/game", h.UpdateGame)
-			admin.PUT("/game/status", h.UpdateGameScoreAndStatus)
-			admin.PATCH("/game/time", h.UpdateGameTime)
-			admin.DELETE("/game/:id", h.DeleteGame)
-
-			// Payment management
-			admin.GET("/payment/list", h.ListPayments)
-			admin.GET("/payment/player", h.ListPaymentsByPlayer)
-			admin.GET("/payment/status-filter", h.ListPaymentsByStatus)
-			admin.GET("/payment/with-player", h.GetPaymentWithPlayer)
-			admin.GET("/payment/list-with-players", h.ListPaymentsWithPlayerInfo)
-			admin.GET("/payment/summary", h.GetPlayerPaymentSummary)
-			admin.GET("/payment", h.GetPayment)
-			admin.POST("/payment", h.CreatePayment)
-			admin.PATCH("/payment/status", h.UpdatePaymentStatus)
-			admin.DELETE("/payment/:id", h.DeletePayment)
+			league.POST("/game", h.CreateGame)
+			league.PUT("/game", h.UpdateGame)
+			league.PATCH("/game/time", h.UpdateGameTime)
+			league.DELETE("/game/:id", h.DeleteGame)
+			league.POST("/game/schedule/generate", h.GenerateSchedule)
+			league.GET("/game/assignment/list", h.ListGameAssignments)
+			league.POST("/game/assignment", h.AssignUserToGame)
+			league.DELETE("/game/assignment/:gameId/:userId", h.UnassignUserFromGame)
+
+			// Playoff bracket management
+			league.POST("/bracket", h.CreateBracket)
+			league.POST("/bracket/match/schedule", h.ScheduleBracketMatch)
+			league.DELETE("/bracket/:id", h.DeleteBracket)
+		}
+
+		// Player lookups
+		players := protected.Group("")
+		players.Use(middleware.RequirePermission(auth.PermViewPlayers))
+		{
+			players.GET("/player/list", h.ListPlayers)
+			players.GET("/player/active", h.ListActivePlayers)
+			players.GET("/player/with-user", h.GetPlayerWithUser)
+			players.GET("/player/with-team", h.GetPlayerWithTeam)
+			players.GET("/player/list-with-users", h.ListPlayersWithUsers)
+			players.GET("/player", h.GetPlayer)
+		}
+
+		// Player management
+		playerAdmin := protected.Group("")
+		playerAdmin.Use(middleware.RequirePermission(auth.PermManagePlayers))
+		{
+			playerAdmin.POST("/player", h.CreatePlayer)
+			playerAdmin.PUT("/player", h.UpdatePlayer)
+			playerAdmin.DELETE("/player/:id", h.DeletePlayer)
+		}
+
+		// Team rosters, captains are limited to their own team
+		roster := protected.Group("")
+		roster.Use(middleware.RequirePermission(auth.PermManageRoster))
+		{
+			roster.GET("/player/team", h.ListPlayersByTeam)
+			roster.GET("/player/free-agents", h.ListFreeAgents)
+			roster.PATCH("/player/team", h.UpdatePlayerTeam)
+		}
+
+		// Game results, scorekeepers and referees are limited to assigned games
+		results := protected.Group("")
+		results.Use(middleware.RequirePermission(auth.PermRecordResults))
+		{
+			results.PUT("/game/status", h.UpdateGameScoreAndStatus)
+		}
+
+		// Box score management, scorekeepers are limited to assigned games
+		boxScores := protected.Group("")
+		boxScores.Use(middleware.RequirePermission(auth.PermRecordBoxScore))
+		{
+			boxScores.POST("/game/details", h.CreateGameDetails)
+			boxScores.PUT("/game/details", h.UpsertGameBoxScore)
+			boxScores.PATCH("/game/details/score", h.UpdateGameDetailsScore)
+			boxScores.DELETE("/game/details/:gameId/:playerId", h.DeleteGameDetails)
+			boxScores.POST("/game/event", idempotent, h.RecordGameEvent)
+			boxScores.POST("/game/event/undo", idempotent, h.UndoGameEvent)
+		}
+
+		// Payment management
+		payments := protected.Group("")
+		payments.Use(middleware.RequirePermission(auth.PermManagePayments), idempotent)
+		{
+			payments.GET("/payment/list", h.ListPayments)
+			payments.GET("/payment/player", h.ListPaymentsByPlayer)
+			payments.GET("/payment/status-filter", h.ListPaymentsByStatus)
+			payments.GET("/payment/with-player", h.GetPaymentWithPlayer)
+			payments.GET("/payment/list-with-players", h.ListPaymentsWithPlayerInfo)
+			payments.GET("/payment/export/payments", h.ExportPayments)
+			payments.GET("/payment/export/refunds", h.ExportRefunds)
+			payments.GET("/payment/export/balances", h.ExportBalances)
+			payments.GET("/payment/summary", h.GetPlayerPaymentSummary)
+			payments.GET("/payment", h.GetPayment)
+			payments.GET("/payment/receipt", h.GetPaymentReceipt)
+			payments.GET("/payment/invoice", h.GetPlayerInvoice)
+			payments.POST("/payment", h.CreatePayment)
+			payments.PATCH("/payment/status", h.UpdatePaymentStatus)
+			payments.DELETE("/payment/:id", h.DeletePayment)
+			payments.POST("/payment/refund", h.CreateRefund)
+			payments.GET("/payment/refunds", h.ListRefunds)
+			payments.GET("/payment/plan/list", h.ListPaymentPlans)
+			payments.GET("/payment/plan/player", h.ListPaymentPlansByPlayer)
+			payments.GET("/payment/plan", h.GetPaymentPlan)
+			payments.POST("/payment/plan", h.CreatePaymentPlan)
+			payments.PATCH("/payment/plan/cancel", h.CancelPaymentPlan)
+			payments.GET("/payment/fees", h.GetRegistrationFees)
+			payments.POST("/payment/fees/assess", h.AssessRegistrationFee)
+			payments.GET("/fee-schedule", h.GetFeeSchedule)
+			payments.PUT("/fee-schedule", h.UpsertFeeSchedule)
+			payments.DELETE("/fee-schedule/:seasonId", h.DeleteFeeSchedule)
+			payments.GET("/promo-code/list", h.ListPromoCodes)
+			payments.POST("/promo-code", h.CreatePromoCode)
+			payments.PUT("/promo-code", h.UpdatePromoCode)
+			payments.DELETE("/promo-code/:id", h.DeletePromoCode)
+			payments.GET("/payment/reminders", h.ListPaymentReminders)
+			payments.GET("/payment/ledger", h.GetPlayerLedger)
+			payments.GET("/payment/ledger/list", h.ListLedgerTransactions)
+			payments.GET("/payment/ledger/trial-balance", h.GetTrialBalance)
+			payments.POST("/payment/ledger/write-off", h.WriteOff)
+			payments.GET("/payment/webhook/list", h.ListWebhookEvents)
+			payments.POST("/payment/reconcile", h.ReconcileRegistrations)
+			payments.GET("/payment/reconcile/report", h.ListRegistrationMismatches)
+			payments.POST("/payment/reconcile/provider", h.ReconcileProviderPayments)
 		}
 	}
 